@@ -4,13 +4,18 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"k8s_dashboard/internal/server"
 )
 
 func main() {
 	addr := defaultAddr()
-	srv := server.New()
+	var opts []server.Option
+	if v := os.Getenv("DASHBOARD_CORS_ORIGINS"); v != "" {
+		opts = append(opts, server.WithCORSOrigins(strings.Split(v, ",")...))
+	}
+	srv := server.New(opts...)
 
 	log.Printf("starting dashboard server on %s", addr)
 	if err := http.ListenAndServe(addr, srv); err != nil {
@@ -0,0 +1,36 @@
+// Package age renders resource ages the way the store summaries report them.
+package age
+
+import (
+	"fmt"
+	"time"
+)
+
+// Format renders d as "XdYh", "XhYm" or "XmYs", clamping negative ages to
+// zero.
+func Format(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		secs := int(d/time.Second) % 60
+		return fmt.Sprintf("%dm%ds", minutes, secs)
+	}
+}
+
+// Seconds is the whole-second form of an age, clamped at zero like Format.
+func Seconds(d time.Duration) int64 {
+	return max(int64(d/time.Second), 0)
+}
@@ -0,0 +1,30 @@
+package age
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAndSeconds(t *testing.T) {
+	tests := []struct {
+		d       time.Duration
+		text    string
+		seconds int64
+	}{
+		{d: 9 * time.Minute, text: "9m0s", seconds: 540},
+		{d: 90 * time.Second, text: "1m30s", seconds: 90},
+		{d: 3*time.Hour + 5*time.Minute, text: "3h5m", seconds: 11100},
+		{d: 50 * time.Hour, text: "2d2h", seconds: 180000},
+		{d: 1500 * time.Millisecond, text: "0m1s", seconds: 1},
+		{d: -time.Minute, text: "0m0s", seconds: 0},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.d); got != tt.text {
+			t.Errorf("Format(%v) = %q, want %q", tt.d, got, tt.text)
+		}
+		if got := Seconds(tt.d); got != tt.seconds {
+			t.Errorf("Seconds(%v) = %d, want %d", tt.d, got, tt.seconds)
+		}
+	}
+}
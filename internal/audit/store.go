@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRetention caps the number of audit entries kept in memory.
+const DefaultRetention = 1000
+
+// Entry records a single mutating API call.
+type Entry struct {
+	Timestamp string `json:"timestamp"`
+	Action    string `json:"action"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Before and After optionally capture the fields the call changed.
+	Before map[string]any `json:"before,omitempty"`
+	After  map[string]any `json:"after,omitempty"`
+}
+
+// Store keeps a bounded, in-memory audit trail.
+type Store struct {
+	mu        sync.RWMutex
+	retention int
+	items     []Entry
+}
+
+// NewStore returns an empty audit store keeping at most retention entries.
+// Non-positive values fall back to DefaultRetention.
+func NewStore(retention int) *Store {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Store{retention: retention}
+}
+
+// Record appends an entry, dropping the oldest ones beyond the retention.
+func (s *Store) Record(entry Entry, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.Timestamp == "" {
+		entry.Timestamp = now.UTC().Format(time.RFC3339)
+	}
+	s.items = append(s.items, entry)
+	if overflow := len(s.items) - s.retention; overflow > 0 {
+		s.items = append([]Entry(nil), s.items[overflow:]...)
+	}
+}
+
+// List returns audit entries newest first.
+func (s *Store) List() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Entry, 0, len(s.items))
+	for i := len(s.items) - 1; i >= 0; i-- {
+		out = append(out, s.items[i])
+	}
+	return out
+}
+
+// ListResource returns up to limit entries for one resource, newest first.
+// Kind matches case-insensitively; an empty namespace matches any.
+func (s *Store) ListResource(kind, namespace, name string, limit int) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Entry, 0)
+	for i := len(s.items) - 1; i >= 0 && len(out) < limit; i-- {
+		entry := s.items[i]
+		if !strings.EqualFold(entry.Kind, kind) || entry.Name != name {
+			continue
+		}
+		if namespace != "" && entry.Namespace != namespace {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// Clear removes all recorded entries.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = nil
+}
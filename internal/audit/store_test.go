@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStoreRetention(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(3)
+
+	for i := 0; i < 5; i++ {
+		store.Record(Entry{Action: "create", Kind: "namespace", Name: fmt.Sprintf("ns-%d", i)}, now)
+	}
+
+	entries := store.List()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	if entries[0].Name != "ns-4" || entries[2].Name != "ns-2" {
+		t.Fatalf("expected newest entries kept newest first, got %+v", entries)
+	}
+
+	if entries[0].Timestamp != "2024-07-12T12:00:00Z" {
+		t.Fatalf("unexpected timestamp %s", entries[0].Timestamp)
+	}
+
+	store.Clear()
+	if len(store.List()) != 0 {
+		t.Fatalf("expected empty store after clear")
+	}
+}
+
+func TestListResource(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(10)
+
+	store.Record(Entry{Action: "scale", Kind: "deployment", Namespace: "default", Name: "frontend"}, now)
+	store.Record(Entry{Action: "scale", Kind: "deployment", Namespace: "default", Name: "backend"}, now)
+	store.Record(Entry{Action: "restart", Kind: "deployment", Namespace: "default", Name: "frontend"}, now)
+	store.Record(Entry{Action: "delete", Kind: "service", Namespace: "default", Name: "frontend"}, now)
+
+	entries := store.ListResource("Deployment", "", "frontend", 10)
+	if len(entries) != 2 || entries[0].Action != "restart" || entries[1].Action != "scale" {
+		t.Fatalf("expected frontend deployment entries newest first, got %+v", entries)
+	}
+	if got := store.ListResource("deployment", "default", "frontend", 1); len(got) != 1 || got[0].Action != "restart" {
+		t.Fatalf("expected limit to keep the newest entry, got %+v", got)
+	}
+	if got := store.ListResource("deployment", "prod", "frontend", 10); len(got) != 0 {
+		t.Fatalf("expected namespace to narrow results, got %+v", got)
+	}
+}
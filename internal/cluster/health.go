@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNegativeWeight indicates a health weight below zero.
+var ErrNegativeWeight = errors.New("health weights must be non-negative")
+
+// HealthConfig holds how many points each unhealthy resource deducts from
+// the 100-point cluster health score.
+type HealthConfig struct {
+	NotReadyNode        int `json:"notReadyNode"`
+	FailedPod           int `json:"failedPod"`
+	PendingPod          int `json:"pendingPod"`
+	UnhealthyDeployment int `json:"unhealthyDeployment"`
+}
+
+// DefaultHealthConfig returns the weights used unless overridden.
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		NotReadyNode:        15,
+		FailedPod:           5,
+		PendingPod:          2,
+		UnhealthyDeployment: 10,
+	}
+}
+
+// Validate reports ErrNegativeWeight if any weight is below zero.
+func (c HealthConfig) Validate() error {
+	if c.NotReadyNode < 0 || c.FailedPod < 0 || c.PendingPod < 0 || c.UnhealthyDeployment < 0 {
+		return ErrNegativeWeight
+	}
+	return nil
+}
+
+// HealthInputs counts the unhealthy resources fed into the score.
+type HealthInputs struct {
+	NotReadyNodes        int `json:"notReadyNodes"`
+	FailedPods           int `json:"failedPods"`
+	PendingPods          int `json:"pendingPods"`
+	UnhealthyDeployments int `json:"unhealthyDeployments"`
+}
+
+// Health statuses, from best to worst.
+const (
+	StatusHealthy  = "Healthy"
+	StatusDegraded = "Degraded"
+	StatusCritical = "Critical"
+)
+
+// HealthScore is the computed score along with the inputs and weights that
+// produced it.
+type HealthScore struct {
+	Score   int          `json:"score"`
+	Status  string       `json:"status"`
+	Inputs  HealthInputs `json:"inputs"`
+	Weights HealthConfig `json:"weights"`
+}
+
+// Score deducts the weighted unhealthy counts from 100, never going below 0,
+// and maps the result to a status with healthStatus.
+func Score(in HealthInputs, cfg HealthConfig) HealthScore {
+	deducted := in.NotReadyNodes*cfg.NotReadyNode +
+		in.FailedPods*cfg.FailedPod +
+		in.PendingPods*cfg.PendingPod +
+		in.UnhealthyDeployments*cfg.UnhealthyDeployment
+	score := max(100-deducted, 0)
+
+	return HealthScore{
+		Score:   score,
+		Status:  healthStatus(score),
+		Inputs:  in,
+		Weights: cfg,
+	}
+}
+
+// healthStatus maps a 0-100 score to a status: 80 or more is Healthy, 50 or
+// more is Degraded and anything lower is Critical.
+func healthStatus(score int) string {
+	switch {
+	case score >= 80:
+		return StatusHealthy
+	case score >= 50:
+		return StatusDegraded
+	default:
+		return StatusCritical
+	}
+}
+
+// HealthSignals are the raw observations ComputeHealth scores.
+type HealthSignals struct {
+	TotalNodes    int `json:"totalNodes"`
+	ReadyNodes    int `json:"readyNodes"`
+	PendingPods   int `json:"pendingPods"`
+	FailedPods    int `json:"failedPods"`
+	WarningEvents int `json:"warningEvents"`
+}
+
+// OverviewHealth is the single health indicator shown on the overview.
+type OverviewHealth struct {
+	Score   int           `json:"score"`
+	Status  string        `json:"status"`
+	Signals HealthSignals `json:"signals"`
+}
+
+// ComputeHealth starts at 100 and deducts:
+//
+//   - up to 50 points in proportion to the share of nodes that are not
+//     Ready, or all 50 when there are no nodes;
+//   - 10 points per failed pod, at most 30;
+//   - 5 points per pending pod, at most 20;
+//   - 2 points per Warning event occurrence, at most 10.
+//
+// The score is rounded and never drops below 0, and maps to a status the
+// same way Score does.
+func ComputeHealth(sig HealthSignals) OverviewHealth {
+	nodePenalty := 50.0
+	if sig.TotalNodes > 0 {
+		notReady := max(sig.TotalNodes-sig.ReadyNodes, 0)
+		nodePenalty = 50 * float64(notReady) / float64(sig.TotalNodes)
+	}
+
+	deducted := nodePenalty +
+		float64(min(sig.FailedPods*10, 30)) +
+		float64(min(sig.PendingPods*5, 20)) +
+		float64(min(sig.WarningEvents*2, 10))
+	score := max(int(math.Round(100-deducted)), 0)
+
+	return OverviewHealth{Score: score, Status: healthStatus(score), Signals: sig}
+}
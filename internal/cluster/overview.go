@@ -1,12 +1,28 @@
 package cluster
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"k8s_dashboard/internal/deploy"
+	"k8s_dashboard/internal/logs"
+	"k8s_dashboard/internal/namespace"
+	"k8s_dashboard/internal/node"
+	"k8s_dashboard/internal/pod"
+	"k8s_dashboard/internal/scope"
+	"k8s_dashboard/internal/service"
+)
 
 // ClusterOverview aggregates summary data shown on the dashboard.
 type ClusterOverview struct {
 	Info          ClusterInfo   `json:"info"`
 	ResourceUsage ResourceUsage `json:"resourceUsage"`
 	RecentEvents  []Event       `json:"recentEvents"`
+
+	// Health is only computed by BuildOverview; MockOverview leaves it zero.
+	Health OverviewHealth `json:"health"`
+	// Degraded is set by the server when Health falls below its threshold.
+	Degraded bool `json:"degraded"`
 }
 
 // ClusterInfo describes the basic cluster metadata displayed in the UI.
@@ -41,6 +57,7 @@ type Event struct {
 	Reason    string `json:"reason"`
 	Message   string `json:"message"`
 	Object    string `json:"object"`
+	Namespace string `json:"namespace,omitempty"`
 	Timestamp string `json:"timestamp"`
 }
 
@@ -98,3 +115,178 @@ func MockOverview(now time.Time) ClusterOverview {
 		},
 	}
 }
+
+// OverviewSources are the stores BuildOverview and NamespaceOverview read
+// live counts from. VisibleNamespaces is the allow-list BuildOverview
+// applies with scope.Allows; empty allows every namespace.
+type OverviewSources struct {
+	Namespaces        *namespace.Store
+	Nodes             *node.Store
+	Pods              *pod.Store
+	Deployments       *deploy.Store
+	Services          *service.Store
+	Events            *logs.Store
+	VisibleNamespaces []string
+}
+
+// BuildOverview starts from MockOverview and replaces the node, namespace,
+// pod, resource and event figures with values computed from src, so the
+// overview agrees with the list endpoints. Namespaces, pods and events are
+// limited to src.VisibleNamespaces. CPU and memory sum every node's usage and
+// capacity, and Health scores the live figures with ComputeHealth. The
+// cluster name and version stay mocked.
+func BuildOverview(now time.Time, src OverviewSources) ClusterOverview {
+	overview := MockOverview(now)
+	info := &overview.Info
+
+	nodes := src.Nodes.List(now)
+	info.NodeCount = len(nodes)
+	info.TotalPodCapacity = 0
+	signals := HealthSignals{TotalNodes: len(nodes)}
+	for _, n := range nodes {
+		if ready, _, _ := strings.Cut(n.Status, ","); ready == "Ready" {
+			signals.ReadyNodes++
+		}
+		info.TotalPodCapacity += n.Pods.Capacity
+	}
+	overview.ResourceUsage = NodeUsage(now, nodes)
+
+	info.NamespaceCount = 0
+	for _, ns := range src.Namespaces.List(now) {
+		if scope.Allows(src.VisibleNamespaces, ns.Name) {
+			info.NamespaceCount++
+		}
+	}
+
+	info.RunningPodCount, info.PendingPodCount, info.FailedPodCount = 0, 0, 0
+	for _, p := range src.Pods.ListFiltered(now, pod.PodFilter{Namespaces: src.VisibleNamespaces}) {
+		switch p.Status {
+		case "Running":
+			info.RunningPodCount++
+		case "Pending":
+			info.PendingPodCount++
+		case "Failed":
+			info.FailedPodCount++
+		}
+	}
+
+	signals.PendingPods, signals.FailedPods = info.PendingPodCount, info.FailedPodCount
+	overview.RecentEvents = make([]Event, 0, recentEventLimit)
+	for _, ev := range src.Events.ListEvents(now) {
+		if !scope.Allows(src.VisibleNamespaces, ev.Namespace) {
+			continue
+		}
+		if strings.EqualFold(ev.Type, "Warning") {
+			signals.WarningEvents += ev.Count
+		}
+		if len(overview.RecentEvents) < recentEventLimit {
+			overview.RecentEvents = append(overview.RecentEvents, overviewEvent(ev))
+		}
+	}
+	overview.Health = ComputeHealth(signals)
+
+	return overview
+}
+
+// NodeUsage sums CPU and memory usage and capacity across nodes, stamped
+// with now. It is the live baseline behind both BuildOverview and
+// UsageSeries.
+func NodeUsage(now time.Time, nodes []node.NodeSummary) ResourceUsage {
+	timestamp := now.UTC().Format(time.RFC3339)
+	usage := ResourceUsage{
+		CPU:    UsageMetric{Unit: "cores", Timestamp: timestamp},
+		Memory: UsageMetric{Unit: "GiB", Timestamp: timestamp},
+	}
+	for _, n := range nodes {
+		usage.CPU.Used += n.CPU.Used
+		usage.CPU.Total += n.CPU.Capacity
+		usage.Memory.Used += n.Memory.Used
+		usage.Memory.Total += n.Memory.Capacity
+	}
+	return usage
+}
+
+// recentEventLimit caps the events returned by BuildOverview and
+// NamespaceOverview.
+const recentEventLimit = 10
+
+// overviewEvent converts a stored event into its overview entry.
+func overviewEvent(ev logs.Event) Event {
+	return Event{
+		Type:      ev.Type,
+		Reason:    ev.Reason,
+		Message:   ev.Message,
+		Object:    strings.ToLower(ev.Kind) + "/" + ev.Name,
+		Namespace: ev.Namespace,
+		Timestamp: ev.Timestamp,
+	}
+}
+
+// NamespaceOverviewData is the overview scoped to a single namespace. The
+// status maps count pods, deployments and services by status; the pod
+// counts break out the phases the dashboard cards show.
+type NamespaceOverviewData struct {
+	Namespace       string         `json:"namespace"`
+	RunningPodCount int            `json:"runningPodCount"`
+	PendingPodCount int            `json:"pendingPodCount"`
+	FailedPodCount  int            `json:"failedPodCount"`
+	DeploymentCount int            `json:"deploymentCount"`
+	ServiceCount    int            `json:"serviceCount"`
+	Pods            map[string]int `json:"pods"`
+	Deployments     map[string]int `json:"deployments"`
+	Services        map[string]int `json:"services"`
+	RecentEvents    []Event        `json:"recentEvents"`
+}
+
+// EmptyNamespaceOverview is the zeroed overview of ns, with non-nil maps and
+// events so it renders as an empty state.
+func EmptyNamespaceOverview(ns string) NamespaceOverviewData {
+	return NamespaceOverviewData{
+		Namespace:    ns,
+		Pods:         make(map[string]int),
+		Deployments:  make(map[string]int),
+		Services:     make(map[string]int),
+		RecentEvents: []Event{},
+	}
+}
+
+// NamespaceOverview counts the pods, deployments and services in ns and
+// collects its most recent events. A namespace with no resources, including
+// one that does not exist, yields a zeroed overview rather than an error so
+// callers can render an empty state.
+func NamespaceOverview(now time.Time, ns string, src OverviewSources) NamespaceOverviewData {
+	out := EmptyNamespaceOverview(ns)
+
+	for _, p := range src.Pods.ListFiltered(now, pod.PodFilter{Namespace: ns}) {
+		out.Pods[p.Status]++
+		switch p.Status {
+		case "Running":
+			out.RunningPodCount++
+		case "Pending":
+			out.PendingPodCount++
+		case "Failed":
+			out.FailedPodCount++
+		}
+	}
+	for _, d := range src.Deployments.List(now) {
+		if d.Namespace == ns {
+			out.Deployments[d.Status]++
+			out.DeploymentCount++
+		}
+	}
+	for _, svc := range src.Services.List(now) {
+		if svc.Namespace == ns {
+			out.Services[svc.Status]++
+			out.ServiceCount++
+		}
+	}
+
+	for _, ev := range src.Events.ListEventsFiltered(now, logs.EventFilter{Namespace: ns}) {
+		if len(out.RecentEvents) == recentEventLimit {
+			break
+		}
+		out.RecentEvents = append(out.RecentEvents, overviewEvent(ev))
+	}
+
+	return out
+}
@@ -3,6 +3,13 @@ package cluster
 import (
 	"testing"
 	"time"
+
+	"k8s_dashboard/internal/deploy"
+	"k8s_dashboard/internal/logs"
+	"k8s_dashboard/internal/namespace"
+	"k8s_dashboard/internal/node"
+	"k8s_dashboard/internal/pod"
+	"k8s_dashboard/internal/service"
 )
 
 func TestMockOverview(t *testing.T) {
@@ -30,3 +37,213 @@ func TestMockOverview(t *testing.T) {
 		t.Errorf("event timestamp mismatch, got %s", overview.RecentEvents[0].Timestamp)
 	}
 }
+
+func TestBuildOverview(t *testing.T) {
+	now := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	src := OverviewSources{
+		Namespaces: namespace.NewStore(now),
+		Nodes:      node.NewStore(now),
+		Pods:       pod.NewStore(now),
+		Events:     logs.NewStore(now),
+	}
+	overview := BuildOverview(now, src)
+	if overview.Health.Status != StatusDegraded {
+		t.Fatalf("expected seeded cluster to be degraded, got %+v", overview.Health)
+	}
+	if overview.Health.Signals.TotalNodes != 3 || overview.Health.Signals.ReadyNodes != 2 {
+		t.Fatalf("expected live node signals, got %+v", overview.Health.Signals)
+	}
+
+	nodes := src.Nodes.List(now)
+	if overview.Info.NodeCount != len(nodes) {
+		t.Fatalf("expected %d nodes, got %d", len(nodes), overview.Info.NodeCount)
+	}
+	if overview.Info.NamespaceCount != len(src.Namespaces.List(now)) {
+		t.Fatalf("unexpected namespace count %d", overview.Info.NamespaceCount)
+	}
+
+	var running, pending, failed int
+	for _, p := range src.Pods.List(now) {
+		switch p.Status {
+		case "Running":
+			running++
+		case "Pending":
+			pending++
+		case "Failed":
+			failed++
+		}
+	}
+	info := overview.Info
+	if info.RunningPodCount != running || info.PendingPodCount != pending || info.FailedPodCount != failed {
+		t.Fatalf("unexpected pod counts %+v", info)
+	}
+
+	var cpuTotal float64
+	for _, n := range nodes {
+		cpuTotal += n.CPU.Capacity
+	}
+	if overview.ResourceUsage.CPU.Total != cpuTotal {
+		t.Fatalf("expected CPU total %v, got %v", cpuTotal, overview.ResourceUsage.CPU.Total)
+	}
+
+	if _, err := src.Namespaces.Create("staging", now, nil); err != nil {
+		t.Fatalf("create namespace: %v", err)
+	}
+	if got := BuildOverview(now, src).Info.NamespaceCount; got != info.NamespaceCount+1 {
+		t.Fatalf("expected overview to follow the store, got %d namespaces", got)
+	}
+
+	events := src.Events.ListEvents(now)
+	if len(overview.RecentEvents) == 0 || overview.RecentEvents[0].Reason != events[0].Reason || overview.RecentEvents[0].Namespace != events[0].Namespace {
+		t.Fatalf("expected recent events from the store, got %+v", overview.RecentEvents)
+	}
+
+	if _, err := src.Namespaces.Create("prod", now, nil); err != nil {
+		t.Fatalf("create namespace: %v", err)
+	}
+	src.VisibleNamespaces = []string{"prod"}
+	scoped := BuildOverview(now, src)
+	var prodPods int
+	for _, p := range src.Pods.ListFiltered(now, pod.PodFilter{Namespace: "prod"}) {
+		if p.Status == "Running" || p.Status == "Pending" || p.Status == "Failed" {
+			prodPods++
+		}
+	}
+	if got := scoped.Info.RunningPodCount + scoped.Info.PendingPodCount + scoped.Info.FailedPodCount; got != prodPods {
+		t.Fatalf("expected %d prod pods, got %d", prodPods, got)
+	}
+	if scoped.Info.NamespaceCount != 1 || len(scoped.RecentEvents) == 0 {
+		t.Fatalf("expected a prod-only overview, got %+v", scoped)
+	}
+	for _, ev := range scoped.RecentEvents {
+		if ev.Namespace != "prod" {
+			t.Fatalf("unexpected event outside prod: %+v", ev)
+		}
+	}
+}
+
+func TestNamespaceOverview(t *testing.T) {
+	now := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	src := OverviewSources{
+		Pods:        pod.NewStore(now),
+		Deployments: deploy.NewStore(now),
+		Services:    service.NewStore(now),
+		Events:      logs.NewStore(now),
+	}
+
+	prod := NamespaceOverview(now, "prod", src)
+	if prod.DeploymentCount != 1 || prod.ServiceCount == 0 {
+		t.Fatalf("unexpected prod counts %+v", prod)
+	}
+	if prod.RunningPodCount+prod.PendingPodCount+prod.FailedPodCount == 0 {
+		t.Fatalf("expected prod pods, got %+v", prod)
+	}
+	if len(prod.RecentEvents) == 0 || prod.RecentEvents[0].Object == "" || prod.RecentEvents[0].Namespace != "prod" {
+		t.Fatalf("expected prod events, got %+v", prod.RecentEvents)
+	}
+	if prod.Pods["Running"] != prod.RunningPodCount || len(prod.Deployments) == 0 || len(prod.Services) == 0 {
+		t.Fatalf("expected status maps to match the counts, got %+v", prod)
+	}
+
+	empty := NamespaceOverview(now, "ghost", src)
+	if empty.Namespace != "ghost" || empty.DeploymentCount != 0 || empty.ServiceCount != 0 || empty.RunningPodCount != 0 {
+		t.Fatalf("expected zeroed overview, got %+v", empty)
+	}
+	if empty.Pods == nil || empty.Deployments == nil || empty.Services == nil {
+		t.Fatalf("expected non-nil status maps, got %+v", empty)
+	}
+	if empty.RecentEvents == nil || len(empty.RecentEvents) != 0 {
+		t.Fatalf("expected empty, non-nil events, got %#v", empty.RecentEvents)
+	}
+}
+
+func TestUsageSeries(t *testing.T) {
+	now := time.Date(2024, 7, 12, 15, 32, 10, 0, time.UTC)
+
+	base := NodeUsage(now, node.NewStore(now).List(now))
+	history, err := UsageSeries(now, base, time.Hour, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("usage series: %v", err)
+	}
+	if len(history.CPU) != 5 || len(history.Memory) != 5 {
+		t.Fatalf("expected 5 samples, got %d cpu and %d memory", len(history.CPU), len(history.Memory))
+	}
+	if history.CPU[0].Timestamp != "2024-07-12T15:30:00Z" || history.CPU[4].Timestamp != "2024-07-12T14:30:00Z" {
+		t.Fatalf("unexpected timestamps %s .. %s", history.CPU[0].Timestamp, history.CPU[4].Timestamp)
+	}
+	for i := 1; i < len(history.CPU); i++ {
+		if history.CPU[i-1].Timestamp <= history.CPU[i].Timestamp {
+			t.Fatalf("expected descending timestamps, got %+v", history.CPU)
+		}
+	}
+
+	for _, sample := range history.CPU {
+		if sample.Total != base.CPU.Total || sample.Unit != "cores" {
+			t.Fatalf("expected samples around the node baseline %+v, got %+v", base.CPU, sample)
+		}
+	}
+
+	again, _ := UsageSeries(now, base, time.Hour, 15*time.Minute)
+	if again.Memory[2] != history.Memory[2] {
+		t.Fatalf("expected deterministic samples, got %+v and %+v", again.Memory[2], history.Memory[2])
+	}
+
+	for _, bad := range [][2]time.Duration{{0, time.Minute}, {time.Hour, 0}, {time.Minute, time.Hour}, {24 * time.Hour, time.Second}} {
+		if _, err := UsageSeries(now, base, bad[0], bad[1]); err != ErrInvalidSeries {
+			t.Fatalf("expected ErrInvalidSeries for window %s step %s, got %v", bad[0], bad[1], err)
+		}
+	}
+}
+
+func TestComputeHealth(t *testing.T) {
+	cases := []struct {
+		name   string
+		sig    HealthSignals
+		score  int
+		status string
+	}{
+		{"all ready", HealthSignals{TotalNodes: 3, ReadyNodes: 3}, 100, StatusHealthy},
+		{"one not ready", HealthSignals{TotalNodes: 3, ReadyNodes: 2}, 83, StatusHealthy},
+		{"healthy boundary", HealthSignals{TotalNodes: 4, ReadyNodes: 4, PendingPods: 4}, 80, StatusHealthy},
+		{"just degraded", HealthSignals{TotalNodes: 4, ReadyNodes: 4, PendingPods: 4, WarningEvents: 1}, 78, StatusDegraded},
+		{"degraded boundary", HealthSignals{TotalNodes: 2, ReadyNodes: 1, FailedPods: 2, PendingPods: 1}, 50, StatusDegraded},
+		{"capped penalties", HealthSignals{TotalNodes: 1, ReadyNodes: 1, FailedPods: 9, PendingPods: 9, WarningEvents: 99}, 40, StatusCritical},
+		{"no nodes", HealthSignals{}, 50, StatusDegraded},
+		{"nothing ready", HealthSignals{TotalNodes: 3, FailedPods: 5, PendingPods: 5, WarningEvents: 5}, 0, StatusCritical},
+	}
+	for _, tc := range cases {
+		got := ComputeHealth(tc.sig)
+		if got.Score != tc.score || got.Status != tc.status {
+			t.Errorf("%s: expected %d %s, got %d %s", tc.name, tc.score, tc.status, got.Score, got.Status)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	in := HealthInputs{NotReadyNodes: 1, FailedPods: 2, PendingPods: 1, UnhealthyDeployments: 1}
+
+	if got := Score(in, DefaultHealthConfig()).Score; got != 63 {
+		t.Fatalf("expected default score 63, got %d", got)
+	}
+	custom := HealthConfig{NotReadyNode: 50, FailedPod: 1}
+	if got := Score(in, custom).Score; got != 48 {
+		t.Fatalf("expected custom score 48, got %d", got)
+	}
+	if got := Score(HealthInputs{NotReadyNodes: 10}, DefaultHealthConfig()).Score; got != 0 {
+		t.Fatalf("expected score to floor at 0, got %d", got)
+	}
+
+	perPod := HealthConfig{PendingPod: 1}
+	for pending, want := range map[int]string{0: StatusHealthy, 20: StatusHealthy, 21: StatusDegraded, 50: StatusDegraded, 51: StatusCritical} {
+		if got := Score(HealthInputs{PendingPods: pending}, perPod).Status; got != want {
+			t.Fatalf("score %d: expected %s, got %s", 100-pending, want, got)
+		}
+	}
+
+	if err := (HealthConfig{FailedPod: -1}).Validate(); err != ErrNegativeWeight {
+		t.Fatalf("expected ErrNegativeWeight, got %v", err)
+	}
+	if err := DefaultHealthConfig().Validate(); err != nil {
+		t.Fatalf("expected defaults to validate, got %v", err)
+	}
+}
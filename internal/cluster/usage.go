@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInvalidSeries indicates a usage window or step that cannot produce a
+// series.
+var ErrInvalidSeries = errors.New("invalid usage series window or step")
+
+// MaxUsageSamples caps how many points UsageSeries returns per resource.
+const MaxUsageSamples = 500
+
+// UsageHistory holds CPU and memory samples, newest first.
+type UsageHistory struct {
+	Window string        `json:"window"`
+	Step   string        `json:"step"`
+	CPU    []UsageMetric `json:"cpu"`
+	Memory []UsageMetric `json:"memory"`
+}
+
+// UsageSeries produces mock CPU and memory samples every step back to
+// now-window, newest first, varying around base, normally the live NodeUsage.
+// Sample times are aligned to step and each value depends only on its
+// timestamp and base, so the same clock and nodes always yield the same
+// series. Window and step must be positive, step must not exceed window, and
+// the series may hold at most MaxUsageSamples points.
+func UsageSeries(now time.Time, base ResourceUsage, window, step time.Duration) (UsageHistory, error) {
+	if window <= 0 || step <= 0 || step > window || window/step+1 > MaxUsageSamples {
+		return UsageHistory{}, ErrInvalidSeries
+	}
+
+	latest := now.UTC().Truncate(step)
+	count := int(window/step) + 1
+
+	history := UsageHistory{
+		Window: window.String(),
+		Step:   step.String(),
+		CPU:    make([]UsageMetric, 0, count),
+		Memory: make([]UsageMetric, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		at := latest.Add(-time.Duration(i) * step)
+		timestamp := at.Format(time.RFC3339)
+		history.CPU = append(history.CPU, sampleAt(base.CPU, at, timestamp))
+		history.Memory = append(history.Memory, sampleAt(base.Memory, at, timestamp))
+	}
+	return history, nil
+}
+
+// sampleAt varies the baseline by up to 10% of its total along a daily wave.
+func sampleAt(base UsageMetric, at time.Time, timestamp string) UsageMetric {
+	phase := float64(at.Unix()%86400) / 86400 * 2 * math.Pi
+	used := base.Used + base.Total*0.1*math.Sin(phase)
+	used = math.Max(0, math.Min(base.Total, used))
+	return UsageMetric{
+		Used:      math.Round(used*10) / 10,
+		Total:     base.Total,
+		Unit:      base.Unit,
+		Timestamp: timestamp,
+	}
+}
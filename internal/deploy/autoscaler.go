@@ -0,0 +1,186 @@
+package deploy
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInvalidAutoscaler indicates the autoscaler bounds or target are invalid.
+var ErrInvalidAutoscaler = errors.New("invalid autoscaler spec")
+
+// ErrNoAutoscaler indicates the deployment has no autoscaler attached.
+var ErrNoAutoscaler = errors.New("autoscaler not found")
+
+const (
+	// autoscalerSyncPeriod mirrors the HPA controller resync interval.
+	autoscalerSyncPeriod = 15 * time.Second
+	// autoscalerTolerance skips scaling while usage is within 10% of target.
+	autoscalerTolerance = 0.1
+	// defaultCPULoad is the synthesized utilisation of a new autoscaler.
+	defaultCPULoad = 50
+)
+
+// AutoscalerSpec configures a mock horizontal pod autoscaler.
+type AutoscalerSpec struct {
+	Min       int `json:"min"`
+	Max       int `json:"max"`
+	TargetCPU int `json:"targetCPU"`
+}
+
+// Autoscaler reports the state of a deployment's autoscaler.
+type Autoscaler struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	MinReplicas     int    `json:"minReplicas"`
+	MaxReplicas     int    `json:"maxReplicas"`
+	TargetCPU       int    `json:"targetCPU"`
+	CurrentCPU      int    `json:"currentCPU"`
+	CurrentReplicas int    `json:"currentReplicas"`
+	DesiredReplicas int    `json:"desiredReplicas"`
+	LastScaleTime   string `json:"lastScaleTime,omitempty"`
+}
+
+type autoscaler struct {
+	spec AutoscalerSpec
+	// demand is the synthesized total CPU load in percent-of-one-replica, so
+	// utilisation drops as replicas are added.
+	demand    int
+	lastSync  time.Time
+	lastScale time.Time
+}
+
+// SetAutoscaler attaches or replaces the autoscaler of a deployment, clamping
+// its replicas into the new bounds. The replaced spec is returned, or nil when
+// the deployment had no autoscaler.
+func (s *Store) SetAutoscaler(name string, spec AutoscalerSpec, now time.Time) (Autoscaler, *AutoscalerSpec, error) {
+	if spec.Min < 1 || spec.Max < spec.Min || !validReplicas(spec.Max) || spec.TargetCPU < 1 || spec.TargetCPU > 100 {
+		return Autoscaler{}, nil, ErrInvalidAutoscaler
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Autoscaler{}, nil, err
+	}
+
+	var previous *AutoscalerSpec
+	if old, ok := s.autoscalers[k]; ok {
+		prev := old.spec
+		previous = &prev
+	}
+
+	hpa := &autoscaler{
+		spec:     spec,
+		demand:   defaultCPULoad * max(rec.DesiredReplicas, 1),
+		lastSync: now,
+	}
+	if clamped := clamp(rec.DesiredReplicas, spec.Min, spec.Max); clamped != rec.DesiredReplicas {
+		rec = applyScale(rec, clamped, now)
+		s.items[k] = rec
+		hpa.lastScale = now
+	}
+	s.autoscalers[k] = hpa
+	return hpa.status(rec), previous, nil
+}
+
+// GetAutoscaler returns the autoscaler state of a deployment at now.
+func (s *Store) GetAutoscaler(name string, now time.Time) (Autoscaler, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Autoscaler{}, err
+	}
+	hpa, ok := s.autoscalers[k]
+	if !ok {
+		return Autoscaler{}, ErrNoAutoscaler
+	}
+	return hpa.status(rec), nil
+}
+
+// SetCPULoad simulates traffic by setting the current average CPU utilisation
+// of an autoscaled deployment's replicas, in percent.
+func (s *Store) SetCPULoad(name string, percent int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return err
+	}
+	hpa, ok := s.autoscalers[k]
+	if !ok {
+		return ErrNoAutoscaler
+	}
+	hpa.demand = percent * max(rec.DesiredReplicas, 1)
+	return nil
+}
+
+// reconcileAutoscalers moves each autoscaled deployment to its recommended
+// replica count once per sync period of the given clock. Callers must hold
+// the write lock.
+func (s *Store) reconcileAutoscalers(now time.Time) {
+	for k, hpa := range s.autoscalers {
+		rec, ok := s.items[k]
+		if !ok {
+			delete(s.autoscalers, k)
+			continue
+		}
+		if now.Sub(hpa.lastSync) < autoscalerSyncPeriod {
+			continue
+		}
+		hpa.lastSync = now
+
+		if desired := hpa.recommend(rec); desired != rec.DesiredReplicas {
+			s.items[k] = applyScale(rec, desired, now)
+			hpa.lastScale = now
+		}
+	}
+}
+
+func (h *autoscaler) utilisation(replicas int) int {
+	return h.demand / max(replicas, 1)
+}
+
+// recommend applies the HPA formula ceil(current * usage / target).
+func (h *autoscaler) recommend(rec record) int {
+	current := max(rec.DesiredReplicas, 1)
+	ratio := float64(h.utilisation(current)) / float64(h.spec.TargetCPU)
+	if math.Abs(ratio-1) <= autoscalerTolerance {
+		return clamp(rec.DesiredReplicas, h.spec.Min, h.spec.Max)
+	}
+	return clamp(int(math.Ceil(float64(current)*ratio)), h.spec.Min, h.spec.Max)
+}
+
+func (h *autoscaler) status(rec record) Autoscaler {
+	out := Autoscaler{
+		Name:            rec.Name,
+		Namespace:       rec.Namespace,
+		MinReplicas:     h.spec.Min,
+		MaxReplicas:     h.spec.Max,
+		TargetCPU:       h.spec.TargetCPU,
+		CurrentCPU:      h.utilisation(rec.DesiredReplicas),
+		CurrentReplicas: rec.DesiredReplicas,
+		DesiredReplicas: h.recommend(rec),
+	}
+	if !h.lastScale.IsZero() {
+		out.LastScaleTime = h.lastScale.Format(time.RFC3339)
+	}
+	return out
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+package deploy
+
+import (
+	"slices"
+	"time"
+)
+
+// Comparison is a field-by-field diff between two deployments.
+type Comparison struct {
+	A           string      `json:"a"`
+	B           string      `json:"b"`
+	Identical   bool        `json:"identical"`
+	Differences []FieldDiff `json:"differences"`
+}
+
+// FieldDiff holds the differing values of one compared field.
+type FieldDiff struct {
+	Field string `json:"field"`
+	A     any    `json:"a"`
+	B     any    `json:"b"`
+}
+
+// GetNamespaced returns the deployment detail for namespace/name.
+func (s *Store) GetNamespaced(namespace, name string, now time.Time) (Detail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	rec, ok := s.items[key(namespace, name)]
+	if !ok {
+		return Detail{}, ErrNotFound
+	}
+	return toDetail(rec, now), nil
+}
+
+// Compare diffs the images, desired replicas and strategy of a and b.
+func Compare(a, b Detail) Comparison {
+	out := Comparison{
+		A:           key(a.Namespace, a.Name),
+		B:           key(b.Namespace, b.Name),
+		Differences: []FieldDiff{},
+	}
+	if !slices.Equal(a.Images, b.Images) {
+		out.Differences = append(out.Differences, FieldDiff{Field: "images", A: a.Images, B: b.Images})
+	}
+	if a.DesiredReplicas != b.DesiredReplicas {
+		out.Differences = append(out.Differences, FieldDiff{Field: "replicas", A: a.DesiredReplicas, B: b.DesiredReplicas})
+	}
+	if a.Strategy != b.Strategy {
+		out.Differences = append(out.Differences, FieldDiff{Field: "strategy", A: a.Strategy, B: b.Strategy})
+	}
+	if !equalRollingUpdate(a.RollingUpdate, b.RollingUpdate) {
+		out.Differences = append(out.Differences, FieldDiff{Field: "rollingUpdate", A: a.RollingUpdate, B: b.RollingUpdate})
+	}
+	out.Identical = len(out.Differences) == 0
+	return out
+}
+
+func equalRollingUpdate(a, b *RollingUpdate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
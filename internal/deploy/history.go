@@ -0,0 +1,145 @@
+package deploy
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnknownRevision indicates a rollback target is not in the retained history.
+var ErrUnknownRevision = errors.New("revision not found")
+
+// revisionHistoryLimit caps how many revision snapshots each deployment keeps.
+const revisionHistoryLimit = 10
+
+type revisionSnapshot struct {
+	Revision      int
+	Replicas      int
+	Strategy      string
+	Images        []string
+	Containers    []Container
+	RollingUpdate *RollingUpdate
+	UpdatedAt     time.Time
+}
+
+// Revision is a retained snapshot of a deployment revision.
+type Revision struct {
+	Revision  int      `json:"revision"`
+	Images    []string `json:"images"`
+	Replicas  int      `json:"replicas"`
+	Strategy  string   `json:"strategy"`
+	Timestamp string   `json:"timestamp"`
+}
+
+// Revisions returns the retained revisions of a deployment, oldest first. At
+// most revisionHistoryLimit revisions are kept.
+func (s *Store) Revisions(name string) ([]Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, rec, err := s.findByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Revision, 0, len(rec.History))
+	for _, snap := range rec.History {
+		out = append(out, Revision{
+			Revision:  snap.Revision,
+			Images:    append([]string{}, snap.Images...),
+			Replicas:  snap.Replicas,
+			Strategy:  snap.Strategy,
+			Timestamp: snap.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
+
+// Rollback restores the snapshot stored for toRevision, or the previous
+// revision when toRevision is zero, as a new revision. It also returns the
+// revision that was current before the rollback.
+func (s *Store) Rollback(name string, toRevision int, now time.Time) (Detail, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, 0, err
+	}
+
+	target, ok := findRevision(rec, toRevision)
+	if !ok {
+		return Detail{}, 0, ErrUnknownRevision
+	}
+
+	previous := rec.Revision
+	rec.DesiredReplicas = target.Replicas
+	if rec.ReadyReplicas > target.Replicas {
+		rec.ReadyReplicas = target.Replicas
+	}
+	if rec.UpdatedReplicas > target.Replicas {
+		rec.UpdatedReplicas = target.Replicas
+	}
+	rec.Strategy = target.Strategy
+	rec.Images = append([]string{}, target.Images...)
+	rec.Containers = copyContainers(target.Containers)
+	rec.RollingUpdate = copyRollingUpdate(target.RollingUpdate)
+	rec.LastUpdate = now
+	rec.Revision++
+	rec = recordRevision(rec)
+	s.items[k] = rec
+	return toDetail(rec, now), previous, nil
+}
+
+func findRevision(rec record, revision int) (revisionSnapshot, bool) {
+	if revision == 0 {
+		// The newest snapshot is the current revision.
+		if len(rec.History) < 2 {
+			return revisionSnapshot{}, false
+		}
+		return rec.History[len(rec.History)-2], true
+	}
+	for _, snap := range rec.History {
+		if snap.Revision == revision {
+			return snap, true
+		}
+	}
+	return revisionSnapshot{}, false
+}
+
+// recordRevision appends the record's current state to its history. The
+// slice is always copied so projected records never share backing arrays
+// with stored ones.
+func recordRevision(rec record) record {
+	history := rec.History
+	if len(history) >= revisionHistoryLimit {
+		history = history[len(history)-revisionHistoryLimit+1:]
+	}
+	rec.History = append(append([]revisionSnapshot{}, history...), revisionSnapshot{
+		Revision:      rec.Revision,
+		Replicas:      rec.DesiredReplicas,
+		Strategy:      rec.Strategy,
+		Images:        append([]string{}, rec.Images...),
+		Containers:    copyContainers(rec.Containers),
+		RollingUpdate: copyRollingUpdate(rec.RollingUpdate),
+		UpdatedAt:     rec.LastUpdate,
+	})
+	return rec
+}
+
+func copyContainers(src []Container) []Container {
+	out := make([]Container, 0, len(src))
+	for _, c := range src {
+		c.Ports = append([]int{}, c.Ports...)
+		out = append(out, c)
+	}
+	return out
+}
+
+func copyRollingUpdate(src *RollingUpdate) *RollingUpdate {
+	if src == nil {
+		return nil
+	}
+	copied := *src
+	return &copied
+}
@@ -0,0 +1,73 @@
+package deploy
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// recommendScaleUpCPU and recommendScaleDownCPU bound the synthesized
+	// utilisation at which the recommender suggests a change.
+	recommendScaleUpCPU   = 70
+	recommendScaleDownCPU = 30
+	// recommendTargetCPU is the utilisation a recommendation aims for.
+	recommendTargetCPU = 60
+)
+
+// Recommendation is a suggested replica count for a deployment.
+type Recommendation struct {
+	Name                string `json:"name"`
+	Namespace           string `json:"namespace"`
+	CurrentReplicas     int    `json:"currentReplicas"`
+	ReadyReplicas       int    `json:"readyReplicas"`
+	CPU                 int    `json:"cpu"`
+	RecommendedReplicas int    `json:"recommendedReplicas"`
+	Rationale           string `json:"rationale"`
+}
+
+// Recommend suggests a replica count from the deployment's synthesized CPU
+// utilisation. A deployment with no ready replicas has no load to measure, so
+// it keeps its current replicas.
+func (s *Store) Recommend(name string) (Recommendation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Recommendation{}, err
+	}
+
+	cpu := min(s.cpuUtilisation(k, rec), 100)
+	out := Recommendation{
+		Name:                rec.Name,
+		Namespace:           rec.Namespace,
+		CurrentReplicas:     rec.DesiredReplicas,
+		ReadyReplicas:       rec.ReadyReplicas,
+		CPU:                 cpu,
+		RecommendedReplicas: rec.DesiredReplicas,
+	}
+
+	target := int(math.Ceil(float64(max(rec.DesiredReplicas, 1)*cpu) / recommendTargetCPU))
+	switch {
+	case rec.ReadyReplicas == 0:
+		out.Rationale = "No replicas are ready, so CPU cannot be measured; keep the current replicas."
+	case cpu > recommendScaleUpCPU && target > rec.DesiredReplicas:
+		out.RecommendedReplicas = min(target, maxReplicas)
+		out.Rationale = fmt.Sprintf("CPU at %d%% is above %d%%; scale up to bring it near %d%%.", cpu, recommendScaleUpCPU, recommendTargetCPU)
+	case cpu < recommendScaleDownCPU && rec.DesiredReplicas > 1:
+		out.RecommendedReplicas = max(target, 1)
+		out.Rationale = fmt.Sprintf("CPU at %d%% is below %d%%; scale down to bring it near %d%%.", cpu, recommendScaleDownCPU, recommendTargetCPU)
+	default:
+		out.Rationale = fmt.Sprintf("CPU at %d%% is within %d%%-%d%%; keep the current replicas.", cpu, recommendScaleDownCPU, recommendScaleUpCPU)
+	}
+	return out, nil
+}
+
+// cpuUtilisation is the synthesized average CPU of a deployment's replicas:
+// its autoscaler's load when one is attached, defaultCPULoad otherwise.
+func (s *Store) cpuUtilisation(k string, rec record) int {
+	if hpa, ok := s.autoscalers[k]; ok {
+		return hpa.utilisation(rec.DesiredReplicas)
+	}
+	return defaultCPULoad
+}
@@ -0,0 +1,111 @@
+package deploy
+
+import "time"
+
+// rolloutStepInterval is how long each replica takes to update after a
+// restart.
+const rolloutStepInterval = 5 * time.Second
+
+// Restart triggers a rollout restart: the revision is bumped and every
+// replica becomes outdated until reconcileRollouts moves it back.
+func (s *Store) Restart(name string, now time.Time) (Detail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, err
+	}
+
+	rec.UpdatedReplicas = 0
+	rec.RolloutStep = now
+	rec.Conditions = setCondition(rec.Conditions, conditionRecord{
+		Type:           "Progressing",
+		Status:         "True",
+		Message:        "restarted",
+		LastUpdate:     now,
+		LastTransition: now,
+	})
+	rec.LastUpdate = now
+	rec.Revision++
+	rec = recordRevision(rec)
+	s.items[k] = rec
+	return toDetail(rec, now), nil
+}
+
+// reconcileRollouts updates one replica per rolloutStepInterval elapsed for
+// every restarting deployment. Callers must hold the write lock.
+func (s *Store) reconcileRollouts(now time.Time) {
+	for k, rec := range s.items {
+		if rec.RolloutStep.IsZero() || rec.Paused {
+			continue
+		}
+		steps := int(now.Sub(rec.RolloutStep) / rolloutStepInterval)
+		if steps <= 0 {
+			continue
+		}
+		rec.UpdatedReplicas = min(rec.UpdatedReplicas+steps, rec.DesiredReplicas)
+		rec.RolloutStep = rec.RolloutStep.Add(time.Duration(steps) * rolloutStepInterval)
+		if rec.UpdatedReplicas >= rec.DesiredReplicas {
+			rec.RolloutStep = time.Time{}
+		}
+		s.items[k] = rec
+	}
+}
+
+// SetPaused pauses or resumes a deployment rollout, also returning whether it
+// was paused before. Resuming settles the ready and updated counts against
+// any scale made while paused.
+func (s *Store) SetPaused(name string, paused bool, now time.Time) (Detail, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, false, err
+	}
+	if rec.Paused == paused {
+		return toDetail(rec, now), paused, nil
+	}
+
+	progressing := conditionRecord{
+		Type:           "Progressing",
+		Status:         "Unknown",
+		Message:        "Deployment is paused",
+		LastUpdate:     now,
+		LastTransition: now,
+	}
+	if !paused {
+		rec = settleReplicas(rec)
+		progressing.Status = "True"
+		progressing.Message = "Deployment is resumed"
+		if !rec.RolloutStep.IsZero() {
+			rec.RolloutStep = now
+		}
+	}
+	rec.Paused = paused
+	rec.Conditions = setCondition(rec.Conditions, progressing)
+	rec.LastUpdate = now
+	s.items[k] = rec
+	return toDetail(rec, now), !paused, nil
+}
+
+// setCondition replaces the condition of the same type or appends it.
+func setCondition(conditions []conditionRecord, c conditionRecord) []conditionRecord {
+	out := make([]conditionRecord, 0, len(conditions)+1)
+	replaced := false
+	for _, existing := range conditions {
+		if existing.Type == c.Type {
+			out = append(out, c)
+			replaced = true
+			continue
+		}
+		out = append(out, existing)
+	}
+	if !replaced {
+		out = append(out, c)
+	}
+	return out
+}
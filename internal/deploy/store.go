@@ -2,19 +2,38 @@ package deploy
 
 import (
 	"errors"
-	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"k8s_dashboard/internal/age"
+	"k8s_dashboard/internal/names"
 )
 
 // ErrNotFound indicates the deployment was not found.
 var ErrNotFound = errors.New("deployment not found")
 
+// ErrAmbiguous indicates a name-only lookup matched deployments in several
+// namespaces.
+var ErrAmbiguous = errors.New("deployment name is ambiguous")
+
 // ErrInvalidReplicas indicates the desired replica count is invalid for mock.
 var ErrInvalidReplicas = errors.New("invalid replica count")
 
+// ErrInvalidStrategy indicates the rolling update parameters are invalid.
+var ErrInvalidStrategy = errors.New("invalid rolling update parameters")
+
+// ErrExists indicates a deployment with the same namespace and name exists.
+var ErrExists = errors.New("deployment already exists")
+
+// ErrUnknownContainer indicates the deployment has no container of that name.
+var ErrUnknownContainer = errors.New("container not found")
+
+// ErrInvalidSpec indicates a create request is missing required fields.
+var ErrInvalidSpec = errors.New("invalid deployment spec")
+
 // Summary represents deployment information shown in the table.
 type Summary struct {
 	Name            string   `json:"name"`
@@ -25,6 +44,7 @@ type Summary struct {
 	Strategy        string   `json:"strategy"`
 	Images          []string `json:"images"`
 	Age             string   `json:"age"`
+	AgeSeconds      int64    `json:"ageSeconds"`
 	Status          string   `json:"status"`
 }
 
@@ -37,13 +57,78 @@ type Detail struct {
 	Conditions  []Condition       `json:"conditions"`
 	Revision    int               `json:"revision"`
 	LastUpdated string            `json:"lastUpdated"`
+
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+	Paused        bool           `json:"paused"`
+
+	// ResourceTotals sums container requests and limits across all desired
+	// replicas.
+	ResourceTotals Resources `json:"resourceTotals"`
+
+	// LastUpdatedLocal is LastUpdated rendered in a caller-requested timezone.
+	LastUpdatedLocal string `json:"lastUpdatedLocal,omitempty"`
+}
+
+// DeploymentStatus is the status subresource of a deployment, omitting the
+// spec and template metadata.
+type DeploymentStatus struct {
+	Name               string      `json:"name"`
+	Namespace          string      `json:"namespace"`
+	ReadyReplicas      int         `json:"readyReplicas"`
+	UpdatedReplicas    int         `json:"updatedReplicas"`
+	DesiredReplicas    int         `json:"desiredReplicas"`
+	Status             string      `json:"status"`
+	ObservedGeneration int         `json:"observedGeneration"`
+	Conditions         []Condition `json:"conditions"`
+}
+
+// RollingUpdate holds the surge and unavailability limits of a RollingUpdate
+// strategy, either as an absolute count ("1") or a percentage ("25%").
+type RollingUpdate struct {
+	MaxSurge       string `json:"maxSurge"`
+	MaxUnavailable string `json:"maxUnavailable"`
 }
 
 // Container summarises the pod template containers.
 type Container struct {
-	Name  string `json:"name"`
-	Image string `json:"image"`
-	Ports []int  `json:"ports"`
+	Name      string    `json:"name"`
+	Image     string    `json:"image"`
+	Ports     []int     `json:"ports"`
+	Resources Resources `json:"resources"`
+}
+
+// Resources holds the requests and limits of a container.
+type Resources struct {
+	Requests ResourceList `json:"requests"`
+	Limits   ResourceList `json:"limits"`
+}
+
+// ResourceList is an amount of CPU in millicores and memory in MiB.
+type ResourceList struct {
+	CPUMillis int64 `json:"cpuMillis"`
+	MemoryMiB int64 `json:"memoryMiB"`
+}
+
+func (l ResourceList) add(other ResourceList) ResourceList {
+	return ResourceList{CPUMillis: l.CPUMillis + other.CPUMillis, MemoryMiB: l.MemoryMiB + other.MemoryMiB}
+}
+
+func (l ResourceList) times(n int) ResourceList {
+	return ResourceList{CPUMillis: l.CPUMillis * int64(n), MemoryMiB: l.MemoryMiB * int64(n)}
+}
+
+func (l ResourceList) negative() bool {
+	return l.CPUMillis < 0 || l.MemoryMiB < 0
+}
+
+// resourceTotals returns the footprint of replicas copies of containers.
+func resourceTotals(containers []Container, replicas int) Resources {
+	var per Resources
+	for _, c := range containers {
+		per.Requests = per.Requests.add(c.Resources.Requests)
+		per.Limits = per.Limits.add(c.Resources.Limits)
+	}
+	return Resources{Requests: per.Requests.times(replicas), Limits: per.Limits.times(replicas)}
 }
 
 // Condition models a deployment condition entry.
@@ -55,6 +140,23 @@ type Condition struct {
 	LastTransition string `json:"lastTransition"`
 }
 
+// CreateSpec describes a deployment to add at runtime.
+type CreateSpec struct {
+	Name       string      `json:"name"`
+	Namespace  string      `json:"namespace"`
+	Replicas   int         `json:"replicas"`
+	Strategy   string      `json:"strategy"`
+	Containers []Container `json:"containers"`
+}
+
+// ScalePreview reports the projected outcome of a scale request.
+type ScalePreview struct {
+	Valid     bool    `json:"valid"`
+	Replicas  int     `json:"replicas"`
+	Reason    string  `json:"reason,omitempty"`
+	Projected Summary `json:"projected"`
+}
+
 type record struct {
 	Summary
 	CreatedAt  time.Time
@@ -64,6 +166,14 @@ type record struct {
 	Containers []Container
 	Conditions []conditionRecord
 	LastUpdate time.Time
+
+	RollingUpdate *RollingUpdate
+	History       []revisionSnapshot
+	Paused        bool
+
+	// RolloutStep is when the last replica was updated during a restart; zero
+	// when no restart is in progress.
+	RolloutStep time.Time
 }
 
 type conditionRecord struct {
@@ -76,27 +186,32 @@ type conditionRecord struct {
 
 // Store keeps deployment mock data in memory.
 type Store struct {
-	mu    sync.RWMutex
-	items map[string]record
+	mu          sync.RWMutex
+	items       map[string]record
+	autoscalers map[string]*autoscaler
 }
 
 // NewStore seeds deployments with deterministic data.
 func NewStore(now time.Time) *Store {
-	s := &Store{items: make(map[string]record)}
+	s := &Store{
+		items:       make(map[string]record),
+		autoscalers: make(map[string]*autoscaler),
+	}
 	for _, rec := range defaultSeed(now) {
-		s.items[key(rec.Namespace, rec.Name)] = rec
+		s.items[key(rec.Namespace, rec.Name)] = recordRevision(rec)
 	}
 	return s
 }
 
 // List returns deployments sorted by namespace/ name.
 func (s *Store) List(now time.Time) []Summary {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
 
 	out := make([]Summary, 0, len(s.items))
 	for _, rec := range s.items {
-		out = append(out, decorateSummary(rec.Summary, rec.CreatedAt, now))
+		out = append(out, decorateSummary(rec, now))
 	}
 
 	sort.Slice(out, func(i, j int) bool {
@@ -109,51 +224,349 @@ func (s *Store) List(now time.Time) []Summary {
 	return out
 }
 
-// Get returns the deployment detail by name.
+// Get returns the deployment detail by name, or ErrAmbiguous when the name
+// exists in several namespaces.
 func (s *Store) Get(name string, now time.Time) (Detail, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	_, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, err
+	}
+	return toDetail(rec, now), nil
+}
 
+// NamespaceResources sums ResourceTotals across the deployments in namespace
+// at now.
+func (s *Store) NamespaceResources(namespace string, now time.Time) Resources {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	var out Resources
 	for _, rec := range s.items {
-		if rec.Name == name {
-			return toDetail(rec, now), nil
+		if rec.Namespace != namespace {
+			continue
 		}
+		totals := resourceTotals(rec.Containers, rec.DesiredReplicas)
+		out.Requests = out.Requests.add(totals.Requests)
+		out.Limits = out.Limits.add(totals.Limits)
+	}
+	return out
+}
+
+// GetStatus returns only the status portion of a deployment.
+func (s *Store) GetStatus(name string, now time.Time) (DeploymentStatus, error) {
+	detail, err := s.Get(name, now)
+	if err != nil {
+		return DeploymentStatus{}, err
+	}
+
+	return DeploymentStatus{
+		Name:               detail.Name,
+		Namespace:          detail.Namespace,
+		ReadyReplicas:      detail.ReadyReplicas,
+		UpdatedReplicas:    detail.UpdatedReplicas,
+		DesiredReplicas:    detail.DesiredReplicas,
+		Status:             detail.Status,
+		ObservedGeneration: detail.Revision,
+		Conditions:         detail.Conditions,
+	}, nil
+}
+
+// Scale updates the desired replicas for a deployment, also returning the
+// desired replicas it replaced.
+func (s *Store) Scale(name string, replicas int, now time.Time) (Detail, int, error) {
+	if !validReplicas(replicas) {
+		return Detail{}, 0, ErrInvalidReplicas
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, 0, err
 	}
-	return Detail{}, ErrNotFound
+	previous := rec.DesiredReplicas
+	rec = applyScale(rec, replicas, now)
+	s.items[k] = rec
+	return toDetail(rec, now), previous, nil
 }
 
-// Scale updates the desired replicas for a deployment.
-func (s *Store) Scale(name string, replicas int, now time.Time) (Detail, error) {
-	if replicas < 0 || replicas > 200 {
+// Create adds a deployment from spec. Namespace defaults to "default" and
+// strategy to RollingUpdate with 25% surge and unavailability.
+func (s *Store) Create(spec CreateSpec, now time.Time) (Detail, error) {
+	if spec.Namespace == "" {
+		spec.Namespace = "default"
+	}
+	if spec.Strategy == "" {
+		spec.Strategy = "RollingUpdate"
+	}
+	if spec.Name == "" || len(spec.Containers) == 0 {
+		return Detail{}, ErrInvalidSpec
+	}
+	images := make([]string, 0, len(spec.Containers))
+	for _, c := range spec.Containers {
+		if c.Name == "" || c.Image == "" || c.Resources.Requests.negative() || c.Resources.Limits.negative() {
+			return Detail{}, ErrInvalidSpec
+		}
+		images = append(images, c.Image)
+	}
+	if !validReplicas(spec.Replicas) {
 		return Detail{}, ErrInvalidReplicas
 	}
 
+	var rolling *RollingUpdate
+	switch spec.Strategy {
+	case "RollingUpdate":
+		rolling = &RollingUpdate{MaxSurge: "25%", MaxUnavailable: "25%"}
+	case "Recreate":
+	default:
+		return Detail{}, ErrInvalidStrategy
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(spec.Namespace, spec.Name)
+	if _, exists := s.items[k]; exists {
+		return Detail{}, ErrExists
+	}
+
+	rec := record{
+		Summary: Summary{
+			Name:            spec.Name,
+			Namespace:       spec.Namespace,
+			ReadyReplicas:   spec.Replicas,
+			UpdatedReplicas: spec.Replicas,
+			DesiredReplicas: spec.Replicas,
+			Strategy:        spec.Strategy,
+			Images:          images,
+		},
+		CreatedAt:  now,
+		Revision:   1,
+		Labels:     map[string]string{"app": spec.Name},
+		Selector:   map[string]string{"app": spec.Name},
+		Containers: copyContainers(spec.Containers),
+		Conditions: []conditionRecord{
+			{
+				Type:           "Available",
+				Status:         "True",
+				Message:        "Deployment has minimum availability.",
+				LastUpdate:     now,
+				LastTransition: now,
+			},
+		},
+		LastUpdate:    now,
+		RollingUpdate: rolling,
+	}
+	rec = recordRevision(rec)
+	s.items[k] = rec
+	return toDetail(rec, now), nil
+}
+
+// PreviewScale validates a scale request and projects the resulting status
+// without mutating the stored deployment.
+func (s *Store) PreviewScale(name string, replicas int, now time.Time) (ScalePreview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	_, rec, err := s.findByName(name)
+	if err != nil {
+		return ScalePreview{}, err
+	}
+	if !validReplicas(replicas) {
+		return ScalePreview{
+			Valid:     false,
+			Replicas:  replicas,
+			Reason:    ErrInvalidReplicas.Error(),
+			Projected: decorateSummary(rec, now),
+		}, nil
+	}
+	projected := applyScale(rec, replicas, now)
+	return ScalePreview{
+		Valid:     true,
+		Replicas:  replicas,
+		Projected: decorateSummary(projected, now),
+	}, nil
+}
+
+// UpdateRollingUpdate changes the surge and unavailability limits of a
+// RollingUpdate deployment. Empty fields keep their current value. The
+// limits it replaced are returned alongside the detail.
+func (s *Store) UpdateRollingUpdate(name string, patch RollingUpdate, now time.Time) (Detail, RollingUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, RollingUpdate{}, err
+	}
+	if rec.RollingUpdate == nil {
+		return Detail{}, RollingUpdate{}, ErrInvalidStrategy
+	}
+
+	next := *rec.RollingUpdate
+	if patch.MaxSurge != "" {
+		next.MaxSurge = patch.MaxSurge
+	}
+	if patch.MaxUnavailable != "" {
+		next.MaxUnavailable = patch.MaxUnavailable
+	}
+
+	surge, ok := parseIntOrPercent(next.MaxSurge)
+	if !ok {
+		return Detail{}, RollingUpdate{}, ErrInvalidStrategy
+	}
+	unavailable, ok := parseIntOrPercent(next.MaxUnavailable)
+	if !ok {
+		return Detail{}, RollingUpdate{}, ErrInvalidStrategy
+	}
+	if surge == 0 && unavailable == 0 {
+		return Detail{}, RollingUpdate{}, ErrInvalidStrategy
+	}
+
+	previous := *rec.RollingUpdate
+	rec.RollingUpdate = &next
+	rec.LastUpdate = now
+	rec.Revision++
+	s.items[k] = recordRevision(rec)
+	return toDetail(rec, now), previous, nil
+}
+
+// SetImage changes the image of the named container and rolls a new revision,
+// also returning the image it replaced.
+func (s *Store) SetImage(name, container, image string, now time.Time) (Detail, string, error) {
+	if strings.TrimSpace(image) == "" {
+		return Detail{}, "", ErrInvalidSpec
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.reconcile(now)
+
+	k, rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, "", err
+	}
+
+	containers := copyContainers(rec.Containers)
+	found, previous := false, ""
+	images := make([]string, 0, len(containers))
+	for i := range containers {
+		if containers[i].Name == container {
+			previous = containers[i].Image
+			containers[i].Image = image
+			found = true
+		}
+		images = append(images, containers[i].Image)
+	}
+	if !found {
+		return Detail{}, "", ErrUnknownContainer
+	}
+
+	rec.Containers = containers
+	rec.Images = images
+	rec.LastUpdate = now
+	rec.Revision++
+	rec = recordRevision(rec)
+	s.items[k] = rec
+	return toDetail(rec, now), previous, nil
+}
+
+// parseIntOrPercent accepts a non-negative integer or a percentage between
+// 0% and 100%.
+func parseIntOrPercent(value string) (int, bool) {
+	if pct, ok := strings.CutSuffix(value, "%"); ok {
+		n, err := strconv.Atoi(pct)
+		if err != nil || n < 0 || n > 100 {
+			return 0, false
+		}
+		return n, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// reconcile lazily applies the autoscaler syncs and rollout steps due by now,
+// so every read and change at now sees them without handlers driving the
+// clock, like namespace expiry. Callers must hold the write lock.
+func (s *Store) reconcile(now time.Time) {
+	s.reconcileAutoscalers(now)
+	s.reconcileRollouts(now)
+}
 
-	for key, rec := range s.items {
+// findByName returns the map key and record of the only deployment with the
+// given name. It returns ErrAmbiguous when the name exists in several
+// namespaces. Callers must hold the lock.
+func (s *Store) findByName(name string) (string, record, error) {
+	var (
+		foundKey string
+		found    record
+		count    int
+	)
+	for k, rec := range s.items {
 		if rec.Name == name {
-			rec.DesiredReplicas = replicas
-			if rec.ReadyReplicas > replicas {
-				rec.ReadyReplicas = replicas
-			}
-			if rec.UpdatedReplicas > replicas {
-				rec.UpdatedReplicas = replicas
-			}
-			rec.LastUpdate = now
-			rec.Revision++
-			s.items[key] = rec
-			return toDetail(rec, now), nil
+			foundKey, found = k, rec
+			count++
 		}
 	}
+	switch count {
+	case 0:
+		return "", record{}, ErrNotFound
+	case 1:
+		return foundKey, found, nil
+	default:
+		return "", record{}, ErrAmbiguous
+	}
+}
 
-	return Detail{}, ErrNotFound
+// maxReplicas is the largest replica count a deployment accepts.
+const maxReplicas = 200
+
+func validReplicas(replicas int) bool {
+	return replicas >= 0 && replicas <= maxReplicas
 }
 
-func decorateSummary(sum Summary, created time.Time, now time.Time) Summary {
-	out := sum
-	out.Age = formatAge(now.Sub(created))
-	if out.ReadyReplicas == out.DesiredReplicas {
+// applyScale sets the desired replicas. Paused deployments keep their ready
+// and updated counts until resumed.
+func applyScale(rec record, replicas int, now time.Time) record {
+	rec.DesiredReplicas = replicas
+	if !rec.Paused {
+		rec = settleReplicas(rec)
+	}
+	rec.LastUpdate = now
+	rec.Revision++
+	return recordRevision(rec)
+}
+
+// settleReplicas caps the ready and updated counts at the desired replicas.
+func settleReplicas(rec record) record {
+	if rec.ReadyReplicas > rec.DesiredReplicas {
+		rec.ReadyReplicas = rec.DesiredReplicas
+	}
+	if rec.UpdatedReplicas > rec.DesiredReplicas {
+		rec.UpdatedReplicas = rec.DesiredReplicas
+	}
+	return rec
+}
+
+func decorateSummary(rec record, now time.Time) Summary {
+	out := rec.Summary
+	out.Age = age.Format(now.Sub(rec.CreatedAt))
+	out.AgeSeconds = age.Seconds(now.Sub(rec.CreatedAt))
+	if rec.Paused {
+		out.Status = "Paused"
+	} else if out.ReadyReplicas == out.DesiredReplicas && out.UpdatedReplicas >= out.DesiredReplicas {
 		out.Status = "Healthy"
 	} else if out.ReadyReplicas == 0 {
 		out.Status = "Down"
@@ -164,7 +577,7 @@ func decorateSummary(sum Summary, created time.Time, now time.Time) Summary {
 }
 
 func toDetail(rec record, now time.Time) Detail {
-	summary := decorateSummary(rec.Summary, rec.CreatedAt, now)
+	summary := decorateSummary(rec, now)
 
 	labels := copyMap(rec.Labels)
 	selector := copyMap(rec.Selector)
@@ -180,14 +593,24 @@ func toDetail(rec record, now time.Time) Detail {
 		})
 	}
 
+	var rolling *RollingUpdate
+	if rec.RollingUpdate != nil {
+		copied := *rec.RollingUpdate
+		rolling = &copied
+	}
+
 	return Detail{
-		Summary:     summary,
-		Labels:      labels,
-		Selector:    selector,
-		Containers:  append([]Container{}, rec.Containers...),
-		Conditions:  conditions,
-		Revision:    rec.Revision,
-		LastUpdated: rec.LastUpdate.Format(time.RFC3339),
+		Summary:       summary,
+		Labels:        labels,
+		Selector:      selector,
+		Containers:    append([]Container{}, rec.Containers...),
+		Conditions:    conditions,
+		Revision:      rec.Revision,
+		LastUpdated:   rec.LastUpdate.Format(time.RFC3339),
+		RollingUpdate: rolling,
+		Paused:        rec.Paused,
+
+		ResourceTotals: resourceTotals(rec.Containers, rec.DesiredReplicas),
 	}
 }
 
@@ -202,28 +625,6 @@ func copyMap(src map[string]string) map[string]string {
 	return dst
 }
 
-func formatAge(d time.Duration) string {
-	if d < 0 {
-		d = 0
-	}
-
-	days := d / (24 * time.Hour)
-	d -= days * 24 * time.Hour
-	hours := d / time.Hour
-	d -= hours * time.Hour
-	minutes := d / time.Minute
-
-	switch {
-	case days > 0:
-		return fmt.Sprintf("%dd%dh", days, hours)
-	case hours > 0:
-		return fmt.Sprintf("%dh%dm", hours, minutes)
-	default:
-		secs := int(d/time.Second) % 60
-		return fmt.Sprintf("%dm%ds", minutes, secs)
-	}
-}
-
 func key(namespace, name string) string {
 	return namespace + "/" + name
 }
@@ -252,7 +653,15 @@ func defaultSeed(now time.Time) []record {
 				"app": "frontend",
 			},
 			Containers: []Container{
-				{Name: "frontend", Image: "registry.local/frontend:2.3.1", Ports: []int{80, 443}},
+				{
+					Name:  "frontend",
+					Image: "registry.local/frontend:2.3.1",
+					Ports: []int{80, 443},
+					Resources: Resources{
+						Requests: ResourceList{CPUMillis: 250, MemoryMiB: 256},
+						Limits:   ResourceList{CPUMillis: 500, MemoryMiB: 512},
+					},
+				},
 			},
 			Conditions: []conditionRecord{
 				{
@@ -263,7 +672,8 @@ func defaultSeed(now time.Time) []record {
 					LastTransition: base.Add(12 * time.Hour),
 				},
 			},
-			LastUpdate: now.Add(-30 * time.Minute),
+			LastUpdate:    now.Add(-30 * time.Minute),
+			RollingUpdate: &RollingUpdate{MaxSurge: "25%", MaxUnavailable: "25%"},
 		},
 		{
 			Summary: Summary{
@@ -285,7 +695,15 @@ func defaultSeed(now time.Time) []record {
 				"app": "backend",
 			},
 			Containers: []Container{
-				{Name: "api", Image: "registry.local/backend:1.12.0", Ports: []int{8080}},
+				{
+					Name:  "api",
+					Image: "registry.local/backend:1.12.0",
+					Ports: []int{8080},
+					Resources: Resources{
+						Requests: ResourceList{CPUMillis: 500, MemoryMiB: 512},
+						Limits:   ResourceList{CPUMillis: 1000, MemoryMiB: 1024},
+					},
+				},
 			},
 			Conditions: []conditionRecord{
 				{
@@ -296,7 +714,8 @@ func defaultSeed(now time.Time) []record {
 					LastTransition: now.Add(-10 * time.Minute),
 				},
 			},
-			LastUpdate: now.Add(-5 * time.Minute),
+			LastUpdate:    now.Add(-5 * time.Minute),
+			RollingUpdate: &RollingUpdate{MaxSurge: "25%", MaxUnavailable: "25%"},
 		},
 		{
 			Summary: Summary{
@@ -318,7 +737,15 @@ func defaultSeed(now time.Time) []record {
 				"app": "batch-jobs",
 			},
 			Containers: []Container{
-				{Name: "runner", Image: "registry.local/batch:0.5.0", Ports: []int{}},
+				{
+					Name:  "runner",
+					Image: "registry.local/batch:0.5.0",
+					Ports: []int{},
+					Resources: Resources{
+						Requests: ResourceList{CPUMillis: 200, MemoryMiB: 128},
+						Limits:   ResourceList{CPUMillis: 400, MemoryMiB: 256},
+					},
+				},
 			},
 			Conditions: []conditionRecord{
 				{
@@ -333,3 +760,16 @@ func defaultSeed(now time.Time) []record {
 		},
 	}
 }
+
+// NamesWithPrefix returns up to limit sorted, distinct deployment names starting
+// with prefix, ignoring case.
+func (s *Store) NamesWithPrefix(prefix string, limit int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates := make([]string, 0, len(s.items))
+	for _, rec := range s.items {
+		candidates = append(candidates, rec.Name)
+	}
+	return names.WithPrefix(candidates, prefix, limit)
+}
@@ -1,6 +1,7 @@
 package deploy
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -36,16 +37,16 @@ func TestGetAndScale(t *testing.T) {
 		t.Fatalf("unexpected desired replicas %d", detail.DesiredReplicas)
 	}
 
-	scaled, err := store.Scale("frontend", 6, now.Add(1*time.Minute))
+	scaled, previous, err := store.Scale("frontend", 6, now.Add(1*time.Minute))
 	if err != nil {
 		t.Fatalf("scale deployment: %v", err)
 	}
 
-	if scaled.DesiredReplicas != 6 {
-		t.Fatalf("expected desired replicas 6, got %d", scaled.DesiredReplicas)
+	if scaled.DesiredReplicas != 6 || previous != 4 {
+		t.Fatalf("expected 4 -> 6 desired replicas, got %d -> %d", previous, scaled.DesiredReplicas)
 	}
 
-	if _, err := store.Scale("frontend", -1, now); err != ErrInvalidReplicas {
+	if _, _, err := store.Scale("frontend", -1, now); err != ErrInvalidReplicas {
 		t.Fatalf("expected ErrInvalidReplicas, got %v", err)
 	}
 
@@ -53,7 +54,490 @@ func TestGetAndScale(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 
-	if _, err := store.Scale("missing", 2, now); err != ErrNotFound {
+	if _, _, err := store.Scale("missing", 2, now); err != ErrNotFound {
 		t.Fatalf("expected ErrNotFound on scale, got %v", err)
 	}
 }
+
+func TestPreviewScaleDoesNotMutate(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	preview, err := store.PreviewScale("frontend", 200, now)
+	if err != nil {
+		t.Fatalf("preview scale: %v", err)
+	}
+
+	if !preview.Valid || preview.Projected.DesiredReplicas != 200 || preview.Projected.Status != "Updating" {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	invalid, err := store.PreviewScale("frontend", 201, now)
+	if err != nil {
+		t.Fatalf("preview invalid scale: %v", err)
+	}
+
+	if invalid.Valid || invalid.Reason == "" {
+		t.Fatalf("expected invalid preview with reason, got %+v", invalid)
+	}
+
+	detail, err := store.Get("frontend", now)
+	if err != nil {
+		t.Fatalf("get deployment detail: %v", err)
+	}
+
+	if detail.DesiredReplicas != 4 || detail.Revision != 7 {
+		t.Fatalf("expected deployment untouched, got desired %d revision %d", detail.DesiredReplicas, detail.Revision)
+	}
+
+	if _, err := store.PreviewScale("missing", 1, now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateRollingUpdate(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, err := store.Get("frontend", now)
+	if err != nil {
+		t.Fatalf("get deployment detail: %v", err)
+	}
+	if detail.RollingUpdate == nil || detail.RollingUpdate.MaxSurge != "25%" {
+		t.Fatalf("expected seeded maxSurge 25%%, got %+v", detail.RollingUpdate)
+	}
+
+	updated, _, err := store.UpdateRollingUpdate("frontend", RollingUpdate{MaxSurge: "2"}, now)
+	if err != nil {
+		t.Fatalf("update rolling update: %v", err)
+	}
+	if updated.RollingUpdate.MaxSurge != "2" || updated.RollingUpdate.MaxUnavailable != "25%" {
+		t.Fatalf("unexpected rolling update %+v", updated.RollingUpdate)
+	}
+
+	if _, _, err := store.UpdateRollingUpdate("frontend", RollingUpdate{MaxSurge: "150%"}, now); err != ErrInvalidStrategy {
+		t.Fatalf("expected ErrInvalidStrategy for 150%%, got %v", err)
+	}
+
+	if _, _, err := store.UpdateRollingUpdate("frontend", RollingUpdate{MaxSurge: "0", MaxUnavailable: "0%"}, now); err != ErrInvalidStrategy {
+		t.Fatalf("expected ErrInvalidStrategy for zero limits, got %v", err)
+	}
+
+	batch, err := store.Get("batch-jobs", now)
+	if err != nil {
+		t.Fatalf("get batch-jobs: %v", err)
+	}
+	if batch.RollingUpdate != nil {
+		t.Fatalf("expected Recreate deployment without rollingUpdate")
+	}
+	if _, _, err := store.UpdateRollingUpdate("batch-jobs", RollingUpdate{MaxSurge: "1"}, now); err != ErrInvalidStrategy {
+		t.Fatalf("expected ErrInvalidStrategy for Recreate, got %v", err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	status, err := store.GetStatus("backend", now)
+	if err != nil {
+		t.Fatalf("get deployment status: %v", err)
+	}
+	if status.ReadyReplicas != 5 || status.DesiredReplicas != 6 || status.ObservedGeneration != 21 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if len(status.Conditions) != 1 || status.Conditions[0].Type != "Progressing" {
+		t.Fatalf("unexpected conditions %+v", status.Conditions)
+	}
+
+	if _, err := store.GetStatus("missing", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAutoscalerReconcile(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if _, _, err := store.SetAutoscaler("frontend", AutoscalerSpec{Min: 5, Max: 2, TargetCPU: 70}, now); err != ErrInvalidAutoscaler {
+		t.Fatalf("expected ErrInvalidAutoscaler, got %v", err)
+	}
+
+	hpa, previous, err := store.SetAutoscaler("frontend", AutoscalerSpec{Min: 2, Max: 10, TargetCPU: 70}, now)
+	if err != nil || previous != nil {
+		t.Fatalf("set autoscaler: %v, replaced %v", err, previous)
+	}
+	if hpa.CurrentReplicas != 4 || hpa.CurrentCPU != defaultCPULoad {
+		t.Fatalf("unexpected initial autoscaler %+v", hpa)
+	}
+
+	if err := store.SetCPULoad("frontend", 140); err != nil {
+		t.Fatalf("set cpu load: %v", err)
+	}
+
+	// Reads reconcile lazily against the clock they are given.
+	if detail, _ := store.Get("frontend", now.Add(5*time.Second)); detail.DesiredReplicas != 4 {
+		t.Fatalf("expected no scaling before sync period, got %d", detail.DesiredReplicas)
+	}
+
+	detail, err := store.Get("frontend", now.Add(15*time.Second))
+	if err != nil {
+		t.Fatalf("get deployment: %v", err)
+	}
+	if detail.DesiredReplicas != 8 {
+		t.Fatalf("expected 8 replicas, got %d", detail.DesiredReplicas)
+	}
+
+	if err := store.SetCPULoad("frontend", 400); err != nil {
+		t.Fatalf("set cpu load: %v", err)
+	}
+	hpa, err = store.GetAutoscaler("frontend", now.Add(30*time.Second))
+	if err != nil {
+		t.Fatalf("get autoscaler: %v", err)
+	}
+	if hpa.CurrentReplicas != 10 {
+		t.Fatalf("expected replicas capped at max 10, got %d", hpa.CurrentReplicas)
+	}
+
+	if _, err := store.GetAutoscaler("backend", now); err != ErrNoAutoscaler {
+		t.Fatalf("expected ErrNoAutoscaler, got %v", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	spec := CreateSpec{
+		Name:       "worker",
+		Replicas:   2,
+		Containers: []Container{{Name: "worker", Image: "registry.local/worker:1.0.0"}},
+	}
+	detail, err := store.Create(spec, now)
+	if err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	if detail.Namespace != "default" || detail.Strategy != "RollingUpdate" || detail.RollingUpdate == nil {
+		t.Fatalf("expected defaults to apply, got %+v", detail)
+	}
+	if detail.DesiredReplicas != 2 || detail.Revision != 1 || detail.Status != "Healthy" {
+		t.Fatalf("unexpected created deployment %+v", detail)
+	}
+	if _, err := store.Get("worker", now); err != nil {
+		t.Fatalf("expected created deployment to be retrievable: %v", err)
+	}
+
+	if _, err := store.Create(spec, now); err != ErrExists {
+		t.Fatalf("expected ErrExists, got %v", err)
+	}
+
+	// The same name in another namespace is allowed, but name-only lookups
+	// can no longer pick one.
+	staging := spec
+	staging.Namespace = "staging"
+	if _, err := store.Create(staging, now); err != nil {
+		t.Fatalf("create deployment in staging: %v", err)
+	}
+	if _, err := store.Get("worker", now); err != ErrAmbiguous {
+		t.Fatalf("expected ErrAmbiguous, got %v", err)
+	}
+	if _, _, err := store.Scale("worker", 3, now); err != ErrAmbiguous {
+		t.Fatalf("expected ErrAmbiguous from Scale, got %v", err)
+	}
+	if detail, err := store.GetNamespaced("staging", "worker", now); err != nil || detail.Namespace != "staging" {
+		t.Fatalf("expected the staging copy, got %+v, %v", detail, err)
+	}
+
+	spec.Name = "worker-big"
+	spec.Replicas = 201
+	if _, err := store.Create(spec, now); err != ErrInvalidReplicas {
+		t.Fatalf("expected ErrInvalidReplicas, got %v", err)
+	}
+	spec.Replicas = 1
+	spec.Strategy = "BlueGreen"
+	if _, err := store.Create(spec, now); err != ErrInvalidStrategy {
+		t.Fatalf("expected ErrInvalidStrategy, got %v", err)
+	}
+	if _, err := store.Create(CreateSpec{Name: "empty"}, now); err != ErrInvalidSpec {
+		t.Fatalf("expected ErrInvalidSpec, got %v", err)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if _, _, err := store.Rollback("frontend", 0, now); err != ErrUnknownRevision {
+		t.Fatalf("expected ErrUnknownRevision without history, got %v", err)
+	}
+
+	if _, _, err := store.Scale("frontend", 6, now); err != nil {
+		t.Fatalf("scale: %v", err)
+	}
+	later := now.Add(time.Minute)
+	detail, _, err := store.Rollback("frontend", 0, later)
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if detail.DesiredReplicas != 4 || detail.Revision != 9 || detail.LastUpdated != later.Format(time.RFC3339) {
+		t.Fatalf("unexpected rollback result %+v", detail)
+	}
+
+	detail, _, err = store.Rollback("frontend", 8, later)
+	if err != nil {
+		t.Fatalf("rollback to revision 8: %v", err)
+	}
+	if detail.DesiredReplicas != 6 || detail.Revision != 10 {
+		t.Fatalf("unexpected rollback to revision 8 %+v", detail)
+	}
+
+	if _, _, err := store.Rollback("frontend", 99, later); err != ErrUnknownRevision {
+		t.Fatalf("expected ErrUnknownRevision, got %v", err)
+	}
+	if _, _, err := store.Rollback("missing", 0, later); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	a, err := store.GetNamespaced("default", "frontend", now)
+	if err != nil {
+		t.Fatalf("get default/frontend: %v", err)
+	}
+	if _, err := store.GetNamespaced("prod", "frontend", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if cmp := Compare(a, a); !cmp.Identical || len(cmp.Differences) != 0 {
+		t.Fatalf("expected identical comparison, got %+v", cmp)
+	}
+
+	b := a
+	b.Namespace = "prod"
+	b.Images = []string{"registry.local/frontend:2.4.0"}
+	cmp := Compare(a, b)
+	if cmp.Identical || len(cmp.Differences) != 1 || cmp.Differences[0].Field != "images" {
+		t.Fatalf("expected single image difference, got %+v", cmp)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, err := store.Restart("frontend", now)
+	if err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if detail.Revision != 8 || detail.UpdatedReplicas != 0 || detail.Status != "Updating" {
+		t.Fatalf("unexpected restart result %+v", detail)
+	}
+	var progressing *Condition
+	for i := range detail.Conditions {
+		if detail.Conditions[i].Type == "Progressing" {
+			progressing = &detail.Conditions[i]
+		}
+	}
+	if progressing == nil || progressing.Message != "restarted" {
+		t.Fatalf("expected Progressing condition, got %+v", detail.Conditions)
+	}
+
+	for _, got := range store.List(now.Add(2 * rolloutStepInterval)) {
+		if got.Name == "frontend" && got.UpdatedReplicas != 2 {
+			t.Fatalf("expected List to reconcile the rollout, got %+v", got)
+		}
+	}
+	if got, _ := store.Get("frontend", now.Add(2*rolloutStepInterval)); got.UpdatedReplicas != 2 || got.Status != "Updating" {
+		t.Fatalf("expected 2 updated replicas mid-rollout, got %+v", got.Summary)
+	}
+	if got, _ := store.Get("frontend", now.Add(10*rolloutStepInterval)); got.UpdatedReplicas != 4 || got.Status != "Healthy" {
+		t.Fatalf("expected rollout to complete, got %+v", got.Summary)
+	}
+
+	if _, err := store.Restart("missing", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestScaleWhilePaused(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, _, err := store.SetPaused("frontend", true, now)
+	if err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if !detail.Paused || detail.Status != "Paused" {
+		t.Fatalf("expected paused deployment, got %+v", detail)
+	}
+
+	detail, _, err = store.Scale("frontend", 2, now)
+	if err != nil {
+		t.Fatalf("scale while paused: %v", err)
+	}
+	if detail.DesiredReplicas != 2 || detail.ReadyReplicas != 4 || detail.UpdatedReplicas != 4 || detail.Status != "Paused" {
+		t.Fatalf("expected frozen progress while paused, got %+v", detail.Summary)
+	}
+
+	detail, _, err = store.SetPaused("frontend", false, now)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if detail.Paused || detail.ReadyReplicas != 2 || detail.UpdatedReplicas != 2 || detail.Status != "Healthy" {
+		t.Fatalf("expected resume to reconcile to desired, got %+v", detail)
+	}
+
+	if _, _, err := store.SetPaused("missing", true, now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSetImage(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, previous, err := store.SetImage("backend", "api", "registry.local/backend:1.13.0", now)
+	if err != nil {
+		t.Fatalf("set image: %v", err)
+	}
+	if previous == "" || previous == "registry.local/backend:1.13.0" {
+		t.Fatalf("expected the replaced image, got %q", previous)
+	}
+	if detail.Containers[0].Image != "registry.local/backend:1.13.0" || detail.Images[0] != "registry.local/backend:1.13.0" {
+		t.Fatalf("expected image to be updated, got %+v", detail)
+	}
+	if detail.Revision != 22 {
+		t.Fatalf("expected revision 22, got %d", detail.Revision)
+	}
+
+	if _, _, err := store.SetImage("backend", "sidecar", "busybox", now); err != ErrUnknownContainer {
+		t.Fatalf("expected ErrUnknownContainer, got %v", err)
+	}
+	if _, _, err := store.SetImage("missing", "api", "busybox", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	for i := 1; i <= 12; i++ {
+		if _, _, err := store.Scale("frontend", i, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("scale: %v", err)
+		}
+	}
+
+	revisions, err := store.Revisions("frontend")
+	if err != nil {
+		t.Fatalf("revisions: %v", err)
+	}
+	if len(revisions) != revisionHistoryLimit {
+		t.Fatalf("expected %d retained revisions, got %d", revisionHistoryLimit, len(revisions))
+	}
+	last := revisions[len(revisions)-1]
+	if revisions[0].Revision != 10 || last.Revision != 19 || last.Replicas != 12 {
+		t.Fatalf("unexpected revision window %+v .. %+v", revisions[0], last)
+	}
+	if last.Timestamp != now.Add(12*time.Minute).Format(time.RFC3339) || last.Strategy != "RollingUpdate" {
+		t.Fatalf("unexpected latest revision %+v", last)
+	}
+
+	if _, err := store.Revisions("missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResourceTotalsFollowScale(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, err := store.Get("frontend", now)
+	if err != nil {
+		t.Fatalf("get deployment detail: %v", err)
+	}
+	if got := detail.ResourceTotals.Requests.CPUMillis; got != 1000 {
+		t.Fatalf("expected 1000m CPU requested across 4 replicas, got %d", got)
+	}
+
+	scaled, _, err := store.Scale("frontend", 8, now)
+	if err != nil {
+		t.Fatalf("scale deployment: %v", err)
+	}
+	if got := scaled.ResourceTotals.Requests.CPUMillis; got != 2*detail.ResourceTotals.Requests.CPUMillis {
+		t.Fatalf("expected scaling to double the CPU request, got %d", got)
+	}
+	if got := scaled.ResourceTotals.Limits.MemoryMiB; got != 8*512 {
+		t.Fatalf("expected %d MiB memory limit, got %d", 8*512, got)
+	}
+}
+
+func TestNamespaceResources(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	spec := CreateSpec{
+		Name:      "frontend",
+		Namespace: "shadow",
+		Replicas:  3,
+		Containers: []Container{{
+			Name:      "web",
+			Image:     "registry.local/web:1.0.0",
+			Resources: Resources{Requests: ResourceList{CPUMillis: 100, MemoryMiB: 64}, Limits: ResourceList{CPUMillis: 200, MemoryMiB: 128}},
+		}},
+	}
+	if _, err := store.Create(spec, now); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+
+	got := store.NamespaceResources("shadow", now)
+	want := Resources{Requests: ResourceList{CPUMillis: 300, MemoryMiB: 192}, Limits: ResourceList{CPUMillis: 600, MemoryMiB: 384}}
+	if got != want {
+		t.Fatalf("expected only the shadow deployment to count, got %+v", got)
+	}
+	if empty := store.NamespaceResources("ghost", now); empty != (Resources{}) {
+		t.Fatalf("expected zero resources for an empty namespace, got %+v", empty)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	steady, err := store.Recommend("frontend")
+	if err != nil {
+		t.Fatalf("recommend: %v", err)
+	}
+	if steady.RecommendedReplicas != steady.CurrentReplicas || steady.Rationale == "" {
+		t.Fatalf("expected fully ready frontend to keep its replicas, got %+v", steady)
+	}
+
+	if _, _, err := store.SetAutoscaler("backend", AutoscalerSpec{Min: 1, Max: 20, TargetCPU: 60}, now); err != nil {
+		t.Fatalf("set autoscaler: %v", err)
+	}
+	if err := store.SetCPULoad("backend", 90); err != nil {
+		t.Fatalf("set cpu load: %v", err)
+	}
+	hot, err := store.Recommend("backend")
+	if err != nil {
+		t.Fatalf("recommend: %v", err)
+	}
+	if hot.CPU != 90 || hot.RecommendedReplicas <= hot.CurrentReplicas {
+		t.Fatalf("expected a scale-up recommendation, got %+v", hot)
+	}
+
+	idle, err := store.Recommend("batch-jobs")
+	if err != nil {
+		t.Fatalf("recommend: %v", err)
+	}
+	if idle.RecommendedReplicas != idle.CurrentReplicas || !strings.Contains(idle.Rationale, "No replicas are ready") {
+		t.Fatalf("expected unready batch-jobs to keep its replicas, got %+v", idle)
+	}
+
+	if _, err := store.Recommend("missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
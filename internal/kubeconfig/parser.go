@@ -3,6 +3,7 @@ package kubeconfig
 import (
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
 	"time"
 
@@ -25,6 +26,15 @@ func Parse(r io.Reader, now time.Time) (Summary, error) {
 				User    string `yaml:"user"`
 			} `yaml:"context"`
 		} `yaml:"contexts"`
+		Users []struct {
+			Name string `yaml:"name"`
+			User struct {
+				Token                 string `yaml:"token"`
+				ClientCertificateData string `yaml:"client-certificate-data"`
+				Username              string `yaml:"username"`
+				Password              string `yaml:"password"`
+			} `yaml:"user"`
+		} `yaml:"users"`
 		CurrentContext string `yaml:"current-context"`
 	}
 
@@ -39,12 +49,34 @@ func Parse(r io.Reader, now time.Time) (Summary, error) {
 
 	clusters := make([]Cluster, 0, len(cfg.Clusters))
 	for _, c := range cfg.Clusters {
-		clusters = append(clusters, Cluster{Name: strings.TrimSpace(c.Name), Server: strings.TrimSpace(c.Cluster.Server)})
+		cluster := Cluster{Name: strings.TrimSpace(c.Name), Server: strings.TrimSpace(c.Cluster.Server)}
+		if err := validateServer(cluster.Server); err != nil {
+			return Summary{}, fmt.Errorf("cluster %q 的 server 地址无效: %w", cluster.Name, err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	users := make([]User, 0, len(cfg.Users))
+	methods := make(map[string]string, len(cfg.Users))
+	for _, u := range cfg.Users {
+		method := AuthNone
+		switch {
+		case u.User.Token != "":
+			method = AuthToken
+		case u.User.ClientCertificateData != "":
+			method = AuthClientCert
+		case u.User.Username != "" && u.User.Password != "":
+			method = AuthBasic
+		}
+		name := strings.TrimSpace(u.Name)
+		users = append(users, User{Name: name, AuthMethod: method})
+		methods[name] = method
 	}
 
 	contexts := make([]Context, 0, len(cfg.Contexts))
 	for _, c := range cfg.Contexts {
-		contexts = append(contexts, Context{Name: strings.TrimSpace(c.Name), Cluster: strings.TrimSpace(c.Context.Cluster), User: strings.TrimSpace(c.Context.User)})
+		user := strings.TrimSpace(c.Context.User)
+		contexts = append(contexts, Context{Name: strings.TrimSpace(c.Name), Cluster: strings.TrimSpace(c.Context.Cluster), User: user, AuthMethod: methods[user]})
 	}
 
 	name := cfg.CurrentContext
@@ -56,7 +88,26 @@ func Parse(r io.Reader, now time.Time) (Summary, error) {
 		Name:           strings.TrimSpace(name),
 		Clusters:       clusters,
 		Contexts:       contexts,
+		Users:          users,
 		CurrentContext: strings.TrimSpace(cfg.CurrentContext),
 		ImportedAt:     now,
 	}, nil
 }
+
+// validateServer accepts absolute http and https URLs with a host.
+func validateServer(server string) error {
+	if server == "" {
+		return fmt.Errorf("server 不能为空")
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q 必须使用 http 或 https 协议", server)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q 缺少主机地址", server)
+	}
+	return nil
+}
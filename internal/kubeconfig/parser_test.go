@@ -1,6 +1,8 @@
 package kubeconfig
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -38,9 +40,222 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseUserAuthMethods(t *testing.T) {
+	const mixed = `apiVersion: v1
+clusters:
+- cluster:
+    server: https://example.com
+  name: prod
+contexts:
+- context: {cluster: prod, user: robot}
+  name: ci
+- context: {cluster: prod, user: admin}
+  name: ops
+- context: {cluster: prod, user: dev}
+  name: dev
+- context: {cluster: prod, user: ghost}
+  name: orphan
+users:
+- name: robot
+  user:
+    token: s3cr3t-token
+- name: admin
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+- name: dev
+  user:
+    username: dev
+    password: hunter2
+`
+	summary, err := Parse(strings.NewReader(mixed), time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if len(summary.Users) != 3 {
+		t.Fatalf("expected 3 users, got %+v", summary.Users)
+	}
+
+	want := map[string]string{"ci": AuthToken, "ops": AuthClientCert, "dev": AuthBasic, "orphan": ""}
+	for _, c := range summary.Contexts {
+		if c.AuthMethod != want[c.Name] {
+			t.Fatalf("context %s: expected auth method %q, got %q", c.Name, want[c.Name], c.AuthMethod)
+		}
+	}
+
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("marshal summary: %v", err)
+	}
+	for _, secret := range []string{"s3cr3t-token", "Y2VydA==", "hunter2"} {
+		if strings.Contains(string(raw), secret) {
+			t.Fatalf("summary leaked credential %q: %s", secret, raw)
+		}
+	}
+}
+
+func TestSummaryToYAMLRoundTrip(t *testing.T) {
+	now := time.Unix(0, 0)
+	summary, err := Parse(strings.NewReader(sample), now)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	raw, err := summary.ToYAML()
+	if err != nil {
+		t.Fatalf("to yaml: %v", err)
+	}
+
+	again, err := Parse(strings.NewReader(string(raw)), now)
+	if err != nil {
+		t.Fatalf("reparse failed: %v", err)
+	}
+
+	if again.CurrentContext != "prod-context" || again.Clusters[0].Name != "prod" || again.Contexts[0].Name != "prod-context" {
+		t.Fatalf("round trip mismatch: %+v", again)
+	}
+
+	if strings.Contains(string(raw), "users") {
+		t.Fatalf("expected no users section, got:\n%s", raw)
+	}
+}
+
 func TestParseMissingClusters(t *testing.T) {
 	_, err := Parse(strings.NewReader("apiVersion: v1"), time.Now())
 	if err == nil {
 		t.Fatalf("expected error for empty kubeconfig")
 	}
 }
+
+func TestParseInvalidServer(t *testing.T) {
+	const tmpl = `apiVersion: v1
+clusters:
+- cluster:
+    server: https://example.com
+  name: prod
+- cluster:
+    server: %s
+  name: broken
+`
+	for _, server := range []string{"ftp://example.com", "not a url", `""`, "https://"} {
+		_, err := Parse(strings.NewReader(fmt.Sprintf(tmpl, server)), time.Now())
+		if err == nil {
+			t.Fatalf("expected error for server %s", server)
+		}
+		if !strings.Contains(err.Error(), `"broken"`) {
+			t.Fatalf("expected error to name the cluster, got %v", err)
+		}
+	}
+}
+
+func TestSummaryMerge(t *testing.T) {
+	base := Summary{
+		Name:           "prod-context",
+		Clusters:       []Cluster{{Name: "prod", Server: "https://old.example.com"}},
+		Contexts:       []Context{{Name: "prod-context", Cluster: "prod", User: "admin"}},
+		CurrentContext: "prod-context",
+	}
+	other := Summary{
+		Clusters: []Cluster{{Name: "prod", Server: "https://new.example.com"}, {Name: "dev", Server: "https://dev.example.com"}},
+		Contexts: []Context{{Name: "dev-context", Cluster: "dev", User: "dev"}},
+	}
+
+	merged := base.Merge(other)
+	if merged.Name != "prod-context" || merged.CurrentContext != "prod-context" {
+		t.Fatalf("unexpected identity after merge: %+v", merged)
+	}
+	if len(merged.Clusters) != 2 || merged.Clusters[0].Server != "https://new.example.com" {
+		t.Fatalf("expected newest cluster to win, got %+v", merged.Clusters)
+	}
+	if len(merged.Contexts) != 2 || merged.Contexts[1].Name != "dev-context" {
+		t.Fatalf("expected appended context, got %+v", merged.Contexts)
+	}
+	if base.Clusters[0].Server != "https://old.example.com" {
+		t.Fatalf("merge must not modify the receiver")
+	}
+}
+
+func TestMergeFirstWins(t *testing.T) {
+	a := Summary{
+		Name:     "a",
+		Clusters: []Cluster{{Name: "prod", Server: "https://a.example.com"}},
+		Contexts: []Context{{Name: "prod-admin", Cluster: "prod"}},
+	}
+	b := Summary{
+		Name:           "b",
+		Clusters:       []Cluster{{Name: "prod", Server: "https://b.example.com"}, {Name: "dev", Server: "https://dev.example.com"}},
+		Contexts:       []Context{{Name: "dev-admin", Cluster: "dev"}},
+		CurrentContext: "dev-admin",
+	}
+	c := Summary{CurrentContext: "prod-admin"}
+
+	merged := Merge(a, b, c)
+	if merged.Name != "a" || merged.CurrentContext != "dev-admin" {
+		t.Fatalf("unexpected name or current context: %+v", merged)
+	}
+	if len(merged.Clusters) != 2 || merged.Clusters[0].Server != "https://a.example.com" || merged.Clusters[1].Name != "dev" {
+		t.Fatalf("unexpected clusters: %+v", merged.Clusters)
+	}
+	if len(merged.Contexts) != 2 {
+		t.Fatalf("unexpected contexts: %+v", merged.Contexts)
+	}
+	if len(a.Clusters) != 1 {
+		t.Fatalf("expected inputs to be left untouched, got %+v", a.Clusters)
+	}
+
+	conflicts := ClusterConflicts(a, b, c)
+	if len(conflicts) != 1 || !strings.Contains(conflicts[0], `"prod"`) {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+}
+
+func TestStoreDeleteNewestFirst(t *testing.T) {
+	store := NewStore()
+	store.Add(Summary{Name: "prod", CurrentContext: "old"})
+	store.Add(Summary{Name: "prod", CurrentContext: "new"})
+
+	// Imports sharing a name are removed newest first.
+	if !store.Delete("prod") {
+		t.Fatalf("expected delete to succeed")
+	}
+	remaining, ok := store.Get("prod")
+	if !ok || remaining.CurrentContext != "old" {
+		t.Fatalf("expected the older import to remain, got %+v", remaining)
+	}
+	if !store.Delete("prod") || store.Delete("prod") {
+		t.Fatalf("expected exactly two deletions")
+	}
+	if len(store.List()) != 0 {
+		t.Fatalf("expected empty store")
+	}
+}
+
+func TestStoreSetActive(t *testing.T) {
+	store := NewStore()
+	store.Add(Summary{Name: "prod"})
+	store.Add(Summary{Name: "staging"})
+
+	if err := store.SetActive("prod"); err != nil {
+		t.Fatalf("activate prod: %v", err)
+	}
+	if err := store.SetActive("staging"); err != nil {
+		t.Fatalf("activate staging: %v", err)
+	}
+	active := 0
+	for _, item := range store.List() {
+		if item.Active {
+			active++
+			if item.Name != "staging" {
+				t.Fatalf("expected staging to be active, got %s", item.Name)
+			}
+		}
+	}
+	if active != 1 {
+		t.Fatalf("expected exactly one active import, got %d", active)
+	}
+
+	if err := store.SetActive("missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
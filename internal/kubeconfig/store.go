@@ -1,17 +1,27 @@
 package kubeconfig
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
 
+// ErrNotFound indicates no import with the requested name exists.
+var ErrNotFound = errors.New("kubeconfig import not found")
+
 // Summary describes an imported kubeconfig resource.
 type Summary struct {
 	Name           string    `json:"name"`
 	Clusters       []Cluster `json:"clusters"`
 	Contexts       []Context `json:"contexts"`
+	Users          []User    `json:"users"`
 	CurrentContext string    `json:"currentContext"`
 	ImportedAt     time.Time `json:"importedAt"`
+
+	// Active marks the import the dashboard treats as the current cluster.
+	// At most one import is active at a time.
+	Active bool `json:"active"`
 }
 
 // Cluster captures minimal cluster information from kubeconfig.
@@ -25,6 +35,120 @@ type Context struct {
 	Name    string `json:"name"`
 	Cluster string `json:"cluster"`
 	User    string `json:"user"`
+
+	// AuthMethod is copied from the matching User, or empty when the context
+	// references a user the kubeconfig does not define.
+	AuthMethod string `json:"authMethod,omitempty"`
+}
+
+// Authentication methods reported on User. Only the kind of credential is
+// recorded; the secret itself is discarded during parsing.
+const (
+	AuthToken      = "token"
+	AuthClientCert = "client-certificate"
+	AuthBasic      = "basic"
+	AuthNone       = "none"
+)
+
+// User captures a kubeconfig user entry without its credentials.
+type User struct {
+	Name       string `json:"name"`
+	AuthMethod string `json:"authMethod"`
+}
+
+// Merge folds the clusters, contexts and users of other into s, the way
+// kubectl config merges files. Entries are matched by name and the ones from
+// other win; new entries are appended. A current context set in other
+// replaces the existing one.
+func (s Summary) Merge(other Summary) Summary {
+	out := s
+	out.Clusters = mergeByName(s.Clusters, other.Clusters, func(c Cluster) string { return c.Name })
+	out.Contexts = mergeByName(s.Contexts, other.Contexts, func(c Context) string { return c.Name })
+	out.Users = mergeByName(s.Users, other.Users, func(u User) string { return u.Name })
+	if other.CurrentContext != "" {
+		out.CurrentContext = other.CurrentContext
+	}
+	return out
+}
+
+func mergeByName[T any](base, extra []T, name func(T) string) []T {
+	out := make([]T, len(base), len(base)+len(extra))
+	copy(out, base)
+	index := make(map[string]int, len(out))
+	for i, item := range out {
+		index[name(item)] = i
+	}
+	for _, item := range extra {
+		if i, ok := index[name(item)]; ok {
+			out[i] = item
+			continue
+		}
+		index[name(item)] = len(out)
+		out = append(out, item)
+	}
+	return out
+}
+
+// Merge unions several kubeconfigs into one summary, as when a user uploads
+// multiple files at once. Unlike Summary.Merge, the first occurrence of a
+// cluster, context or user name wins, and the current context comes from the
+// first summary that sets one. Name and ImportedAt are taken from the first
+// summary.
+func Merge(summaries ...Summary) Summary {
+	if len(summaries) == 0 {
+		return Summary{}
+	}
+
+	out := summaries[0]
+	out.Clusters, out.Contexts, out.Users = nil, nil, nil
+	out.CurrentContext = ""
+	for _, sum := range summaries {
+		out.Clusters = appendNewByName(out.Clusters, sum.Clusters, func(c Cluster) string { return c.Name })
+		out.Contexts = appendNewByName(out.Contexts, sum.Contexts, func(c Context) string { return c.Name })
+		out.Users = appendNewByName(out.Users, sum.Users, func(u User) string { return u.Name })
+		if out.CurrentContext == "" {
+			out.CurrentContext = sum.CurrentContext
+		}
+	}
+	return out
+}
+
+// ClusterConflicts describes every cluster name that several summaries define
+// with different servers, in the order the names first appear. Merge keeps
+// the first server for each of them.
+func ClusterConflicts(summaries ...Summary) []string {
+	var conflicts []string
+	first := make(map[string]string)
+	reported := make(map[string]bool)
+	for _, sum := range summaries {
+		for _, c := range sum.Clusters {
+			server, ok := first[c.Name]
+			if !ok {
+				first[c.Name] = c.Server
+				continue
+			}
+			if server != c.Server && !reported[c.Name] {
+				reported[c.Name] = true
+				conflicts = append(conflicts, fmt.Sprintf("cluster %q 在多个文件中的 server 不一致，已保留 %s", c.Name, server))
+			}
+		}
+	}
+	return conflicts
+}
+
+func appendNewByName[T any](base, extra []T, name func(T) string) []T {
+	seen := make(map[string]bool, len(base))
+	for _, item := range base {
+		seen[name(item)] = true
+	}
+	for _, item := range extra {
+		if seen[name(item)] {
+			continue
+		}
+		seen[name(item)] = true
+		base = append(base, item)
+	}
+	return base
 }
 
 // Store keeps track of imported kubeconfig summaries.
@@ -48,11 +172,77 @@ func (s *Store) List() []Summary {
 	return result
 }
 
-// Add stores a new kubeconfig summary.
+// Get returns the most recently imported summary with the given name.
+func (s *Store) Get(name string) (Summary, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, item := range s.items {
+		if item.Name == name {
+			return item, true
+		}
+	}
+	return Summary{}, false
+}
+
+// Add stores a new kubeconfig summary. New imports start inactive.
 func (s *Store) Add(summary Summary) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	summary.Active = false
 	// prepend to keep newest first
 	s.items = append([]Summary{summary}, s.items...)
 }
+
+// Merge folds other into the most recently imported summary with the given
+// name and returns the result. It reports false when no such import exists.
+func (s *Store) Merge(name string, other Summary) (Summary, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range s.items {
+		if item.Name == name {
+			s.items[i] = item.Merge(other)
+			return s.items[i], true
+		}
+	}
+	return Summary{}, false
+}
+
+// Delete removes the most recently imported summary with the given name,
+// leaving older imports that share it. It reports whether one was removed.
+func (s *Store) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range s.items {
+		if item.Name == name {
+			s.items = append(s.items[:i:i], s.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetActive makes the most recently imported summary with the given name the
+// only active one.
+func (s *Store) SetActive(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target := -1
+	for i, item := range s.items {
+		if item.Name == name {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return ErrNotFound
+	}
+	for i := range s.items {
+		s.items[i].Active = i == target
+	}
+	return nil
+}
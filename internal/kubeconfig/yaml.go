@@ -0,0 +1,55 @@
+package kubeconfig
+
+import "gopkg.in/yaml.v3"
+
+type yamlConfig struct {
+	APIVersion     string        `yaml:"apiVersion"`
+	Kind           string        `yaml:"kind"`
+	Clusters       []yamlCluster `yaml:"clusters"`
+	Contexts       []yamlContext `yaml:"contexts"`
+	CurrentContext string        `yaml:"current-context,omitempty"`
+}
+
+type yamlCluster struct {
+	Name    string `yaml:"name"`
+	Cluster struct {
+		Server string `yaml:"server"`
+	} `yaml:"cluster"`
+}
+
+type yamlContext struct {
+	Name    string `yaml:"name"`
+	Context struct {
+		Cluster string `yaml:"cluster"`
+		User    string `yaml:"user,omitempty"`
+	} `yaml:"context"`
+}
+
+// ToYAML reconstructs a minimal kubeconfig from the summary. Only clusters,
+// contexts and the current context are emitted; credentials are never stored
+// on import, so the output has no users section and cannot authenticate on
+// its own.
+func (s Summary) ToYAML() ([]byte, error) {
+	cfg := yamlConfig{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		Clusters:       make([]yamlCluster, 0, len(s.Clusters)),
+		Contexts:       make([]yamlContext, 0, len(s.Contexts)),
+		CurrentContext: s.CurrentContext,
+	}
+
+	for _, c := range s.Clusters {
+		item := yamlCluster{Name: c.Name}
+		item.Cluster.Server = c.Server
+		cfg.Clusters = append(cfg.Clusters, item)
+	}
+
+	for _, c := range s.Contexts {
+		item := yamlContext{Name: c.Name}
+		item.Context.Cluster = c.Cluster
+		item.Context.User = c.User
+		cfg.Contexts = append(cfg.Contexts, item)
+	}
+
+	return yaml.Marshal(cfg)
+}
@@ -0,0 +1,70 @@
+// Package label implements the Kubernetes label syntax and the key=value
+// selectors shared by the namespace and pod stores.
+package label
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidSelector signals a malformed label selector.
+var ErrInvalidSelector = errors.New("invalid label selector")
+
+var (
+	nameRegex   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	prefixRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// ParseSelector parses a comma-separated key=value selector into its
+// requirements. An empty selector yields no requirements.
+func ParseSelector(raw string) (map[string]string, error) {
+	reqs := make(map[string]string)
+	if strings.TrimSpace(raw) == "" {
+		return reqs, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !ok || strings.Contains(value, "=") || !ValidKey(key) || !ValidValue(value) {
+			return nil, ErrInvalidSelector
+		}
+		reqs[key] = value
+	}
+	return reqs, nil
+}
+
+// Matches reports whether labels satisfy every requirement in reqs.
+func Matches(labels, reqs map[string]string) bool {
+	for k, v := range reqs {
+		actual, ok := labels[k]
+		if !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidKey reports whether key is a valid label key: an optional DNS
+// subdomain prefix and a name of at most 63 characters.
+func ValidKey(key string) bool {
+	name := key
+	if idx := strings.LastIndex(key, "/"); idx >= 0 {
+		prefix := key[:idx]
+		name = key[idx+1:]
+		if prefix == "" || len(prefix) > 253 || !prefixRegex.MatchString(prefix) {
+			return false
+		}
+	}
+	return name != "" && len(name) <= 63 && nameRegex.MatchString(name)
+}
+
+// ValidValue reports whether value is a valid, possibly empty, label value.
+func ValidValue(value string) bool {
+	if value == "" {
+		return true
+	}
+	return len(value) <= 63 && nameRegex.MatchString(value)
+}
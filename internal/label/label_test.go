@@ -0,0 +1,40 @@
+package label
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{raw: "", want: map[string]string{}},
+		{raw: "app=web", want: map[string]string{"app": "web"}},
+		{raw: " app = web , tier=", want: map[string]string{"app": "web", "tier": ""}},
+		{raw: "example.com/team=core", want: map[string]string{"example.com/team": "core"}},
+		{raw: "app", wantErr: true},
+		{raw: "app=a=b", wantErr: true},
+		{raw: "-app=web", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSelector(tt.raw)
+		if tt.wantErr {
+			if err != ErrInvalidSelector {
+				t.Errorf("ParseSelector(%q) error = %v, want ErrInvalidSelector", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSelector(%q) = %v, %v, want %v", tt.raw, got, err, tt.want)
+		}
+	}
+
+	labels := map[string]string{"app": "web", "tier": "frontend"}
+	if !Matches(labels, map[string]string{"app": "web"}) || Matches(labels, map[string]string{"app": "api"}) {
+		t.Fatal("unexpected Matches result")
+	}
+}
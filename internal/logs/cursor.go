@@ -0,0 +1,46 @@
+package logs
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a log cursor cannot be decoded or no
+// longer points at a stored line.
+var ErrInvalidCursor = errors.New("invalid log cursor")
+
+// LogPage is one page of ListLogsPage results.
+type LogPage struct {
+	Entries    []LogEntry `json:"entries"`
+	NextCursor string     `json:"nextCursor"`
+}
+
+// encodeCursor builds the opaque token for the line with the given creation
+// time and insertion ID.
+func encodeCursor(createdAt time.Time, id int) string {
+	raw := fmt.Sprintf("%d:%d", createdAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// resolveCursor decodes token and returns the insertion ID it points at,
+// checking that the line still exists with the recorded timestamp. The
+// caller must hold s.mu.
+func (s *Store) resolveCursor(token string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	var (
+		nanos int64
+		id    int
+	)
+	if n, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil || n != 2 {
+		return 0, ErrInvalidCursor
+	}
+	if id < 0 || id >= len(s.logs) || s.record(id).CreatedAt.UnixNano() != nanos {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
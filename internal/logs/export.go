@@ -0,0 +1,57 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ExportFormat selects how ExportLogs renders entries.
+type ExportFormat string
+
+const (
+	ExportText ExportFormat = "text"
+	ExportCSV  ExportFormat = "csv"
+)
+
+// ErrUnknownFormat is returned by ExportLogs for unsupported formats.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+var csvHeader = []string{"timestamp", "namespace", "pod", "level", "message"}
+
+// ExportLogs renders the entries matched by filter either as one FormatLine
+// per entry or as CSV with a header row.
+func (s *Store) ExportLogs(now time.Time, filter LogFilter, format ExportFormat) ([]byte, error) {
+	if format != ExportText && format != ExportCSV {
+		return nil, ErrUnknownFormat
+	}
+
+	entries := s.ListLogs(now, filter)
+	if format == ExportText {
+		var b strings.Builder
+		for _, entry := range entries {
+			b.WriteString(FormatLine(entry))
+			b.WriteByte('\n')
+		}
+		return []byte(b.String()), nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		row := []string{entry.Timestamp, entry.Namespace, entry.Pod, string(entry.Level), entry.Message}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
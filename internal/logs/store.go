@@ -6,6 +6,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"k8s_dashboard/internal/scope"
 )
 
 // Level represents the severity of a log entry.
@@ -38,12 +40,45 @@ type Event struct {
 	Count     int    `json:"count"`
 }
 
+// Config tunes the behaviour of the mock log and event store.
+type Config struct {
+	// EscalationThreshold is the aggregated count at which repeated Warning
+	// events for the same object and reason produce a Critical summary event.
+	// Zero or negative values disable escalation.
+	EscalationThreshold int
+}
+
+// DefaultConfig returns the configuration used by NewStore.
+func DefaultConfig() Config {
+	return Config{EscalationThreshold: 5}
+}
+
 // LogFilter narrows down the log entries returned from the store.
 type LogFilter struct {
-	Namespace string
-	Pod       string
-	Level     string
-	Limit     int
+	Namespace    string
+	Pod          string
+	Level        string
+	ExcludeLevel string
+	Limit        int
+
+	// Levels, when non-empty, keeps entries whose level is in the set. It
+	// is applied together with Level.
+	Levels map[Level]bool
+
+	// Ascending returns the oldest entries first instead of the newest.
+	// The limit applies after ordering.
+	Ascending bool
+
+	// Search, when non-empty, keeps entries whose message contains it,
+	// ignoring case.
+	Search string
+
+	// Namespaces, when non-empty, restricts results to these namespaces.
+	Namespaces []string
+
+	// Pods, when non-empty, keeps entries from these pods, ignoring case.
+	// It is applied together with Pod.
+	Pods []string
 }
 
 type logRecord struct {
@@ -68,23 +103,67 @@ type eventRecord struct {
 // Store contains mock log lines and events with thread-safety.
 type Store struct {
 	mu     sync.RWMutex
+	cfg    Config
 	logs   []logRecord
 	events []eventRecord
 }
 
 // NewStore seeds the store with deterministic diagnostic data.
 func NewStore(now time.Time) *Store {
-	s := &Store{}
+	return NewStoreWithConfig(now, DefaultConfig())
+}
+
+// NewStoreWithConfig seeds the store and applies the supplied configuration.
+func NewStoreWithConfig(now time.Time, cfg Config) *Store {
+	s := &Store{cfg: cfg}
 	s.logs = defaultLogs(now)
 	s.events = defaultEvents(now)
 	return s
 }
 
-// ListLogs returns log entries sorted by recency with optional filtering.
+// NewStoreWithVolume seeds the default data plus linesPerPod deterministic
+// lines for every seeded pod, newest first, to exercise scrolling and limits.
+func NewStoreWithVolume(now time.Time, linesPerPod int) *Store {
+	s := NewStore(now)
+	s.logs = append(volumeLogs(now, s.logs, linesPerPod), s.logs...)
+	return s
+}
+
+// ListLogs returns log entries sorted by creation time, newest first unless
+// filter.Ascending is set, with optional filtering.
 func (s *Store) ListLogs(now time.Time, filter LogFilter) []LogEntry {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	entries, _ := s.listLogs(filter, -1)
+	return entries
+}
+
+// ListLogsPage is ListLogs resuming after cursor, a token taken from a
+// previous page's NextCursor. An empty cursor starts from the beginning.
+// NextCursor is empty once no further entries match.
+func (s *Store) ListLogsPage(now time.Time, filter LogFilter, cursor string) (LogPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	after := -1
+	if cursor != "" {
+		id, err := s.resolveCursor(cursor)
+		if err != nil {
+			return LogPage{}, err
+		}
+		after = id
+	}
+
+	entries, next := s.listLogs(filter, after)
+	return LogPage{Entries: entries, NextCursor: next}, nil
+}
+
+// listLogs filters and orders the stored lines, skipping everything up to
+// and including the record with ID after when it is not negative. Record IDs
+// count insertions from the oldest, so they stay stable as lines are
+// prepended. The caller must hold s.mu.
+func (s *Store) listLogs(filter LogFilter, after int) ([]LogEntry, string) {
 	limit := filter.Limit
 	if limit <= 0 || limit > 200 {
 		limit = 50
@@ -93,34 +172,108 @@ func (s *Store) ListLogs(now time.Time, filter LogFilter) []LogEntry {
 	namespace := strings.TrimSpace(strings.ToLower(filter.Namespace))
 	pod := strings.TrimSpace(strings.ToLower(filter.Pod))
 	level := strings.TrimSpace(strings.ToUpper(filter.Level))
+	exclude := strings.TrimSpace(strings.ToUpper(filter.ExcludeLevel))
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+
+	ids := make([]int, len(s.logs))
+	for i := range ids {
+		ids[i] = len(s.logs) - 1 - i
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := s.record(ids[i]), s.record(ids[j])
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt) == filter.Ascending
+		}
+		return (ids[i] < ids[j]) == filter.Ascending
+	})
+	if after >= 0 {
+		for i, id := range ids {
+			if id == after {
+				ids = ids[i+1:]
+				break
+			}
+		}
+	}
 
 	result := make([]LogEntry, 0, limit)
+	next := ""
+	lastID := -1
 
-	for _, rec := range s.logs {
+	for _, id := range ids {
+		rec := s.record(id)
 		if namespace != "" && strings.ToLower(rec.Namespace) != namespace {
 			continue
 		}
 		if pod != "" && strings.ToLower(rec.Pod) != pod {
 			continue
 		}
+		if !scope.Allows(filter.Namespaces, rec.Namespace) {
+			continue
+		}
+		if len(filter.Pods) > 0 && !containsFold(filter.Pods, rec.Pod) {
+			continue
+		}
 		if level != "" && string(rec.Level) != level {
 			continue
 		}
+		if exclude != "" && string(rec.Level) == exclude {
+			continue
+		}
+		if len(filter.Levels) > 0 && !filter.Levels[rec.Level] {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(rec.Message), search) {
+			continue
+		}
 
-		entry := LogEntry{
+		if len(result) >= limit {
+			// Another match exists past this page.
+			next = encodeCursor(s.record(lastID).CreatedAt, lastID)
+			break
+		}
+		lastID = id
+		result = append(result, LogEntry{
 			Timestamp: rec.CreatedAt.Format(time.RFC3339),
 			Namespace: rec.Namespace,
 			Pod:       rec.Pod,
 			Level:     rec.Level,
 			Message:   rec.Message,
-		}
-		result = append(result, entry)
-		if len(result) >= limit {
-			break
-		}
+		})
 	}
 
-	return result
+	return result, next
+}
+
+// record returns the log line with the given insertion ID.
+func (s *Store) record(id int) logRecord {
+	return s.logs[len(s.logs)-1-id]
+}
+
+// EventFilter narrows the event timeline. Empty fields match every event and
+// set fields must all match. Type ignores case; namespace and reason are
+// exact.
+type EventFilter struct {
+	Type      string
+	Namespace string
+	Reason    string
+}
+
+func (f EventFilter) matches(ev Event) bool {
+	return (f.Type == "" || strings.EqualFold(f.Type, ev.Type)) &&
+		(f.Namespace == "" || f.Namespace == ev.Namespace) &&
+		(f.Reason == "" || f.Reason == ev.Reason)
+}
+
+// ListEventsFiltered is ListEvents limited to events matching filter.
+func (s *Store) ListEventsFiltered(now time.Time, filter EventFilter) []Event {
+	events := s.ListEvents(now)
+	out := make([]Event, 0, len(events))
+	for _, ev := range events {
+		if filter.matches(ev) {
+			out = append(out, ev)
+		}
+	}
+	return out
 }
 
 // ListEvents returns cluster events ordered by most recent first.
@@ -149,6 +302,17 @@ func (s *Store) ListEvents(now time.Time) []Event {
 	return events
 }
 
+// ListEventsGrouped returns events keyed by their lowercase kind/name object
+// reference, keeping the most recent first within each group.
+func (s *Store) ListEventsGrouped(now time.Time) map[string][]Event {
+	grouped := make(map[string][]Event)
+	for _, ev := range s.ListEvents(now) {
+		key := strings.ToLower(ev.Kind) + "/" + ev.Name
+		grouped[key] = append(grouped[key], ev)
+	}
+	return grouped
+}
+
 func defaultLogs(now time.Time) []logRecord {
 	base := now.Add(-5 * time.Minute)
 
@@ -221,8 +385,9 @@ func defaultEvents(now time.Time) []eventRecord {
 	}
 }
 
-// AppendLog adds a new log line to the store ensuring recency ordering.
-func (s *Store) AppendLog(entry LogEntry) {
+// AppendLog adds a new log line to the store ensuring recency ordering and
+// returns the entry as stored.
+func (s *Store) AppendLog(entry LogEntry) LogEntry {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -234,6 +399,13 @@ func (s *Store) AppendLog(entry LogEntry) {
 		CreatedAt: parseTimestamp(entry.Timestamp, time.Now()),
 	}
 	s.logs = append([]logRecord{rec}, s.logs...)
+	return LogEntry{
+		Timestamp: rec.CreatedAt.Format(time.RFC3339),
+		Namespace: rec.Namespace,
+		Pod:       rec.Pod,
+		Level:     rec.Level,
+		Message:   rec.Message,
+	}
 }
 
 func parseTimestamp(ts string, fallback time.Time) time.Time {
@@ -246,11 +418,19 @@ func parseTimestamp(ts string, fallback time.Time) time.Time {
 	return fallback
 }
 
-// AppendEvent adds a new event to the store.
+// AppendEvent adds a new event to the store. Events repeating the same
+// object, type and reason are aggregated into the existing entry, and a
+// Warning whose count crosses the escalation threshold additionally records a
+// Critical summary event.
 func (s *Store) AppendEvent(ev Event) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	count := ev.Count
+	if count <= 0 {
+		count = 1
+	}
+
 	rec := eventRecord{
 		Namespace: ev.Namespace,
 		Kind:      ev.Kind,
@@ -258,23 +438,67 @@ func (s *Store) AppendEvent(ev Event) {
 		Type:      ev.Type,
 		Reason:    ev.Reason,
 		Message:   ev.Message,
-		Count:     ev.Count,
+		Count:     count,
 		Occurred:  parseTimestamp(ev.Timestamp, time.Now()),
 	}
+
+	previous := 0
+	for i, existing := range s.events {
+		if sameEvent(existing, rec) {
+			previous = existing.Count
+			rec.Count += existing.Count
+			s.events = append(s.events[:i], s.events[i+1:]...)
+			break
+		}
+	}
 	s.events = append([]eventRecord{rec}, s.events...)
+
+	threshold := s.cfg.EscalationThreshold
+	if threshold > 0 && rec.Type == "Warning" && previous < threshold && rec.Count >= threshold {
+		s.events = append([]eventRecord{escalation(rec)}, s.events...)
+	}
+}
+
+func sameEvent(a, b eventRecord) bool {
+	return a.Namespace == b.Namespace &&
+		a.Kind == b.Kind &&
+		a.Name == b.Name &&
+		a.Type == b.Type &&
+		a.Reason == b.Reason
+}
+
+func escalation(rec eventRecord) eventRecord {
+	return eventRecord{
+		Namespace: rec.Namespace,
+		Kind:      rec.Kind,
+		Name:      rec.Name,
+		Type:      "Critical",
+		Reason:    "Escalated" + rec.Reason,
+		Message:   fmt.Sprintf("%s repeated %d times for %s/%s", rec.Reason, rec.Count, rec.Kind, rec.Name),
+		Count:     1,
+		Occurred:  rec.Occurred,
+	}
 }
 
 // UniqueNamespaces returns the distinct namespaces used in either logs or events.
 func (s *Store) UniqueNamespaces() []string {
+	return s.uniqueNamespacesIn(nil)
+}
+
+func (s *Store) uniqueNamespacesIn(allowed []string) []string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	set := make(map[string]struct{})
 	for _, rec := range s.logs {
-		set[rec.Namespace] = struct{}{}
+		if scope.Allows(allowed, rec.Namespace) {
+			set[rec.Namespace] = struct{}{}
+		}
 	}
 	for _, rec := range s.events {
-		set[rec.Namespace] = struct{}{}
+		if scope.Allows(allowed, rec.Namespace) {
+			set[rec.Namespace] = struct{}{}
+		}
 	}
 
 	namespaces := make([]string, 0, len(set))
@@ -287,12 +511,18 @@ func (s *Store) UniqueNamespaces() []string {
 
 // UniquePods returns the distinct pod names present in the logs.
 func (s *Store) UniquePods() []string {
+	return s.uniquePodsIn(nil)
+}
+
+func (s *Store) uniquePodsIn(allowed []string) []string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	set := make(map[string]struct{})
 	for _, rec := range s.logs {
-		set[rec.Pod] = struct{}{}
+		if scope.Allows(allowed, rec.Namespace) {
+			set[rec.Pod] = struct{}{}
+		}
 	}
 	pods := make([]string, 0, len(set))
 	for pod := range set {
@@ -309,13 +539,28 @@ func (s *Store) UniqueLevels() []Level {
 
 // DescribeFilters returns contextual information for filter dropdowns.
 func (s *Store) DescribeFilters() map[string]any {
+	return s.DescribeFiltersIn(nil)
+}
+
+// DescribeFiltersIn is DescribeFilters limited to the given namespaces; an
+// empty list means all namespaces.
+func (s *Store) DescribeFiltersIn(namespaces []string) map[string]any {
 	return map[string]any{
-		"namespaces": s.UniqueNamespaces(),
-		"pods":       s.UniquePods(),
+		"namespaces": s.uniqueNamespacesIn(namespaces),
+		"pods":       s.uniquePodsIn(namespaces),
 		"levels":     s.UniqueLevels(),
 	}
 }
 
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
 // Summarize returns a concise overview for status widgets.
 func (s *Store) Summarize(now time.Time) map[string]any {
 	logs := s.ListLogs(now, LogFilter{Limit: 10})
@@ -326,6 +571,11 @@ func (s *Store) Summarize(now time.Time) map[string]any {
 	}
 }
 
+// FormatLine renders a log entry as a single plain-text line.
+func FormatLine(entry LogEntry) string {
+	return fmt.Sprintf("%s [%s] %s/%s %s", entry.Timestamp, entry.Level, entry.Namespace, entry.Pod, entry.Message)
+}
+
 // FormatRelative renders a human readable relative duration.
 func FormatRelative(now, target time.Time) string {
 	if target.After(now) {
@@ -346,3 +596,43 @@ func FormatRelative(now, target time.Time) string {
 		return fmt.Sprintf("%dm", minutes)
 	}
 }
+
+// volumeLogs generates linesPerPod lines for each pod in seed, one second
+// apart and interleaved across pods, ordered newest first.
+func volumeLogs(now time.Time, seed []logRecord, linesPerPod int) []logRecord {
+	type podRef struct{ namespace, pod string }
+	var pods []podRef
+	seen := make(map[podRef]bool)
+	for _, rec := range seed {
+		ref := podRef{rec.Namespace, rec.Pod}
+		if !seen[ref] {
+			seen[ref] = true
+			pods = append(pods, ref)
+		}
+	}
+	if linesPerPod <= 0 || len(pods) == 0 {
+		return nil
+	}
+
+	total := linesPerPod * len(pods)
+	out := make([]logRecord, 0, total)
+	for i := 0; i < total; i++ {
+		ref := pods[i%len(pods)]
+		line := linesPerPod - i/len(pods)
+		level := LevelInfo
+		switch {
+		case line%25 == 0:
+			level = LevelError
+		case line%10 == 0:
+			level = LevelWarn
+		}
+		out = append(out, logRecord{
+			Namespace: ref.namespace,
+			Pod:       ref.pod,
+			Level:     level,
+			Message:   fmt.Sprintf("synthetic line %d", line),
+			CreatedAt: now.Add(-time.Duration(i) * time.Second),
+		})
+	}
+	return out
+}
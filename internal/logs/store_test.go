@@ -1,6 +1,7 @@
 package logs
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -33,6 +34,74 @@ func TestListLogsWithFilters(t *testing.T) {
 	}
 }
 
+func TestListLogsExcludeLevel(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+
+	entries := store.ListLogs(freeze, LogFilter{ExcludeLevel: "info", Limit: 50})
+	if len(entries) == 0 {
+		t.Fatalf("expected non-info logs")
+	}
+	for _, entry := range entries {
+		if entry.Level != LevelWarn && entry.Level != LevelError {
+			t.Fatalf("expected only WARN/ERROR, got %s", entry.Level)
+		}
+	}
+}
+
+func TestListLogsLevelSet(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+
+	entries := store.ListLogs(freeze, LogFilter{Levels: map[Level]bool{LevelWarn: true, LevelError: true}, Limit: 50})
+	if len(entries) == 0 {
+		t.Fatalf("expected WARN/ERROR logs")
+	}
+	for _, entry := range entries {
+		if entry.Level == LevelInfo {
+			t.Fatalf("unexpected INFO entry %q", entry.Message)
+		}
+	}
+}
+
+func TestListLogsSearch(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+
+	entries := store.ListLogs(freeze, LogFilter{Search: "HEALTHZ", Limit: 50})
+	if len(entries) == 0 {
+		t.Fatalf("expected logs matching healthz")
+	}
+	for _, entry := range entries {
+		if !strings.Contains(strings.ToLower(entry.Message), "healthz") {
+			t.Fatalf("unexpected message %q", entry.Message)
+		}
+	}
+
+	if got := store.ListLogs(freeze, LogFilter{Search: "healthz", Level: "warn", Limit: 50}); len(got) != 0 {
+		t.Fatalf("expected search to combine with level, got %d entries", len(got))
+	}
+	if all, plain := store.ListLogs(freeze, LogFilter{Search: "  ", Limit: 50}), store.ListLogs(freeze, LogFilter{Limit: 50}); len(all) != len(plain) {
+		t.Fatalf("expected blank search to match everything, got %d vs %d", len(all), len(plain))
+	}
+}
+
+func TestListLogsPodSet(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+
+	pods := []string{"frontend-7d8fdc9f7c-abc12", "FRONTEND-7d8fdc9f7c-def34"}
+	entries := store.ListLogs(freeze, LogFilter{Pods: pods})
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries from both frontend pods, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Pod, "frontend-") {
+			t.Fatalf("unexpected pod %s", entry.Pod)
+		}
+	}
+}
+
 func TestListEventsOrdering(t *testing.T) {
 	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
 	store := NewStore(freeze)
@@ -48,3 +117,228 @@ func TestListEventsOrdering(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendEventEscalatesRepeatedWarnings(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStoreWithConfig(freeze, Config{EscalationThreshold: 5})
+
+	warning := Event{
+		Namespace: "default",
+		Kind:      "Pod",
+		Name:      "frontend-7d8fdc9f7c-abc12",
+		Type:      "Warning",
+		Reason:    "BackOff",
+		Message:   "Back-off restarting failed container",
+		Timestamp: freeze.Format(time.RFC3339),
+	}
+
+	escalations := func() int {
+		count := 0
+		for _, ev := range store.ListEvents(freeze) {
+			if ev.Type == "Critical" && ev.Reason == "EscalatedBackOff" {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 4; i++ {
+		store.AppendEvent(warning)
+	}
+	if got := escalations(); got != 0 {
+		t.Fatalf("expected no escalation below threshold, got %d", got)
+	}
+
+	store.AppendEvent(warning)
+	if got := escalations(); got != 1 {
+		t.Fatalf("expected escalation at threshold, got %d", got)
+	}
+
+	store.AppendEvent(warning)
+	if got := escalations(); got != 1 {
+		t.Fatalf("expected a single escalation after threshold, got %d", got)
+	}
+
+	for _, ev := range store.ListEvents(freeze) {
+		if ev.Reason == "BackOff" && ev.Count != 6 {
+			t.Fatalf("expected aggregated count 6, got %d", ev.Count)
+		}
+	}
+}
+
+func TestListEventsGrouped(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+
+	grouped := store.ListEventsGrouped(freeze)
+
+	gateway := grouped["pod/edge-gateway-7d8fdc9f7c-9012a"]
+	if len(gateway) != 1 || gateway[0].Reason != "FailedScheduling" {
+		t.Fatalf("unexpected edge-gateway group: %+v", gateway)
+	}
+
+	if len(grouped["deployment/frontend"]) != 1 {
+		t.Fatalf("expected frontend deployment group, got %v", grouped)
+	}
+}
+
+func TestNewStoreWithVolume(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStoreWithVolume(freeze, 500)
+
+	if logs := store.ListLogs(freeze, LogFilter{Limit: 200}); len(logs) != 200 {
+		t.Fatalf("expected 200 logs at the maximum limit, got %d", len(logs))
+	}
+	for _, limit := range []int{0, 1000} {
+		if logs := store.ListLogs(freeze, LogFilter{Limit: limit}); len(logs) != 50 {
+			t.Fatalf("expected limit %d to fall back to 50, got %d", limit, len(logs))
+		}
+	}
+
+	tail := store.ListLogs(freeze, LogFilter{Pod: "backend-76c4d5f6d6-xyz89", Limit: 3})
+	if len(tail) != 3 {
+		t.Fatalf("expected 3 tail lines, got %d", len(tail))
+	}
+	for i, want := range []string{"synthetic line 500", "synthetic line 499", "synthetic line 498"} {
+		if tail[i].Message != want {
+			t.Fatalf("expected %q at %d, got %q", want, i, tail[i].Message)
+		}
+	}
+	if tail[0].Timestamp < tail[1].Timestamp {
+		t.Fatalf("expected newest line first, got %s before %s", tail[0].Timestamp, tail[1].Timestamp)
+	}
+
+	errors := store.ListLogs(freeze, LogFilter{Pod: "backend-76c4d5f6d6-xyz89", Level: "error", Limit: 200})
+	if len(errors) != 500/25 {
+		t.Fatalf("expected %d error lines for backend, got %d", 500/25, len(errors))
+	}
+
+	if small := NewStore(freeze).ListLogs(freeze, LogFilter{Limit: 200}); len(small) != 10 {
+		t.Fatalf("expected default store to stay small, got %d", len(small))
+	}
+}
+
+func TestListLogsOrder(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+	store.AppendLog(LogEntry{Namespace: "prod", Pod: "api", Level: LevelInfo, Message: "oldest", Timestamp: freeze.Add(-24 * time.Hour).Format(time.RFC3339)})
+
+	desc := store.ListLogs(freeze, LogFilter{Limit: 200})
+	for i := 1; i < len(desc); i++ {
+		if desc[i-1].Timestamp < desc[i].Timestamp {
+			t.Fatalf("expected newest first, got %s before %s", desc[i-1].Timestamp, desc[i].Timestamp)
+		}
+	}
+	if desc[len(desc)-1].Message != "oldest" {
+		t.Fatalf("expected appended old entry last, got %q", desc[len(desc)-1].Message)
+	}
+
+	asc := store.ListLogs(freeze, LogFilter{Limit: 200, Ascending: true})
+	if asc[0].Message != "oldest" {
+		t.Fatalf("expected oldest entry first, got %q", asc[0].Message)
+	}
+	for i := 1; i < len(asc); i++ {
+		if asc[i-1].Timestamp > asc[i].Timestamp {
+			t.Fatalf("expected oldest first, got %s before %s", asc[i-1].Timestamp, asc[i].Timestamp)
+		}
+	}
+}
+
+func TestExportLogsCSV(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+	store.AppendLog(LogEntry{Namespace: "prod", Pod: "api", Level: LevelError, Message: `dial "db", refused`, Timestamp: freeze.Format(time.RFC3339)})
+
+	raw, err := store.ExportLogs(freeze, LogFilter{Pod: "api", Limit: 10}, ExportCSV)
+	if err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if lines[0] != "timestamp,namespace,pod,level,message" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	want := freeze.Format(time.RFC3339) + `,prod,api,ERROR,"dial ""db"", refused"`
+	if len(lines) != 2 || lines[1] != want {
+		t.Fatalf("expected escaped row %q, got %q", want, lines)
+	}
+
+	text, err := store.ExportLogs(freeze, LogFilter{Pod: "api", Limit: 10}, ExportText)
+	if err != nil || !strings.Contains(string(text), "[ERROR] prod/api") {
+		t.Fatalf("unexpected text export %q (%v)", text, err)
+	}
+
+	if _, err := store.ExportLogs(freeze, LogFilter{}, "xml"); err != ErrUnknownFormat {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestListLogsPageCursor(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStoreWithVolume(freeze, 5)
+
+	all := store.ListLogs(freeze, LogFilter{Limit: 200})
+	var paged []LogEntry
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > len(all) {
+			t.Fatalf("cursor pagination did not terminate")
+		}
+		page, err := store.ListLogsPage(freeze, LogFilter{Limit: 7}, cursor)
+		if err != nil {
+			t.Fatalf("list page: %v", err)
+		}
+		paged = append(paged, page.Entries...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(paged) != len(all) {
+		t.Fatalf("expected %d entries across pages, got %d", len(all), len(paged))
+	}
+	for i := range all {
+		if paged[i] != all[i] {
+			t.Fatalf("entry %d differs: %+v vs %+v", i, paged[i], all[i])
+		}
+	}
+
+	for _, bad := range []string{"%%%", "bm90LWEtY3Vyc29y", encodeCursor(freeze, 100000)} {
+		if _, err := store.ListLogsPage(freeze, LogFilter{}, bad); err != ErrInvalidCursor {
+			t.Fatalf("expected ErrInvalidCursor for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestListEventsFiltered(t *testing.T) {
+	freeze := time.Date(2024, 7, 12, 10, 0, 0, 0, time.UTC)
+	store := NewStore(freeze)
+
+	all := store.ListEvents(freeze)
+	if got := store.ListEventsFiltered(freeze, EventFilter{}); len(got) != len(all) {
+		t.Fatalf("expected empty filter to return all %d events, got %d", len(all), len(got))
+	}
+
+	warnings := store.ListEventsFiltered(freeze, EventFilter{Type: "warning"})
+	if len(warnings) == 0 {
+		t.Fatalf("expected Warning events in the seed")
+	}
+	for _, ev := range warnings {
+		if ev.Type != "Warning" {
+			t.Fatalf("expected only Warning events, got %+v", ev)
+		}
+	}
+
+	first := warnings[0]
+	combined := store.ListEventsFiltered(freeze, EventFilter{Type: "Warning", Namespace: first.Namespace, Reason: first.Reason})
+	if len(combined) == 0 {
+		t.Fatalf("expected combined filter to match %+v", first)
+	}
+	for _, ev := range combined {
+		if ev.Namespace != first.Namespace || ev.Reason != first.Reason {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	}
+	if got := store.ListEventsFiltered(freeze, EventFilter{Reason: strings.ToLower(first.Reason) + "x"}); len(got) != 0 {
+		t.Fatalf("expected unknown reason to match nothing, got %d", len(got))
+	}
+}
@@ -0,0 +1,28 @@
+// Package names implements the name-prefix matching behind the stores'
+// NamesWithPrefix methods.
+package names
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithPrefix returns up to limit sorted, distinct entries of names starting
+// with prefix, ignoring case.
+func WithPrefix(names []string, prefix string, limit int) []string {
+	prefix = strings.ToLower(prefix)
+	seen := make(map[string]bool)
+	out := make([]string, 0)
+	for _, name := range names {
+		if seen[name] || !strings.HasPrefix(strings.ToLower(name), prefix) {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
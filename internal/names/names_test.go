@@ -0,0 +1,27 @@
+package names
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithPrefix(t *testing.T) {
+	candidates := []string{"frontend", "Fluentd", "backend", "frontend", "fleet"}
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{prefix: "f", limit: 10, want: []string{"Fluentd", "fleet", "frontend"}},
+		{prefix: "FR", limit: 10, want: []string{"frontend"}},
+		{prefix: "f", limit: 2, want: []string{"Fluentd", "fleet"}},
+		{prefix: "x", limit: 10, want: []string{}},
+		{prefix: "", limit: 0, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := WithPrefix(candidates, tt.prefix, tt.limit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("WithPrefix(%q, %d) = %v, want %v", tt.prefix, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,11 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"k8s_dashboard/internal/age"
+	"k8s_dashboard/internal/label"
+	"k8s_dashboard/internal/names"
+	"k8s_dashboard/internal/scope"
 )
 
 var (
@@ -15,17 +20,58 @@ var (
 	ErrExists = errors.New("namespace already exists")
 	// ErrInvalidName signals the provided name violates Kubernetes naming rules.
 	ErrInvalidName = errors.New("invalid namespace name")
+	// ErrNotFound indicates the namespace does not exist.
+	ErrNotFound = errors.New("namespace not found")
+	// ErrInvalidLabels signals a label key or value violates Kubernetes syntax.
+	ErrInvalidLabels = errors.New("invalid namespace labels")
+	// ErrInvalidSelector signals a malformed label selector.
+	ErrInvalidSelector = label.ErrInvalidSelector
+	// ErrProtected indicates the namespace is a protected system namespace.
+	ErrProtected = errors.New("namespace is protected")
+	// ErrCycle signals the parent label chain loops back on itself.
+	ErrCycle = errors.New("namespace parent cycle detected")
+	// ErrInvalidNameRule signals a NameRule with a non-positive length or a
+	// pattern that does not compile.
+	ErrInvalidNameRule = errors.New("invalid namespace name rule")
 )
 
-var namespaceNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+// DefaultProtected lists the system namespaces that refuse deletion by default.
+var DefaultProtected = []string{"default", "kube-system", "kube-public"}
+
+// ParentLabel references the parent namespace whose labels are inherited.
+const ParentLabel = "namespace.kubernetes.io/parent"
+
+// NameRule constrains the names accepted by Create.
+type NameRule struct {
+	MaxLength int
+	Pattern   string
+}
+
+// DefaultNameRule mirrors the Kubernetes DNS-1123 label rule.
+func DefaultNameRule() NameRule {
+	return NameRule{MaxLength: 63, Pattern: `^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`}
+}
+
+var namespaceNameRegex = regexp.MustCompile(DefaultNameRule().Pattern)
 
 // Namespace represents the JSON payload returned to the frontend.
 type Namespace struct {
-	Name      string            `json:"name"`
-	Status    string            `json:"status"`
-	Age       string            `json:"age"`
-	CreatedAt string            `json:"createdAt"`
-	Labels    map[string]string `json:"labels,omitempty"`
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Age        string            `json:"age"`
+	AgeSeconds int64             `json:"ageSeconds"`
+	CreatedAt  string            `json:"createdAt"`
+	Labels     map[string]string `json:"labels,omitempty"`
+
+	// CreatedAtLocal is CreatedAt rendered in a caller-requested timezone.
+	CreatedAtLocal string `json:"createdAtLocal,omitempty"`
+}
+
+// DeleteOptions controls how a namespace deletion is carried out.
+type DeleteOptions struct {
+	// GraceSeconds keeps the namespace Terminating for this long before it
+	// disappears. Zero removes the namespace immediately.
+	GraceSeconds int
 }
 
 type record struct {
@@ -33,18 +79,40 @@ type record struct {
 	Status    string
 	CreatedAt time.Time
 	Labels    map[string]string
+	// RemoveAt is set once the namespace is Terminating.
+	RemoveAt time.Time
+}
+
+func (rec record) expired(now time.Time) bool {
+	return !rec.RemoveAt.IsZero() && !now.Before(rec.RemoveAt)
 }
 
 // Store keeps in-memory namespace state for the mock API.
 type Store struct {
-	mu    sync.RWMutex
-	items map[string]record
+	mu        sync.RWMutex
+	items     map[string]record
+	protected map[string]bool
+
+	maxNameLen int
+	nameRegex  *regexp.Regexp
 }
 
-// NewStore seeds a namespace store with deterministic mock data.
-func NewStore(now time.Time) *Store {
+// NewStore seeds a namespace store with deterministic mock data. The optional
+// protected names replace DefaultProtected as the namespaces refusing deletion.
+func NewStore(now time.Time, protected ...string) *Store {
+	if len(protected) == 0 {
+		protected = DefaultProtected
+	}
+
 	s := &Store{
-		items: make(map[string]record),
+		items:      make(map[string]record),
+		protected:  make(map[string]bool, len(protected)),
+		maxNameLen: DefaultNameRule().MaxLength,
+		nameRegex:  namespaceNameRegex,
+	}
+
+	for _, name := range protected {
+		s.protected[name] = true
 	}
 
 	for _, rec := range defaultSeed(now) {
@@ -54,6 +122,24 @@ func NewStore(now time.Time) *Store {
 	return s
 }
 
+// NewStoreWithNameRule seeds a store like NewStore but validates new names
+// against rule instead of DefaultNameRule. It returns ErrInvalidNameRule when
+// the pattern does not compile or the length is not positive.
+func NewStoreWithNameRule(now time.Time, rule NameRule, protected ...string) (*Store, error) {
+	if rule.MaxLength <= 0 {
+		return nil, ErrInvalidNameRule
+	}
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNameRule, err)
+	}
+
+	s := NewStore(now, protected...)
+	s.maxNameLen = rule.MaxLength
+	s.nameRegex = re
+	return s, nil
+}
+
 // List returns all namespaces sorted alphabetically.
 func (s *Store) List(now time.Time) []Namespace {
 	s.mu.RLock()
@@ -61,6 +147,9 @@ func (s *Store) List(now time.Time) []Namespace {
 
 	out := make([]Namespace, 0, len(s.items))
 	for _, rec := range s.items {
+		if rec.expired(now) {
+			continue
+		}
 		out = append(out, toNamespace(rec, now))
 	}
 
@@ -71,17 +160,100 @@ func (s *Store) List(now time.Time) []Namespace {
 	return out
 }
 
+// ListFiltered returns namespaces matching every key=value requirement in the
+// comma-separated selector. An empty selector matches all namespaces.
+func (s *Store) ListFiltered(now time.Time, selector string) ([]Namespace, error) {
+	reqs, err := label.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	all := s.List(now)
+	if len(reqs) == 0 {
+		return all, nil
+	}
+
+	out := make([]Namespace, 0, len(all))
+	for _, ns := range all {
+		if label.Matches(ns.Labels, reqs) {
+			out = append(out, ns)
+		}
+	}
+	return out, nil
+}
+
+// Get returns a single namespace by name.
+func (s *Store) Get(name string, now time.Time) (Namespace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.lookup(name, now)
+	if !ok {
+		return Namespace{}, ErrNotFound
+	}
+	return toNamespace(rec, now), nil
+}
+
+// GetInherited returns the namespace with its effective labels, merged from
+// the parent chain so that labels closer to the namespace take precedence.
+// A chain referencing a missing parent stops at the last existing namespace.
+func (s *Store) GetInherited(name string, now time.Time) (Namespace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.lookup(name, now)
+	if !ok {
+		return Namespace{}, ErrNotFound
+	}
+
+	chain := []record{rec}
+	visited := map[string]bool{rec.Name: true}
+	for current := rec; ; {
+		parent := current.Labels[ParentLabel]
+		if parent == "" {
+			break
+		}
+		if visited[parent] {
+			return Namespace{}, ErrCycle
+		}
+		next, ok := s.lookup(parent, now)
+		if !ok {
+			break
+		}
+		visited[parent] = true
+		chain = append(chain, next)
+		current = next
+	}
+
+	labels := make(map[string]string)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].Labels {
+			labels[k] = v
+		}
+	}
+	labels["kubernetes.io/metadata.name"] = rec.Name
+	if parent, ok := rec.Labels[ParentLabel]; ok {
+		labels[ParentLabel] = parent
+	} else {
+		delete(labels, ParentLabel)
+	}
+
+	ns := toNamespace(rec, now)
+	ns.Labels = labels
+	return ns, nil
+}
+
 // Create inserts a new namespace if it does not yet exist.
 func (s *Store) Create(name string, now time.Time, labels map[string]string) (Namespace, error) {
 	clean := strings.TrimSpace(name)
-	if clean == "" || len(clean) > 63 || !namespaceNameRegex.MatchString(clean) {
+	if clean == "" || len(clean) > s.maxNameLen || !s.nameRegex.MatchString(clean) {
 		return Namespace{}, ErrInvalidName
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.items[clean]; exists {
+	if _, exists := s.lookup(clean, now); exists {
 		return Namespace{}, ErrExists
 	}
 
@@ -96,51 +268,97 @@ func (s *Store) Create(name string, now time.Time, labels map[string]string) (Na
 	return toNamespace(rec, now), nil
 }
 
-// Delete removes the namespace if it exists.
-func (s *Store) Delete(name string) bool {
+// Update merges the supplied labels over the existing ones. The
+// kubernetes.io/metadata.name label always reflects the namespace name.
+func (s *Store) Update(name string, labels map[string]string, now time.Time) (Namespace, error) {
+	if err := validateLabels(labels); err != nil {
+		return Namespace{}, err
+	}
+
 	clean := strings.TrimSpace(name)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.items[clean]; !exists {
-		return false
+	rec, ok := s.lookup(clean, now)
+	if !ok {
+		return Namespace{}, ErrNotFound
 	}
 
-	delete(s.items, clean)
-	return true
+	merged := make(map[string]string, len(rec.Labels)+len(labels))
+	for k, v := range rec.Labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	merged["kubernetes.io/metadata.name"] = rec.Name
+
+	rec.Labels = merged
+	s.items[clean] = rec
+	return toNamespace(rec, now), nil
 }
 
-func toNamespace(rec record, now time.Time) Namespace {
-	age := formatAge(now.Sub(rec.CreatedAt))
-	return Namespace{
-		Name:      rec.Name,
-		Status:    rec.Status,
-		Age:       age,
-		CreatedAt: rec.CreatedAt.Format(time.RFC3339),
-		Labels:    rec.Labels,
+// Delete removes the namespace if it exists and is not protected. With a
+// positive grace period the namespace is marked Terminating and disappears
+// from List/Get once the grace passes; deleting a Terminating namespace again
+// returns it unchanged.
+func (s *Store) Delete(name string, now time.Time, opts DeleteOptions) (Namespace, error) {
+	clean := strings.TrimSpace(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, exists := s.lookup(clean, now)
+	if !exists {
+		return Namespace{}, ErrNotFound
 	}
+
+	if s.protected[clean] {
+		return Namespace{}, ErrProtected
+	}
+
+	if !rec.RemoveAt.IsZero() {
+		return toNamespace(rec, now), nil
+	}
+
+	if opts.GraceSeconds <= 0 {
+		delete(s.items, clean)
+		return Namespace{}, nil
+	}
+
+	rec.Status = "Terminating"
+	rec.RemoveAt = now.Add(time.Duration(opts.GraceSeconds) * time.Second)
+	s.items[clean] = rec
+	return toNamespace(rec, now), nil
 }
 
-func formatAge(d time.Duration) string {
-	if d < 0 {
-		d = 0
+// lookup returns the record unless it has finished terminating. Callers must
+// hold the lock.
+func (s *Store) lookup(name string, now time.Time) (record, bool) {
+	rec, ok := s.items[strings.TrimSpace(name)]
+	if !ok || rec.expired(now) {
+		return record{}, false
 	}
+	return rec, true
+}
 
-	days := d / (24 * time.Hour)
-	d -= days * 24 * time.Hour
-	hours := d / time.Hour
-	d -= hours * time.Hour
-	minutes := d / time.Minute
+func toNamespace(rec record, now time.Time) Namespace {
+	return Namespace{
+		Name:       rec.Name,
+		Status:     rec.Status,
+		Age:        age.Format(now.Sub(rec.CreatedAt)),
+		AgeSeconds: age.Seconds(now.Sub(rec.CreatedAt)),
+		CreatedAt:  rec.CreatedAt.Format(time.RFC3339),
+		Labels:     rec.Labels,
+	}
+}
 
-	switch {
-	case days > 0:
-		return fmt.Sprintf("%dd%dh", days, hours)
-	case hours > 0:
-		return fmt.Sprintf("%dh%dm", hours, minutes)
-	default:
-		secs := int(d/time.Second) % 60
-		return fmt.Sprintf("%dm%ds", minutes, secs)
+func validateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if !label.ValidKey(k) || !label.ValidValue(v) {
+			return ErrInvalidLabels
+		}
 	}
+	return nil
 }
 
 func mergeLabels(name string, custom map[string]string) map[string]string {
@@ -187,3 +405,19 @@ func defaultSeed(now time.Time) []record {
 		},
 	}
 }
+
+// NamesWithPrefix returns up to limit sorted namespace names starting with
+// prefix, ignoring case. Namespaces past their termination grace are skipped,
+// and namespaces, when non-empty, restricts the search to that allow-list.
+func (s *Store) NamesWithPrefix(prefix string, namespaces []string, now time.Time, limit int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates := make([]string, 0, len(s.items))
+	for _, rec := range s.items {
+		if !rec.expired(now) && scope.Allows(namespaces, rec.Name) {
+			candidates = append(candidates, rec.Name)
+		}
+	}
+	return names.WithPrefix(candidates, prefix, limit)
+}
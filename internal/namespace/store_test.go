@@ -1,6 +1,8 @@
 package namespace
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -23,6 +25,37 @@ func TestStoreListOrdering(t *testing.T) {
 	}
 }
 
+func TestStoreCreateWithNameRule(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	long := "team." + strings.Repeat("a", 70)
+
+	if _, err := NewStore(now).Create(long, now, nil); err != ErrInvalidName {
+		t.Fatalf("expected default rule to reject %q, got %v", long, err)
+	}
+
+	store, err := NewStoreWithNameRule(now, NameRule{MaxLength: 128, Pattern: `^[a-z0-9.]+$`})
+	if err != nil {
+		t.Fatalf("new store with rule: %v", err)
+	}
+	ns, err := store.Create(long, now, nil)
+	if err != nil {
+		t.Fatalf("create with relaxed rule: %v", err)
+	}
+	if ns.Name != long {
+		t.Fatalf("unexpected namespace name %s", ns.Name)
+	}
+	if _, err := store.Create("Upper", now, nil); err != ErrInvalidName {
+		t.Fatalf("expected pattern to reject uppercase, got %v", err)
+	}
+
+	if _, err := NewStoreWithNameRule(now, NameRule{MaxLength: 10, Pattern: "(["}); !errors.Is(err, ErrInvalidNameRule) {
+		t.Fatalf("expected ErrInvalidNameRule for bad pattern, got %v", err)
+	}
+	if _, err := NewStoreWithNameRule(now, NameRule{Pattern: ".*"}); err != ErrInvalidNameRule {
+		t.Fatalf("expected ErrInvalidNameRule for zero length, got %v", err)
+	}
+}
+
 func TestStoreCreateAndDelete(t *testing.T) {
 	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
 	store := NewStore(now)
@@ -44,12 +77,169 @@ func TestStoreCreateAndDelete(t *testing.T) {
 		t.Fatalf("expected ErrExists, got %v", err)
 	}
 
-	if !store.Delete("staging") {
-		t.Fatalf("expected delete to succeed")
+	if _, err := store.Delete("staging", now, DeleteOptions{}); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+
+	if _, err := store.Delete("staging", now, DeleteOptions{}); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for missing namespace, got %v", err)
+	}
+}
+
+func TestStoreDeleteTerminating(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	ns, err := store.Delete("monitoring", now, DeleteOptions{GraceSeconds: 30})
+	if err != nil {
+		t.Fatalf("delete namespace: %v", err)
+	}
+	if ns.Status != "Terminating" {
+		t.Fatalf("expected Terminating status, got %s", ns.Status)
+	}
+
+	again, err := store.Delete("monitoring", now.Add(10*time.Second), DeleteOptions{GraceSeconds: 30})
+	if err != nil || again.Status != "Terminating" {
+		t.Fatalf("expected idempotent delete while terminating, got %+v (%v)", again, err)
+	}
+
+	during, err := store.Get("monitoring", now.Add(29*time.Second))
+	if err != nil || during.Status != "Terminating" {
+		t.Fatalf("expected namespace visible while terminating, got %+v (%v)", during, err)
+	}
+	if len(store.List(now.Add(29*time.Second))) != 3 {
+		t.Fatalf("expected terminating namespace listed")
+	}
+
+	after := now.Add(30 * time.Second)
+	if _, err := store.Get("monitoring", after); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound after grace, got %v", err)
+	}
+	if len(store.List(after)) != 2 {
+		t.Fatalf("expected namespace removed from list after grace")
+	}
+	if _, err := store.Delete("monitoring", after, DeleteOptions{}); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound deleting after grace, got %v", err)
+	}
+	if _, err := store.Create("monitoring", after, nil); err != nil {
+		t.Fatalf("expected name reusable after grace, got %v", err)
+	}
+}
+
+func TestNamesWithPrefix(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if got := store.NamesWithPrefix("MON", nil, now, 10); len(got) != 1 || got[0] != "monitoring" {
+		t.Fatalf("expected case-insensitive match on monitoring, got %v", got)
+	}
+	if _, err := store.Delete("monitoring", now, DeleteOptions{GraceSeconds: 30}); err != nil {
+		t.Fatalf("delete namespace: %v", err)
+	}
+	if got := store.NamesWithPrefix("mon", nil, now.Add(29*time.Second), 10); len(got) != 1 {
+		t.Fatalf("expected terminating namespace to match, got %v", got)
+	}
+	if got := store.NamesWithPrefix("mon", nil, now.Add(30*time.Second), 10); len(got) != 0 {
+		t.Fatalf("expected namespace past its grace to be skipped, got %v", got)
+	}
+	if got := store.NamesWithPrefix("", nil, now, 1); len(got) != 1 {
+		t.Fatalf("expected limit to cap results, got %v", got)
+	}
+	// The allow-list applies before the limit, so hidden names sorting first
+	// cannot crowd out visible ones.
+	if got := store.NamesWithPrefix("", []string{"kube-system"}, now, 1); len(got) != 1 || got[0] != "kube-system" {
+		t.Fatalf("expected only the allowed namespace, got %v", got)
+	}
+}
+
+func TestStoreDeleteProtected(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	for _, name := range []string{"default", "kube-system"} {
+		if _, err := store.Delete(name, now, DeleteOptions{}); err != ErrProtected {
+			t.Fatalf("expected ErrProtected for %s, got %v", name, err)
+		}
 	}
 
-	if store.Delete("staging") {
-		t.Fatalf("expected delete to fail for missing namespace")
+	custom := NewStore(now, "monitoring")
+	if _, err := custom.Delete("monitoring", now, DeleteOptions{}); err != ErrProtected {
+		t.Fatalf("expected injected protected set to apply, got %v", err)
+	}
+	if _, err := custom.Delete("default", now, DeleteOptions{}); err != nil {
+		t.Fatalf("expected default deletable with custom protected set, got %v", err)
+	}
+}
+
+func TestStoreUpdateLabels(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	ns, err := store.Update("monitoring", map[string]string{
+		"team":                        "observability",
+		"example.com/owner":           "alice",
+		"kubernetes.io/metadata.name": "hijacked",
+	}, now)
+	if err != nil {
+		t.Fatalf("update namespace: %v", err)
+	}
+
+	if ns.Labels["team"] != "observability" || ns.Labels["example.com/owner"] != "alice" {
+		t.Fatalf("expected labels to be merged, got %v", ns.Labels)
+	}
+
+	if ns.Labels["kubernetes.io/metadata.name"] != "monitoring" {
+		t.Fatalf("expected metadata.name label to be kept, got %v", ns.Labels)
+	}
+
+	if _, err := store.Update("ghost", map[string]string{"team": "x"}, now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	for _, labels := range []map[string]string{
+		{"bad key": "v"},
+		{"team": "-invalid"},
+		{"/name": "v"},
+	} {
+		if _, err := store.Update("monitoring", labels, now); err != ErrInvalidLabels {
+			t.Fatalf("expected ErrInvalidLabels for %v, got %v", labels, err)
+		}
+	}
+}
+
+func TestStoreListFiltered(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if _, err := store.Create("sre-tools", now, map[string]string{"team": "sre", "tier": "ops"}); err != nil {
+		t.Fatalf("create namespace: %v", err)
+	}
+
+	matched, err := store.ListFiltered(now, "team=sre")
+	if err != nil {
+		t.Fatalf("list filtered: %v", err)
+	}
+	if len(matched) != 2 || matched[0].Name != "monitoring" || matched[1].Name != "sre-tools" {
+		t.Fatalf("unexpected team=sre matches: %+v", matched)
+	}
+
+	matched, err = store.ListFiltered(now, "team=sre, tier=ops")
+	if err != nil {
+		t.Fatalf("list filtered: %v", err)
+	}
+	if len(matched) != 1 || matched[0].Name != "sre-tools" {
+		t.Fatalf("expected requirements to AND together, got %+v", matched)
+	}
+
+	all, err := store.ListFiltered(now, "")
+	if err != nil || len(all) != 4 {
+		t.Fatalf("expected empty selector to return all, got %d (%v)", len(all), err)
+	}
+
+	for _, selector := range []string{"team", "team=sre,", "=sre", "team==sre"} {
+		if _, err := store.ListFiltered(now, selector); err != ErrInvalidSelector {
+			t.Fatalf("expected ErrInvalidSelector for %q, got %v", selector, err)
+		}
 	}
 }
 
@@ -61,3 +251,65 @@ func TestStoreCreateInvalidName(t *testing.T) {
 		t.Fatalf("expected ErrInvalidName, got %v", err)
 	}
 }
+
+func TestStoreGetMatchesList(t *testing.T) {
+	created := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(created)
+	later := created.Add(90 * time.Minute)
+
+	ns, err := store.Get("monitoring", later)
+	if err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+
+	for _, item := range store.List(later) {
+		if item.Name == "monitoring" && item.Age != ns.Age {
+			t.Fatalf("expected detail age %s to match list age %s", ns.Age, item.Age)
+		}
+	}
+
+	if ns.Age != "1d19h" {
+		t.Fatalf("unexpected age %s", ns.Age)
+	}
+
+	if _, err := store.Get("ghost", later); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStoreGetInherited(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if _, err := store.Create("sre-alerts", now, map[string]string{ParentLabel: "monitoring", "tier": "ops"}); err != nil {
+		t.Fatalf("create child namespace: %v", err)
+	}
+
+	ns, err := store.GetInherited("sre-alerts", now)
+	if err != nil {
+		t.Fatalf("get inherited: %v", err)
+	}
+
+	if ns.Labels["team"] != "sre" {
+		t.Fatalf("expected team label inherited from parent, got %v", ns.Labels)
+	}
+
+	if ns.Labels["tier"] != "ops" || ns.Labels["kubernetes.io/metadata.name"] != "sre-alerts" {
+		t.Fatalf("expected own labels to win, got %v", ns.Labels)
+	}
+
+	if _, err := store.Create("loop-a", now, map[string]string{ParentLabel: "loop-b"}); err != nil {
+		t.Fatalf("create loop-a: %v", err)
+	}
+	if _, err := store.Create("loop-b", now, map[string]string{ParentLabel: "loop-a"}); err != nil {
+		t.Fatalf("create loop-b: %v", err)
+	}
+
+	if _, err := store.GetInherited("loop-a", now); err != ErrCycle {
+		t.Fatalf("expected ErrCycle, got %v", err)
+	}
+
+	if _, err := store.GetInherited("ghost", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package node
+
+import "strings"
+
+// RoleLabelPrefix is the label prefix Kubernetes uses to mark node roles.
+const RoleLabelPrefix = "node-role.kubernetes.io/"
+
+// knownRoles lists the role names the dashboard recognises.
+var knownRoles = map[string]bool{
+	"control-plane": true,
+	"master":        true,
+	"worker":        true,
+	"etcd":          true,
+}
+
+// CanonicalRole renders a role in the node-role.kubernetes.io/<role> form.
+func CanonicalRole(role string) string {
+	return RoleLabelPrefix + shortRole(role)
+}
+
+// CanonicalRoles renders every role in canonical form.
+func CanonicalRoles(roles []string) []string {
+	out := make([]string, 0, len(roles))
+	for _, role := range roles {
+		out = append(out, CanonicalRole(role))
+	}
+	return out
+}
+
+// UnknownRoles returns the roles outside the known set so callers setting
+// roles can warn about them.
+func UnknownRoles(roles []string) []string {
+	var unknown []string
+	for _, role := range roles {
+		if !knownRoles[shortRole(role)] {
+			unknown = append(unknown, role)
+		}
+	}
+	return unknown
+}
+
+func shortRole(role string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(role), RoleLabelPrefix))
+}
@@ -3,21 +3,36 @@ package node
 import (
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"k8s_dashboard/internal/age"
+	"k8s_dashboard/internal/names"
 )
 
 // ErrNotFound indicates the node does not exist in the mock store.
 var ErrNotFound = errors.New("node not found")
 
-// UsageMetric describes resource consumption relative to capacity.
+// ErrInvalidSort indicates an unsupported node sort key.
+var ErrInvalidSort = errors.New("invalid sort key")
+
+// ErrInvalidTaint indicates a taint is malformed or duplicates another key.
+var ErrInvalidTaint = errors.New("invalid taint")
+
+var taintRegex = regexp.MustCompile(`^((?:[a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)(=[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?:(NoSchedule|PreferNoSchedule|NoExecute)$`)
+
+// UsageMetric describes resource consumption relative to what is schedulable.
+// Percentage is computed against Allocatable rather than total Capacity.
 type UsageMetric struct {
-	Used       float64 `json:"used"`
-	Capacity   float64 `json:"capacity"`
-	Unit       string  `json:"unit"`
-	Percentage float64 `json:"percentage"`
+	Used        float64 `json:"used"`
+	Capacity    float64 `json:"capacity"`
+	Allocatable float64 `json:"allocatable"`
+	Unit        string  `json:"unit"`
+	Percentage  float64 `json:"percentage"`
 }
 
 // PodSummary reports pod allocation for a node.
@@ -27,6 +42,40 @@ type PodSummary struct {
 	Capacity int `json:"capacity"`
 }
 
+// NodeCapacity breaks down pod capacity for a single node.
+type NodeCapacity struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	Running  int    `json:"running"`
+	Pending  int    `json:"pending"`
+	Free     int    `json:"free"`
+}
+
+// CapacitySummary aggregates pod capacity across all nodes.
+type CapacitySummary struct {
+	Capacity int            `json:"capacity"`
+	Running  int            `json:"running"`
+	Pending  int            `json:"pending"`
+	Free     int            `json:"free"`
+	Nodes    []NodeCapacity `json:"nodes"`
+}
+
+// ReservedResource breaks a node resource into reserved and allocatable parts,
+// where capacity = allocatable + systemReserved + kubeReserved.
+type ReservedResource struct {
+	Capacity       float64 `json:"capacity"`
+	SystemReserved float64 `json:"systemReserved"`
+	KubeReserved   float64 `json:"kubeReserved"`
+	Allocatable    float64 `json:"allocatable"`
+	Unit           string  `json:"unit"`
+}
+
+// Reservations reports the CPU and memory carved out for system daemons.
+type Reservations struct {
+	CPU    ReservedResource `json:"cpu"`
+	Memory ReservedResource `json:"memory"`
+}
+
 // Condition represents the status of a node subsystem.
 type Condition struct {
 	Type           string `json:"type"`
@@ -42,12 +91,54 @@ type NodeSummary struct {
 	Status         string      `json:"status"`
 	Roles          []string    `json:"roles"`
 	Age            string      `json:"age"`
+	AgeSeconds     int64       `json:"ageSeconds"`
 	KubeletVersion string      `json:"kubeletVersion"`
 	CPU            UsageMetric `json:"cpu"`
 	Memory         UsageMetric `json:"memory"`
 	Pods           PodSummary  `json:"pods"`
 }
 
+// NodeFilter narrows the node list. Empty fields match every node; matching is
+// case-insensitive, accepts roles in short or canonical form, and all fields
+// must match when set.
+type NodeFilter struct {
+	Status string
+	Role   string
+
+	// MinHeadroom keeps nodes whose free CPU and memory, as a percentage
+	// of capacity, are both at least this value.
+	MinHeadroom float64
+}
+
+// SortKey selects the field node lists are ordered by.
+type SortKey string
+
+// Supported node sort keys.
+const (
+	SortByName   SortKey = "name"
+	SortByCPU    SortKey = "cpu"
+	SortByMemory SortKey = "memory"
+	SortByPods   SortKey = "pods"
+)
+
+// SortOrder orders node lists by a key, breaking ties by name ascending.
+type SortOrder struct {
+	Key  SortKey
+	Desc bool
+}
+
+// ParseSortKey validates a sort key, defaulting to name when empty.
+func ParseSortKey(value string) (SortKey, error) {
+	switch key := SortKey(strings.ToLower(value)); key {
+	case "":
+		return SortByName, nil
+	case SortByName, SortByCPU, SortByMemory, SortByPods:
+		return key, nil
+	default:
+		return "", ErrInvalidSort
+	}
+}
+
 // NodeDetail extends NodeSummary with additional metadata.
 type NodeDetail struct {
 	NodeSummary
@@ -58,6 +149,8 @@ type NodeDetail struct {
 	Labels           map[string]string `json:"labels"`
 	Taints           []string          `json:"taints"`
 	Conditions       []Condition       `json:"conditions"`
+	Reservations     Reservations      `json:"reservations"`
+	Unschedulable    bool              `json:"unschedulable"`
 }
 
 type record struct {
@@ -70,6 +163,10 @@ type record struct {
 	CPUCapacity      float64
 	MemoryUsed       float64
 	MemoryCapacity   float64
+	SystemCPU        float64
+	SystemMemory     float64
+	KubeCPU          float64
+	KubeMemory       float64
 	PodRunning       int
 	PodPending       int
 	PodCapacity      int
@@ -80,6 +177,7 @@ type record struct {
 	Labels           map[string]string
 	Taints           []string
 	Conditions       []conditionRecord
+	Unschedulable    bool
 }
 
 type conditionRecord struct {
@@ -105,23 +203,92 @@ func NewStore(now time.Time) *Store {
 	return s
 }
 
-// List returns sorted node summaries.
-func (s *Store) List(now time.Time) []NodeSummary {
+// List returns node summaries sorted by name, or by the given order.
+func (s *Store) List(now time.Time, order ...SortOrder) []NodeSummary {
+	return s.ListFiltered(now, NodeFilter{}, order...)
+}
+
+// ListFiltered returns node summaries matching the filter, sorted by name or
+// by the given order.
+func (s *Store) ListFiltered(now time.Time, filter NodeFilter, order ...SortOrder) []NodeSummary {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	result := make([]NodeSummary, 0, len(s.items))
 	for _, rec := range s.items {
-		result = append(result, toSummary(rec, now))
+		if filter.matches(rec) {
+			result = append(result, toSummary(rec, now))
+		}
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return strings.Compare(result[i].Name, result[j].Name) < 0
-	})
+	by := SortOrder{Key: SortByName}
+	if len(order) > 0 {
+		by = order[0]
+	}
+	sortSummaries(result, by)
 
 	return result
 }
 
+func sortSummaries(items []NodeSummary, order SortOrder) {
+	value := func(n NodeSummary) float64 {
+		switch order.Key {
+		case SortByCPU:
+			return n.CPU.Percentage
+		case SortByMemory:
+			return n.Memory.Percentage
+		case SortByPods:
+			return float64(n.Pods.Running)
+		default:
+			return 0
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if vi, vj := value(items[i]), value(items[j]); vi != vj {
+			if order.Desc {
+				return vi > vj
+			}
+			return vi < vj
+		}
+		cmp := strings.Compare(items[i].Name, items[j].Name)
+		if order.Key == SortByName && order.Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+func (f NodeFilter) matches(rec record) bool {
+	if f.Status != "" && !strings.EqualFold(rec.Status, f.Status) {
+		return false
+	}
+	if f.MinHeadroom > 0 && headroom(rec) < f.MinHeadroom {
+		return false
+	}
+	if f.Role == "" {
+		return true
+	}
+	for _, role := range rec.Roles {
+		if shortRole(role) == shortRole(f.Role) {
+			return true
+		}
+	}
+	return false
+}
+
+// headroom is the smaller of the free CPU and free memory percentages,
+// measured against total capacity.
+func headroom(rec record) float64 {
+	free := func(used, capacity float64) float64 {
+		if capacity <= 0 {
+			return 0
+		}
+		return (capacity - used) / capacity * 100
+	}
+	return math.Min(free(rec.CPUUsed, rec.CPUCapacity), free(rec.MemoryUsed, rec.MemoryCapacity))
+}
+
 // Get returns a node detail by name.
 func (s *Store) Get(name string, now time.Time) (NodeDetail, error) {
 	s.mu.RLock()
@@ -135,26 +302,202 @@ func (s *Store) Get(name string, now time.Time) (NodeDetail, error) {
 	return toDetail(rec, now), nil
 }
 
+// SetSchedulable cordons (schedulable=false) or uncordons a node.
+func (s *Store) SetSchedulable(name string, schedulable bool, now time.Time) (NodeDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.items[name]
+	if !ok {
+		return NodeDetail{}, ErrNotFound
+	}
+
+	rec.Unschedulable = !schedulable
+	s.items[name] = rec
+	return toDetail(rec, now), nil
+}
+
+// Drain cordons the node and evicts its pods, recording a Draining condition.
+// Draining an already drained node leaves it unchanged.
+func (s *Store) Drain(name string, now time.Time) (NodeDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.items[name]
+	if !ok {
+		return NodeDetail{}, ErrNotFound
+	}
+
+	rec.Unschedulable = true
+	rec.PodRunning = 0
+	rec.PodPending = 0
+	if !hasCondition(rec.Conditions, "Draining") {
+		rec.Conditions = append(append([]conditionRecord{}, rec.Conditions...), conditionRecord{
+			Type:           "Draining",
+			Status:         "True",
+			Message:        "Node drained, pods evicted",
+			LastHeartbeat:  now,
+			LastTransition: now,
+		})
+	}
+	s.items[name] = rec
+	return toDetail(rec, now), nil
+}
+
+// SetTaints replaces the node taints. Each taint must have the form
+// key[=value]:Effect and keys must be unique.
+func (s *Store) SetTaints(name string, taints []string, now time.Time) (NodeDetail, error) {
+	if err := validateTaints(taints); err != nil {
+		return NodeDetail{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.items[name]
+	if !ok {
+		return NodeDetail{}, ErrNotFound
+	}
+
+	rec.Taints = append([]string{}, taints...)
+	s.items[name] = rec
+	return toDetail(rec, now), nil
+}
+
+func validateTaints(taints []string) error {
+	seen := make(map[string]bool, len(taints))
+	for _, taint := range taints {
+		match := taintRegex.FindStringSubmatch(taint)
+		if match == nil {
+			return fmt.Errorf("%w: %q must match key[=value]:Effect", ErrInvalidTaint, taint)
+		}
+		key := match[1]
+		if len(key) > 253 {
+			return fmt.Errorf("%w: key %q is too long", ErrInvalidTaint, key)
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate key %q", ErrInvalidTaint, key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func hasCondition(conditions []conditionRecord, condType string) bool {
+	for _, c := range conditions {
+		if c.Type == condType {
+			return true
+		}
+	}
+	return false
+}
+
+// ConditionMatrix maps node name to condition type to status. Types lists
+// every condition type seen on any node, sorted; a node missing a type
+// reports it as Unknown.
+type ConditionMatrix struct {
+	Types []string                     `json:"types"`
+	Nodes map[string]map[string]string `json:"nodes"`
+}
+
+// ConditionMatrix returns the condition status of every node as a grid.
+func (s *Store) ConditionMatrix(now time.Time) ConditionMatrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, rec := range s.items {
+		for _, c := range rec.Conditions {
+			seen[c.Type] = true
+		}
+	}
+	out := ConditionMatrix{
+		Types: make([]string, 0, len(seen)),
+		Nodes: make(map[string]map[string]string, len(s.items)),
+	}
+	for t := range seen {
+		out.Types = append(out.Types, t)
+	}
+	sort.Strings(out.Types)
+
+	for _, rec := range s.items {
+		row := make(map[string]string, len(out.Types))
+		for _, t := range out.Types {
+			row[t] = "Unknown"
+		}
+		for _, c := range rec.Conditions {
+			row[c.Type] = c.Status
+		}
+		out.Nodes[rec.Name] = row
+	}
+	return out
+}
+
+// CapacitySummary reports how many more pods fit on the cluster, where free
+// capacity is the pod capacity minus the running pods.
+func (s *Store) CapacitySummary() CapacitySummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := CapacitySummary{Nodes: make([]NodeCapacity, 0, len(s.items))}
+	for _, rec := range s.items {
+		item := NodeCapacity{
+			Name:     rec.Name,
+			Capacity: rec.PodCapacity,
+			Running:  rec.PodRunning,
+			Pending:  rec.PodPending,
+			Free:     freeCapacity(rec.PodCapacity, rec.PodRunning),
+		}
+		out.Capacity += item.Capacity
+		out.Running += item.Running
+		out.Pending += item.Pending
+		out.Nodes = append(out.Nodes, item)
+	}
+	out.Free = freeCapacity(out.Capacity, out.Running)
+
+	sort.Slice(out.Nodes, func(i, j int) bool {
+		return strings.Compare(out.Nodes[i].Name, out.Nodes[j].Name) < 0
+	})
+
+	return out
+}
+
+func freeCapacity(capacity, running int) int {
+	if running >= capacity {
+		return 0
+	}
+	return capacity - running
+}
+
 func toSummary(rec record, now time.Time) NodeSummary {
-	age := formatAge(now.Sub(rec.CreatedAt))
+	cpuAllocatable := allocatable(rec.CPUCapacity, rec.SystemCPU, rec.KubeCPU)
+	memAllocatable := allocatable(rec.MemoryCapacity, rec.SystemMemory, rec.KubeMemory)
 	cpu := UsageMetric{
-		Used:       rec.CPUUsed,
-		Capacity:   rec.CPUCapacity,
-		Unit:       "cores",
-		Percentage: percentage(rec.CPUUsed, rec.CPUCapacity),
+		Used:        rec.CPUUsed,
+		Capacity:    rec.CPUCapacity,
+		Allocatable: cpuAllocatable,
+		Unit:        "cores",
+		Percentage:  percentage(rec.CPUUsed, cpuAllocatable),
 	}
 	mem := UsageMetric{
-		Used:       rec.MemoryUsed,
-		Capacity:   rec.MemoryCapacity,
-		Unit:       "GiB",
-		Percentage: percentage(rec.MemoryUsed, rec.MemoryCapacity),
+		Used:        rec.MemoryUsed,
+		Capacity:    rec.MemoryCapacity,
+		Allocatable: memAllocatable,
+		Unit:        "GiB",
+		Percentage:  percentage(rec.MemoryUsed, memAllocatable),
+	}
+
+	status := rec.Status
+	if rec.Unschedulable {
+		status += ",SchedulingDisabled"
 	}
 
 	return NodeSummary{
 		Name:           rec.Name,
-		Status:         rec.Status,
+		Status:         status,
 		Roles:          append([]string{}, rec.Roles...),
-		Age:            age,
+		Age:            age.Format(now.Sub(rec.CreatedAt)),
+		AgeSeconds:     age.Seconds(now.Sub(rec.CreatedAt)),
 		KubeletVersion: rec.KubeletVersion,
 		CPU:            cpu,
 		Memory:         mem,
@@ -194,9 +537,32 @@ func toDetail(rec record, now time.Time) NodeDetail {
 		Labels:           labels,
 		Taints:           append([]string{}, rec.Taints...),
 		Conditions:       conditions,
+		Unschedulable:    rec.Unschedulable,
+		Reservations: Reservations{
+			CPU:    reserved(rec.CPUCapacity, rec.SystemCPU, rec.KubeCPU, "cores"),
+			Memory: reserved(rec.MemoryCapacity, rec.SystemMemory, rec.KubeMemory, "GiB"),
+		},
+	}
+}
+
+func reserved(capacity, system, kube float64, unit string) ReservedResource {
+	return ReservedResource{
+		Capacity:       capacity,
+		SystemReserved: system,
+		KubeReserved:   kube,
+		Allocatable:    allocatable(capacity, system, kube),
+		Unit:           unit,
 	}
 }
 
+func allocatable(capacity, system, kube float64) float64 {
+	out := capacity - system - kube
+	if out < 0 {
+		return 0
+	}
+	return out
+}
+
 func percentage(used, capacity float64) float64 {
 	if capacity <= 0 {
 		return 0
@@ -225,28 +591,6 @@ func mathPow10(power int) float64 {
 	return result
 }
 
-func formatAge(d time.Duration) string {
-	if d < 0 {
-		d = 0
-	}
-
-	days := d / (24 * time.Hour)
-	d -= days * 24 * time.Hour
-	hours := d / time.Hour
-	d -= hours * time.Hour
-	minutes := d / time.Minute
-
-	switch {
-	case days > 0:
-		return fmt.Sprintf("%dd%dh", days, hours)
-	case hours > 0:
-		return fmt.Sprintf("%dh%dm", hours, minutes)
-	default:
-		secs := int(d/time.Second) % 60
-		return fmt.Sprintf("%dm%ds", minutes, secs)
-	}
-}
-
 func defaultSeed(now time.Time) []record {
 	base := now.Add(-72 * time.Hour)
 
@@ -261,6 +605,10 @@ func defaultSeed(now time.Time) []record {
 			CPUCapacity:      16,
 			MemoryUsed:       48,
 			MemoryCapacity:   128,
+			SystemCPU:        1,
+			SystemMemory:     6,
+			KubeCPU:          0.5,
+			KubeMemory:       6,
 			PodRunning:       45,
 			PodPending:       2,
 			PodCapacity:      110,
@@ -293,6 +641,10 @@ func defaultSeed(now time.Time) []record {
 			CPUCapacity:      32,
 			MemoryUsed:       72,
 			MemoryCapacity:   256,
+			SystemCPU:        2,
+			SystemMemory:     12,
+			KubeCPU:          1,
+			KubeMemory:       12,
 			PodRunning:       68,
 			PodPending:       5,
 			PodCapacity:      150,
@@ -333,6 +685,10 @@ func defaultSeed(now time.Time) []record {
 			CPUCapacity:      16,
 			MemoryUsed:       24,
 			MemoryCapacity:   128,
+			SystemCPU:        1,
+			SystemMemory:     6,
+			KubeCPU:          0.5,
+			KubeMemory:       6,
 			PodRunning:       12,
 			PodPending:       8,
 			PodCapacity:      110,
@@ -365,3 +721,16 @@ func defaultSeed(now time.Time) []record {
 		},
 	}
 }
+
+// NamesWithPrefix returns up to limit sorted, distinct node names starting
+// with prefix, ignoring case.
+func (s *Store) NamesWithPrefix(prefix string, limit int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates := make([]string, 0, len(s.items))
+	for _, rec := range s.items {
+		candidates = append(candidates, rec.Name)
+	}
+	return names.WithPrefix(candidates, prefix, limit)
+}
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -52,3 +53,286 @@ func TestGetDetail(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestCapacitySummary(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	summary := store.CapacitySummary()
+
+	capacity, running := 0, 0
+	for _, n := range store.List(now) {
+		capacity += n.Pods.Capacity
+		running += n.Pods.Running
+	}
+
+	if summary.Capacity != capacity {
+		t.Fatalf("expected capacity %d, got %d", capacity, summary.Capacity)
+	}
+
+	if summary.Free != capacity-running {
+		t.Fatalf("expected free %d, got %d", capacity-running, summary.Free)
+	}
+
+	if len(summary.Nodes) != 3 || summary.Nodes[0].Name != "node-1" {
+		t.Fatalf("unexpected node breakdown: %+v", summary.Nodes)
+	}
+}
+
+func TestDetailReservations(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, err := store.Get("node-2", now)
+	if err != nil {
+		t.Fatalf("get node detail: %v", err)
+	}
+
+	for _, res := range []ReservedResource{detail.Reservations.CPU, detail.Reservations.Memory} {
+		if res.SystemReserved <= 0 || res.KubeReserved <= 0 {
+			t.Fatalf("expected seeded reservations, got %+v", res)
+		}
+		if res.Allocatable+res.SystemReserved+res.KubeReserved != res.Capacity {
+			t.Fatalf("expected allocatable + reserved to equal capacity, got %+v", res)
+		}
+	}
+
+	if detail.Reservations.CPU.Capacity != detail.CPU.Capacity {
+		t.Fatalf("expected reservation capacity to match cpu capacity")
+	}
+}
+
+func TestSetSchedulable(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	cordoned, err := store.SetSchedulable("node-2", false, now)
+	if err != nil {
+		t.Fatalf("cordon node: %v", err)
+	}
+	if !cordoned.Unschedulable || cordoned.Status != "Ready,SchedulingDisabled" {
+		t.Fatalf("unexpected cordoned node: status %s unschedulable %v", cordoned.Status, cordoned.Unschedulable)
+	}
+
+	uncordoned, err := store.SetSchedulable("node-2", true, now)
+	if err != nil {
+		t.Fatalf("uncordon node: %v", err)
+	}
+	if uncordoned.Unschedulable || uncordoned.Status != "Ready" {
+		t.Fatalf("unexpected uncordoned node: status %s unschedulable %v", uncordoned.Status, uncordoned.Unschedulable)
+	}
+
+	if _, err := store.SetSchedulable("missing", false, now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	drained, err := store.Drain("node-2", now)
+	if err != nil {
+		t.Fatalf("drain node: %v", err)
+	}
+
+	if !drained.Unschedulable || drained.Pods.Running != 0 || drained.Pods.Pending != 0 {
+		t.Fatalf("unexpected drained node: %+v", drained.NodeSummary)
+	}
+
+	last := drained.Conditions[len(drained.Conditions)-1]
+	if last.Type != "Draining" || last.Status != "True" {
+		t.Fatalf("expected Draining condition, got %+v", last)
+	}
+
+	again, err := store.Drain("node-2", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("drain node again: %v", err)
+	}
+	if len(again.Conditions) != len(drained.Conditions) {
+		t.Fatalf("expected idempotent drain, got %d conditions", len(again.Conditions))
+	}
+
+	if _, err := store.Drain("missing", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSetTaints(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, err := store.SetTaints("node-2", []string{"dedicated=gpu:NoSchedule", "example.com/spot:PreferNoSchedule"}, now)
+	if err != nil {
+		t.Fatalf("set taints: %v", err)
+	}
+	if len(detail.Taints) != 2 || detail.Taints[0] != "dedicated=gpu:NoSchedule" {
+		t.Fatalf("unexpected taints %v", detail.Taints)
+	}
+
+	for _, taints := range [][]string{
+		{"dedicated=gpu"},
+		{"dedicated=gpu:Sometimes"},
+		{"=gpu:NoSchedule"},
+		{"dedicated=gpu:NoSchedule", "dedicated:NoExecute"},
+	} {
+		if _, err := store.SetTaints("node-2", taints, now); !errors.Is(err, ErrInvalidTaint) {
+			t.Fatalf("expected ErrInvalidTaint for %v, got %v", taints, err)
+		}
+	}
+
+	cleared, err := store.SetTaints("node-2", nil, now)
+	if err != nil {
+		t.Fatalf("clear taints: %v", err)
+	}
+	if len(cleared.Taints) != 0 {
+		t.Fatalf("expected no taints, got %v", cleared.Taints)
+	}
+
+	if _, err := store.SetTaints("missing", nil, now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListFiltered(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if all := store.ListFiltered(now, NodeFilter{}); len(all) != 3 {
+		t.Fatalf("expected 3 nodes without filter, got %d", len(all))
+	}
+
+	notReady := store.ListFiltered(now, NodeFilter{Status: "notready"})
+	if len(notReady) != 1 || notReady[0].Name != "node-3" {
+		t.Fatalf("unexpected NotReady nodes %+v", notReady)
+	}
+
+	workers := store.ListFiltered(now, NodeFilter{Role: "Worker"})
+	if len(workers) != 2 {
+		t.Fatalf("expected 2 workers, got %d", len(workers))
+	}
+
+	readyWorkers := store.ListFiltered(now, NodeFilter{Status: "Ready", Role: "worker"})
+	if len(readyWorkers) != 1 || readyWorkers[0].Name != "node-2" {
+		t.Fatalf("unexpected ready workers %+v", readyWorkers)
+	}
+
+	if none := store.ListFiltered(now, NodeFilter{Status: "NotReady", Role: "control-plane"}); len(none) != 0 {
+		t.Fatalf("expected no nodes, got %+v", none)
+	}
+}
+
+func TestCanonicalRoles(t *testing.T) {
+	roles := CanonicalRoles([]string{"control-plane", "node-role.kubernetes.io/worker"})
+	if roles[0] != "node-role.kubernetes.io/control-plane" || roles[1] != "node-role.kubernetes.io/worker" {
+		t.Fatalf("unexpected canonical roles %v", roles)
+	}
+
+	unknown := UnknownRoles([]string{"worker", "node-role.kubernetes.io/etcd", "gpu"})
+	if len(unknown) != 1 || unknown[0] != "gpu" {
+		t.Fatalf("expected gpu to be unknown, got %v", unknown)
+	}
+}
+
+func TestUsageAgainstAllocatable(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	detail, err := store.Get("node-2", now)
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+
+	if detail.CPU.Capacity != 32 || detail.CPU.Allocatable != 29 {
+		t.Fatalf("unexpected cpu metric %+v", detail.CPU)
+	}
+	if detail.CPU.Percentage != 31 {
+		t.Fatalf("expected cpu percentage 31 against allocatable, got %v", detail.CPU.Percentage)
+	}
+	if detail.Memory.Allocatable != detail.Reservations.Memory.Allocatable {
+		t.Fatalf("expected memory allocatable to match reservations, got %+v", detail.Memory)
+	}
+
+	for _, item := range store.List(now) {
+		if item.Name == "node-2" && item.CPU.Percentage != detail.CPU.Percentage {
+			t.Fatalf("expected list percentage %v, got %v", detail.CPU.Percentage, item.CPU.Percentage)
+		}
+	}
+}
+
+func TestListSorted(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	byCPU := store.List(now, SortOrder{Key: SortByCPU, Desc: true})
+	if byCPU[0].Name != "node-1" || byCPU[2].Name != "node-3" {
+		t.Fatalf("unexpected cpu order %s, %s, %s", byCPU[0].Name, byCPU[1].Name, byCPU[2].Name)
+	}
+
+	byPods := store.List(now, SortOrder{Key: SortByPods})
+	if byPods[0].Name != "node-3" || byPods[2].Name != "node-2" {
+		t.Fatalf("unexpected pods order %s, %s, %s", byPods[0].Name, byPods[1].Name, byPods[2].Name)
+	}
+
+	byName := store.List(now, SortOrder{Key: SortByName, Desc: true})
+	if byName[0].Name != "node-3" {
+		t.Fatalf("expected node-3 first for name desc, got %s", byName[0].Name)
+	}
+
+	if _, err := ParseSortKey("disk"); err != ErrInvalidSort {
+		t.Fatalf("expected ErrInvalidSort, got %v", err)
+	}
+	if key, err := ParseSortKey(""); err != nil || key != SortByName {
+		t.Fatalf("expected default name sort, got %v, %v", key, err)
+	}
+}
+
+func TestConditionMatrix(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	matrix := store.ConditionMatrix(now)
+	if len(matrix.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(matrix.Nodes))
+	}
+	if got := matrix.Nodes["node-3"]["NetworkUnavailable"]; got != "True" {
+		t.Fatalf("expected node-3 NetworkUnavailable True, got %q", got)
+	}
+	if got := matrix.Nodes["node-1"]["NetworkUnavailable"]; got != "Unknown" {
+		t.Fatalf("expected node-1 NetworkUnavailable Unknown, got %q", got)
+	}
+	for name, row := range matrix.Nodes {
+		if len(row) != len(matrix.Types) {
+			t.Fatalf("expected %s to report every type, got %v", name, row)
+		}
+	}
+}
+
+func TestListFilteredMinHeadroom(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	rec := store.items["node-3"]
+	rec.CPUUsed, rec.CPUCapacity = 6.4, 16
+	rec.MemoryUsed, rec.MemoryCapacity = 71.68, 128
+	store.items["node-3"] = rec
+
+	has := func(items []NodeSummary, name string) bool {
+		for _, item := range items {
+			if item.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !has(store.ListFiltered(now, NodeFilter{MinHeadroom: 20}), "node-3") {
+		t.Fatalf("expected node at 40%% CPU / 56%% memory to pass minHeadroom=20")
+	}
+	if has(store.ListFiltered(now, NodeFilter{MinHeadroom: 60}), "node-3") {
+		t.Fatalf("expected node at 40%% CPU / 56%% memory to fail minHeadroom=60")
+	}
+	if got := store.ListFiltered(now, NodeFilter{}); len(got) != 3 {
+		t.Fatalf("expected zero headroom to keep every node, got %d", len(got))
+	}
+}
@@ -0,0 +1,111 @@
+package pod
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"k8s_dashboard/internal/label"
+)
+
+var (
+	// ErrInvalidSelector signals a malformed label selector.
+	ErrInvalidSelector = label.ErrInvalidSelector
+	// ErrInvalidLabels signals a label key or value violates Kubernetes syntax.
+	ErrInvalidLabels = errors.New("invalid pod labels")
+	// ErrReservedLabel signals an attempt to set a system-managed label key.
+	ErrReservedLabel = errors.New("reserved label key")
+)
+
+// reservedLabelKeys are managed by controllers and may not be set by callers.
+var reservedLabelKeys = map[string]bool{
+	"pod-template-hash": true,
+}
+
+// Ref identifies a pod by namespace and name.
+type Ref struct {
+	Namespace string
+	Name      string
+}
+
+// UpdateLabelsBySelector merges labels into every pod matching both the
+// key=value selector and filter, returning the updated pods sorted by
+// namespace and name.
+func (s *Store) UpdateLabelsBySelector(selector string, labels map[string]string, filter PodFilter) ([]Ref, error) {
+	reqs, err := label.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(reqs) == 0 {
+		// An empty selector would relabel every pod, so require one.
+		return nil, ErrInvalidSelector
+	}
+	if len(labels) == 0 {
+		return nil, ErrInvalidLabels
+	}
+	for k, v := range labels {
+		if reservedLabelKey(k) {
+			return nil, ErrReservedLabel
+		}
+		if !label.ValidKey(k) || !label.ValidValue(v) {
+			return nil, ErrInvalidLabels
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var updated []Ref
+	for k, rec := range s.items {
+		if !filter.matches(rec.Summary) || !label.Matches(rec.Labels, reqs) {
+			continue
+		}
+		merged := copyLabels(rec.Labels)
+		if merged == nil {
+			merged = make(map[string]string, len(labels))
+		}
+		for lk, lv := range labels {
+			merged[lk] = lv
+		}
+		rec.Labels = merged
+		s.items[k] = rec
+		s.notify(Modified, rec)
+		updated = append(updated, Ref{Namespace: rec.Namespace, Name: rec.Name})
+	}
+	sort.Slice(updated, func(i, j int) bool {
+		if updated[i].Namespace != updated[j].Namespace {
+			return updated[i].Namespace < updated[j].Namespace
+		}
+		return updated[i].Name < updated[j].Name
+	})
+	return updated, nil
+}
+
+// reservedLabelKey reports whether key belongs to a Kubernetes-owned prefix
+// such as kubernetes.io/ or k8s.io/, or is controller-managed.
+func reservedLabelKey(key string) bool {
+	if reservedLabelKeys[key] {
+		return true
+	}
+	prefix, _, ok := strings.Cut(key, "/")
+	if !ok {
+		return false
+	}
+	for _, domain := range []string{"kubernetes.io", "k8s.io"} {
+		if prefix == domain || strings.HasSuffix(prefix, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyLabels(src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return nil
+	}
+	dst := make(map[string]string, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
@@ -0,0 +1,87 @@
+package pod
+
+import "fmt"
+
+// Reachability reports whether one pod can reach another in the mock network.
+type Reachability struct {
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+	Reachable bool   `json:"reachable"`
+	Reason    string `json:"reason"`
+}
+
+// isolatedNamespaces models a default-deny NetworkPolicy: pods in these
+// namespaces only talk to pods in the same namespace.
+var isolatedNamespaces = map[string]bool{
+	"batch": true,
+}
+
+// Connectivity evaluates mock reachability from the source pod to the target,
+// each looked up like lookup. NetworkPolicy isolation is checked first, then
+// both pods must be Running.
+func (s *Store) Connectivity(sourceNamespace, source, targetNamespace, target string) (Reachability, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	src, err := s.lookup(sourceNamespace, source)
+	if err != nil {
+		return Reachability{}, err
+	}
+	dst, err := s.lookup(targetNamespace, target)
+	if err != nil {
+		return Reachability{}, err
+	}
+
+	result := Reachability{Source: src.Name, Target: dst.Name}
+	crossNamespace := src.Namespace != dst.Namespace
+
+	switch {
+	case crossNamespace && (isolatedNamespaces[src.Namespace] || isolatedNamespaces[dst.Namespace]):
+		result.Reason = fmt.Sprintf("NetworkPolicy denies %s->%s", src.Namespace, dst.Namespace)
+	case src.Status != "Running":
+		result.Reason = fmt.Sprintf("pod %s is %s", src.Name, src.Status)
+	case dst.Status != "Running":
+		result.Reason = fmt.Sprintf("pod %s is %s", dst.Name, dst.Status)
+	default:
+		result.Reachable = true
+		result.Reason = fmt.Sprintf("NetworkPolicy allows %s->%s", src.Namespace, dst.Namespace)
+	}
+
+	return result, nil
+}
+
+// lookup finds the pod by namespace and name, or by name alone through
+// findByName when namespace is empty. Callers must hold the lock.
+func (s *Store) lookup(namespace, name string) (record, error) {
+	if namespace == "" {
+		return s.findByName(name)
+	}
+	rec, ok := s.items[key(namespace, name)]
+	if !ok {
+		return record{}, ErrNotFound
+	}
+	return rec, nil
+}
+
+// findByName returns the only pod with the given name, or ErrAmbiguous when
+// several namespaces share it. Callers must hold the lock.
+func (s *Store) findByName(name string) (record, error) {
+	var (
+		found record
+		count int
+	)
+	for _, rec := range s.items {
+		if rec.Name == name {
+			found = rec
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return record{}, ErrNotFound
+	case 1:
+		return found, nil
+	default:
+		return record{}, ErrAmbiguous
+	}
+}
@@ -2,16 +2,32 @@ package pod
 
 import (
 	"errors"
-	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"k8s_dashboard/internal/age"
+	"k8s_dashboard/internal/names"
+	"k8s_dashboard/internal/scope"
 )
 
 // ErrNotFound indicates the pod does not exist in the store.
 var ErrNotFound = errors.New("pod not found")
 
+// ErrAmbiguous indicates a name-only lookup matched pods in several namespaces.
+var ErrAmbiguous = errors.New("pod name is ambiguous across namespaces")
+
+// ErrUnknownContainer indicates the pod has no container with the given name.
+var ErrUnknownContainer = errors.New("unknown container")
+
+// ErrExists indicates a pod with the same namespace and name already exists.
+var ErrExists = errors.New("pod already exists")
+
+// ErrInvalidSort indicates an unsupported pod sort key.
+var ErrInvalidSort = errors.New("invalid sort key")
+
 // Summary represents the data shown in the pods list view.
 type Summary struct {
 	Name            string   `json:"name"`
@@ -20,10 +36,23 @@ type Summary struct {
 	ReadyContainers string   `json:"readyContainers"`
 	Restarts        int      `json:"restarts"`
 	Age             string   `json:"age"`
+	AgeSeconds      int64    `json:"ageSeconds"`
 	Node            string   `json:"node"`
 	Images          []string `json:"images"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+	Owner  *OwnerReference   `json:"owner,omitempty"`
 }
 
+// OwnerReference names the controller that owns a pod.
+type OwnerReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// NoOwner is the ListGrouped bucket for pods without an owner.
+const NoOwner = "<none>"
+
 // Container describes a single container in the pod detail view.
 type Container struct {
 	Name         string `json:"name"`
@@ -40,12 +69,118 @@ type Event struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// LogLine is a single log line attributed to the container that wrote it.
+type LogLine struct {
+	Container string `json:"container"`
+	Message   string `json:"message"`
+}
+
+// ContainerLogs holds the log stream of one container of a pod.
+type ContainerLogs struct {
+	Pod        string    `json:"pod"`
+	Namespace  string    `json:"namespace"`
+	Container  string    `json:"container"`
+	Containers []string  `json:"containers"`
+	Lines      []LogLine `json:"lines"`
+}
+
+// ResourceUsage reports a pod resource against its request and limit.
+// Percentage is computed against the limit.
+type ResourceUsage struct {
+	Used       int     `json:"used"`
+	Request    int     `json:"request"`
+	Limit      int     `json:"limit"`
+	Unit       string  `json:"unit"`
+	Percentage float64 `json:"percentage"`
+}
+
+// Metrics reports pod CPU and memory usage.
+type Metrics struct {
+	CPU    ResourceUsage `json:"cpu"`
+	Memory ResourceUsage `json:"memory"`
+}
+
 // Detail includes a pod summary plus container info, logs and events.
 type Detail struct {
 	Summary
 	Containers []Container `json:"containers"`
 	Logs       []string    `json:"logs"`
 	Events     []Event     `json:"events"`
+	Metrics    Metrics     `json:"metrics"`
+}
+
+// PodFilter narrows the pod list. Empty fields match every pod; matching is
+// exact but case-insensitive and all set fields must match.
+type PodFilter struct {
+	Namespace string
+	Node      string
+	Status    string
+
+	// Namespaces, when non-empty, restricts results to these namespaces.
+	Namespaces []string
+
+	// Sort orders the results; the zero value keeps namespace/name order.
+	Sort SortKey
+}
+
+// SortKey selects the field pod lists are ordered by.
+type SortKey string
+
+// Supported pod sort keys. SortByAge lists the oldest pods first, comparing
+// creation times rather than the formatted age and breaking ties by name.
+const (
+	SortByName SortKey = "name"
+	SortByAge  SortKey = "age"
+)
+
+// ParseSortKey validates a sort key, defaulting to name when empty.
+func ParseSortKey(value string) (SortKey, error) {
+	switch key := SortKey(strings.ToLower(value)); key {
+	case "":
+		return SortByName, nil
+	case SortByName, SortByAge:
+		return key, nil
+	default:
+		return "", ErrInvalidSort
+	}
+}
+
+// EventFilter narrows a pod's events. Empty fields match every event;
+// matching is exact but case-insensitive.
+type EventFilter struct {
+	Type   string
+	Reason string
+}
+
+func (f EventFilter) matches(ev Event) bool {
+	return matchField(f.Type, ev.Type) && matchField(f.Reason, ev.Reason)
+}
+
+// Pagination bounds for pod lists.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Page selects a window of a sorted pod list. A zero Limit uses
+// DefaultPageLimit and limits above MaxPageLimit are capped.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Normalize applies the default and maximum limit.
+func (p Page) Normalize() Page {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	return p
 }
 
 type record struct {
@@ -53,19 +188,28 @@ type record struct {
 	CreatedAt  time.Time
 	Containers []Container
 	Events     []Event
-	Logs       []string
+	Logs       []LogLine
+	Usage      usage
+}
+
+// usage holds raw resource figures in millicores and MiB.
+type usage struct {
+	CPUUsed, CPURequest, CPULimit          int
+	MemoryUsed, MemoryRequest, MemoryLimit int
 }
 
 // Store keeps in-memory mock pod data.
 type Store struct {
-	mu    sync.RWMutex
-	items map[string]record
+	mu       sync.RWMutex
+	items    map[string]record
+	watchers map[*watcher]struct{}
 }
 
 // NewStore seeds pods with deterministic data.
 func NewStore(now time.Time) *Store {
 	s := &Store{items: make(map[string]record)}
 	for _, rec := range defaultSeed(now) {
+		rec.Owner = ownerFromName(rec.Name)
 		s.items[key(rec.Namespace, rec.Name)] = rec
 	}
 	return s
@@ -81,40 +225,369 @@ func (s *Store) List(now time.Time) []Summary {
 		summaries = append(summaries, decorateSummary(rec.Summary, rec.CreatedAt, now))
 	}
 
+	sortSummaries(summaries)
+	return summaries
+}
+
+func sortSummaries(summaries []Summary) {
 	sort.Slice(summaries, func(i, j int) bool {
 		if summaries[i].Namespace == summaries[j].Namespace {
 			return strings.Compare(summaries[i].Name, summaries[j].Name) < 0
 		}
 		return strings.Compare(summaries[i].Namespace, summaries[j].Namespace) < 0
 	})
+}
 
-	return summaries
+// ListFiltered returns pods matching the filter, sorted by namespace/name
+// unless the filter selects another sort key.
+func (s *Store) ListFiltered(now time.Time, filter PodFilter) []Summary {
+	if filter.Sort == SortByAge {
+		return s.listByAge(now, filter)
+	}
+
+	all := s.List(now)
+	out := make([]Summary, 0, len(all))
+	for _, item := range all {
+		if filter.matches(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (s *Store) listByAge(now time.Time, filter PodFilter) []Summary {
+	s.mu.RLock()
+	matched := make([]record, 0, len(s.items))
+	for _, rec := range s.items {
+		if filter.matches(rec.Summary) {
+			matched = append(matched, rec)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+		}
+		return strings.Compare(matched[i].Name, matched[j].Name) < 0
+	})
+
+	out := make([]Summary, 0, len(matched))
+	for _, rec := range matched {
+		out = append(out, decorateSummary(rec.Summary, rec.CreatedAt, now))
+	}
+	return out
+}
+
+// ListPage returns one page of the pods matching the filter, sliced after
+// sorting so pages stay stable, along with the total number of matches.
+func (s *Store) ListPage(now time.Time, filter PodFilter, page Page) ([]Summary, int) {
+	all := s.ListFiltered(now, filter)
+	page = page.Normalize()
+
+	start := min(page.Offset, len(all))
+	end := min(start+page.Limit, len(all))
+	return all[start:end], len(all)
+}
+
+// PodName is the lightweight projection of a pod used by selectors.
+type PodName struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ListNames returns the name and namespace of every pod, sorted by
+// namespace/name. A non-empty namespace restricts the result to it.
+func (s *Store) ListNames(namespace string) []PodName {
+	s.mu.RLock()
+	names := make([]PodName, 0, len(s.items))
+	for _, rec := range s.items {
+		if matchField(namespace, rec.Namespace) {
+			names = append(names, PodName{Name: rec.Name, Namespace: rec.Namespace})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Namespace == names[j].Namespace {
+			return names[i].Name < names[j].Name
+		}
+		return names[i].Namespace < names[j].Namespace
+	})
+	return names
+}
+
+// ListGrouped returns pods keyed by their lowercase namespace/kind/name
+// owner, so same-named owners in different namespaces stay apart, with
+// unowned pods under NoOwner. Pods are sorted by name within each group.
+func (s *Store) ListGrouped(now time.Time) map[string][]Summary {
+	grouped := make(map[string][]Summary)
+	for _, item := range s.List(now) {
+		group := NoOwner
+		if item.Owner != nil {
+			group = strings.ToLower(item.Namespace + "/" + item.Owner.Kind + "/" + item.Owner.Name)
+		}
+		grouped[group] = append(grouped[group], item)
+	}
+	for _, items := range grouped {
+		sort.Slice(items, func(i, j int) bool {
+			return strings.Compare(items[i].Name, items[j].Name) < 0
+		})
+	}
+	return grouped
+}
+
+// ownerFromName derives the owning deployment from a
+// <deployment>-<replicaset hash>-<suffix> pod name.
+func ownerFromName(name string) *OwnerReference {
+	parts := strings.Split(name, "-")
+	if len(parts) < 3 {
+		return nil
+	}
+	return &OwnerReference{Kind: "Deployment", Name: strings.Join(parts[:len(parts)-2], "-")}
 }
 
-// Get fetches pod detail by name (unique across cluster for this mock).
+func (f PodFilter) matches(sum Summary) bool {
+	return scope.Allows(f.Namespaces, sum.Namespace) &&
+		matchField(f.Namespace, sum.Namespace) &&
+		matchField(f.Node, sum.Node) &&
+		matchField(f.Status, sum.Status)
+}
+
+func matchField(want, got string) bool {
+	return want == "" || strings.EqualFold(want, got)
+}
+
+// Get fetches pod detail by name, returning ErrAmbiguous when the name exists
+// in more than one namespace.
 func (s *Store) Get(name string, now time.Time) (Detail, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	rec, err := s.findByName(name)
+	if err != nil {
+		return Detail{}, err
+	}
+	return toDetail(rec, now), nil
+}
+
+// Resolve returns the namespace of the only pod called name among the
+// namespaces allowed by scope.Allows, so copies elsewhere are ignored. It
+// returns ErrNotFound when no allowed copy exists and ErrAmbiguous when
+// several do.
+func (s *Store) Resolve(name string, namespaces []string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found, count := "", 0
 	for _, rec := range s.items {
-		if rec.Name == name {
-			summary := decorateSummary(rec.Summary, rec.CreatedAt, now)
-			detail := Detail{
-				Summary:    summary,
-				Containers: append([]Container{}, rec.Containers...),
-				Logs:       append([]string{}, rec.Logs...),
-				Events:     decorateEvents(rec.Events, now),
-			}
-			return detail, nil
+		if rec.Name == name && scope.Allows(namespaces, rec.Namespace) {
+			found = rec.Namespace
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return "", ErrNotFound
+	case 1:
+		return found, nil
+	default:
+		return "", ErrAmbiguous
+	}
+}
+
+// GetNamespaced fetches pod detail by namespace and name.
+func (s *Store) GetNamespaced(namespace, name string, now time.Time) (Detail, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.items[key(namespace, name)]
+	if !ok {
+		return Detail{}, ErrNotFound
+	}
+	return toDetail(rec, now), nil
+}
+
+// Add stores a new pod created at the given time.
+func (s *Store) Add(detail Detail, createdAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(detail.Namespace, detail.Name)
+	if _, ok := s.items[k]; ok {
+		return ErrExists
+	}
+
+	summary := detail.Summary
+	summary.Age = ""
+	summary.Labels = copyLabels(summary.Labels)
+	if summary.Owner == nil {
+		summary.Owner = ownerFromName(summary.Name)
+	}
+	rec := record{
+		Summary:    summary,
+		CreatedAt:  createdAt,
+		Containers: append([]Container{}, detail.Containers...),
+		Events:     append([]Event{}, detail.Events...),
+	}
+	// Detail logs carry no attribution, so credit them to the first container.
+	if len(detail.Logs) > 0 && len(detail.Containers) > 0 {
+		for _, line := range detail.Logs {
+			rec.Logs = append(rec.Logs, LogLine{Container: detail.Containers[0].Name, Message: line})
+		}
+	}
+	s.items[k] = rec
+	s.notify(Added, rec)
+	return nil
+}
+
+// Logs returns the log lines of one container of the named pod, looked up
+// like lookup. An empty container selects the first container.
+func (s *Store) Logs(namespace, name, container string) (ContainerLogs, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.lookup(namespace, name)
+	if err != nil {
+		return ContainerLogs{}, err
+	}
+
+	names := make([]string, 0, len(rec.Containers))
+	for _, c := range rec.Containers {
+		names = append(names, c.Name)
+	}
+	out := ContainerLogs{
+		Pod:        rec.Name,
+		Namespace:  rec.Namespace,
+		Container:  container,
+		Containers: names,
+		Lines:      []LogLine{},
+	}
+	if out.Container == "" && len(names) > 0 {
+		out.Container = names[0]
+	}
+
+	if !slices.Contains(names, out.Container) {
+		return out, ErrUnknownContainer
+	}
+
+	for _, line := range rec.Logs {
+		if line.Container == out.Container {
+			out.Lines = append(out.Lines, line)
+		}
+	}
+	return out, nil
+}
+
+// Delete removes the pod with the given name. It returns false when no pod
+// matches or the name is ambiguous across namespaces.
+func (s *Store) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.findByName(name)
+	if err != nil {
+		return false
+	}
+	delete(s.items, key(rec.Namespace, rec.Name))
+	s.notify(Deleted, rec)
+	return true
+}
+
+// DeleteNamespaced removes the pod with the given namespace and name.
+func (s *Store) DeleteNamespaced(namespace, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(namespace, name)
+	rec, ok := s.items[k]
+	if !ok {
+		return false
+	}
+	delete(s.items, k)
+	s.notify(Deleted, rec)
+	return true
+}
+
+func toDetail(rec record, now time.Time) Detail {
+	return Detail{
+		Summary:    decorateSummary(rec.Summary, rec.CreatedAt, now),
+		Containers: append([]Container{}, rec.Containers...),
+		Logs:       logMessages(rec.Logs),
+		Events:     decorateEvents(rec.Events, now),
+		Metrics:    toMetrics(rec.Usage),
+	}
+}
+
+// Events returns the page of the named pod's events matching filter, along
+// with the total number of matches. The pod is looked up like lookup.
+func (s *Store) Events(namespace, name string, now time.Time, filter EventFilter, page Page) ([]Event, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.lookup(namespace, name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	all := make([]Event, 0, len(rec.Events))
+	for _, ev := range decorateEvents(rec.Events, now) {
+		if filter.matches(ev) {
+			all = append(all, ev)
 		}
 	}
+	page = page.Normalize()
+	start := min(page.Offset, len(all))
+	end := min(start+page.Limit, len(all))
+	return all[start:end], len(all), nil
+}
+
+// Metrics returns the resource usage of the named pod, looked up like
+// lookup.
+func (s *Store) Metrics(namespace, name string) (Metrics, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	return Detail{}, ErrNotFound
+	rec, err := s.lookup(namespace, name)
+	if err != nil {
+		return Metrics{}, err
+	}
+	return toMetrics(rec.Usage), nil
+}
+
+func toMetrics(u usage) Metrics {
+	return Metrics{
+		CPU:    resourceUsage(u.CPUUsed, u.CPURequest, u.CPULimit, "m"),
+		Memory: resourceUsage(u.MemoryUsed, u.MemoryRequest, u.MemoryLimit, "MiB"),
+	}
+}
+
+func resourceUsage(used, request, limit int, unit string) ResourceUsage {
+	out := ResourceUsage{Used: used, Request: request, Limit: limit, Unit: unit}
+	if limit > 0 {
+		pct := float64(used) / float64(limit) * 100
+		out.Percentage = float64(int64(pct*10+0.5)) / 10
+	}
+	return out
+}
+
+func logMessages(lines []LogLine) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		out = append(out, line.Message)
+	}
+	return out
 }
 
 func decorateSummary(sum Summary, createdAt, now time.Time) Summary {
 	out := sum
-	out.Age = formatAge(now.Sub(createdAt))
+	out.Age = age.Format(now.Sub(createdAt))
+	out.AgeSeconds = age.Seconds(now.Sub(createdAt))
+	out.Labels = copyLabels(sum.Labels)
+	if sum.Owner != nil {
+		owner := *sum.Owner
+		out.Owner = &owner
+	}
 	return out
 }
 
@@ -134,28 +607,6 @@ func key(namespace, name string) string {
 	return namespace + "/" + name
 }
 
-func formatAge(d time.Duration) string {
-	if d < 0 {
-		d = 0
-	}
-
-	days := d / (24 * time.Hour)
-	d -= days * 24 * time.Hour
-	hours := d / time.Hour
-	d -= hours * time.Hour
-	minutes := d / time.Minute
-
-	switch {
-	case days > 0:
-		return fmt.Sprintf("%dd%dh", days, hours)
-	case hours > 0:
-		return fmt.Sprintf("%dh%dm", hours, minutes)
-	default:
-		secs := int(d/time.Second) % 60
-		return fmt.Sprintf("%dm%ds", minutes, secs)
-	}
-}
-
 func defaultSeed(now time.Time) []record {
 	base := now.Add(-6 * time.Hour)
 
@@ -170,22 +621,54 @@ func defaultSeed(now time.Time) []record {
 				Age:             "",
 				Node:            "node-2",
 				Images:          []string{"nginx:1.25", "busybox:1.36"},
+				Labels:          map[string]string{"app": "frontend", "pod-template-hash": "7d8fdc9f7c"},
 			},
 			CreatedAt: base,
 			Containers: []Container{
 				{Name: "frontend", Image: "nginx:1.25", Ready: true, RestartCount: 1},
 				{Name: "sidecar", Image: "busybox:1.36", Ready: true, RestartCount: 0},
 			},
-			Logs: []string{
-				"[INFO] 10:15:01 request handled /",
-				"[INFO] 10:15:02 request handled /healthz",
-				"[WARN] 10:16:12 upstream latency 240ms",
+			Logs: []LogLine{
+				{Container: "frontend", Message: "[INFO] 10:15:01 request handled /"},
+				{Container: "frontend", Message: "[INFO] 10:15:02 request handled /healthz"},
+				{Container: "frontend", Message: "[WARN] 10:16:12 upstream latency 240ms"},
+				{Container: "sidecar", Message: "[INFO] 10:15:00 shipping access logs"},
+				{Container: "sidecar", Message: "[INFO] 10:16:00 flushed 120 records"},
 			},
+			Usage: usage{CPUUsed: 180, CPURequest: 250, CPULimit: 500, MemoryUsed: 210, MemoryRequest: 256, MemoryLimit: 512},
 			Events: []Event{
 				{Type: "Normal", Reason: "Pulled", Message: "Successfully pulled image nginx:1.25"},
 				{Type: "Normal", Reason: "Started", Message: "Started container frontend"},
 			},
 		},
+		{
+			Summary: Summary{
+				Name:            "frontend-7d8fdc9f7c-def34",
+				Namespace:       "default",
+				Status:          "Running",
+				ReadyContainers: "2/2",
+				Restarts:        0,
+				Age:             "",
+				Node:            "node-3",
+				Images:          []string{"nginx:1.25", "busybox:1.36"},
+				Labels:          map[string]string{"app": "frontend", "pod-template-hash": "7d8fdc9f7c"},
+			},
+			CreatedAt: base.Add(20 * time.Minute),
+			Containers: []Container{
+				{Name: "frontend", Image: "nginx:1.25", Ready: true, RestartCount: 0},
+				{Name: "sidecar", Image: "busybox:1.36", Ready: true, RestartCount: 0},
+			},
+			Logs: []LogLine{
+				{Container: "frontend", Message: "[INFO] 10:15:07 request handled /api/cart"},
+				{Container: "frontend", Message: "[WARN] 10:16:30 upstream latency 430ms"},
+				{Container: "sidecar", Message: "[INFO] 10:15:05 shipping access logs"},
+			},
+			Usage: usage{CPUUsed: 140, CPURequest: 250, CPULimit: 500, MemoryUsed: 190, MemoryRequest: 256, MemoryLimit: 512},
+			Events: []Event{
+				{Type: "Normal", Reason: "Pulled", Message: "Container image nginx:1.25 already present on machine"},
+				{Type: "Normal", Reason: "Started", Message: "Started container frontend"},
+			},
+		},
 		{
 			Summary: Summary{
 				Name:            "backend-76c4d5f6d6-xyz89",
@@ -196,16 +679,18 @@ func defaultSeed(now time.Time) []record {
 				Age:             "",
 				Node:            "node-3",
 				Images:          []string{"golang:1.21"},
+				Labels:          map[string]string{"app": "backend", "pod-template-hash": "76c4d5f6d6"},
 			},
 			CreatedAt: base.Add(-2 * time.Hour),
 			Containers: []Container{
 				{Name: "backend", Image: "golang:1.21", Ready: true, RestartCount: 0},
 			},
-			Logs: []string{
-				"[INFO] 09:10:04 processed job 2384",
-				"[INFO] 09:12:51 processed job 2385",
-				"[INFO] 09:15:13 cache warmup complete",
+			Logs: []LogLine{
+				{Container: "backend", Message: "[INFO] 09:10:04 processed job 2384"},
+				{Container: "backend", Message: "[INFO] 09:12:51 processed job 2385"},
+				{Container: "backend", Message: "[INFO] 09:15:13 cache warmup complete"},
 			},
+			Usage: usage{CPUUsed: 620, CPURequest: 500, CPULimit: 1000, MemoryUsed: 740, MemoryRequest: 512, MemoryLimit: 1024},
 			Events: []Event{
 				{Type: "Normal", Reason: "ScalingReplicaSet", Message: "Scaled up replica set backend-76c4d5f6d6 to 3"},
 			},
@@ -220,17 +705,35 @@ func defaultSeed(now time.Time) []record {
 				Age:             "",
 				Node:            "",
 				Images:          []string{"python:3.12"},
+				Labels:          map[string]string{"app": "jobs-runner", "pod-template-hash": "bb7d67f4f6"},
 			},
 			CreatedAt: base.Add(-30 * time.Minute),
 			Containers: []Container{
 				{Name: "worker", Image: "python:3.12", Ready: false, RestartCount: 0},
 			},
-			Logs: []string{
-				"[INFO] job queued",
+			Logs: []LogLine{
+				{Container: "worker", Message: "[INFO] job queued"},
 			},
+			Usage: usage{CPUUsed: 0, CPURequest: 1000, CPULimit: 2000, MemoryUsed: 0, MemoryRequest: 2048, MemoryLimit: 4096},
 			Events: []Event{
 				{Type: "Warning", Reason: "FailedScheduling", Message: "0/3 nodes available: insufficient memory."},
 			},
 		},
 	}
 }
+
+// NamesWithPrefix returns up to limit sorted, distinct pod names starting
+// with prefix, ignoring case. Namespaces, when non-empty, restricts the
+// search to those namespaces.
+func (s *Store) NamesWithPrefix(prefix string, namespaces []string, limit int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates := make([]string, 0, len(s.items))
+	for _, rec := range s.items {
+		if scope.Allows(namespaces, rec.Namespace) {
+			candidates = append(candidates, rec.Name)
+		}
+	}
+	return names.WithPrefix(candidates, prefix, limit)
+}
@@ -1,6 +1,9 @@
 package pod
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -10,8 +13,8 @@ func TestListOrdering(t *testing.T) {
 	store := NewStore(now)
 
 	pods := store.List(now)
-	if len(pods) != 3 {
-		t.Fatalf("expected 3 pods, got %d", len(pods))
+	if len(pods) != 4 {
+		t.Fatalf("expected 4 pods, got %d", len(pods))
 	}
 
 	if pods[0].Namespace != "batch" {
@@ -48,3 +51,414 @@ func TestGetDetail(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestConnectivity(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	denied, err := store.Connectivity("", "jobs-runner-bb7d67f4f6-123zt", "", "frontend-7d8fdc9f7c-abc12")
+	if err != nil {
+		t.Fatalf("connectivity: %v", err)
+	}
+	if denied.Reachable || denied.Reason != "NetworkPolicy denies batch->default" {
+		t.Fatalf("expected cross-namespace denial, got %+v", denied)
+	}
+
+	allowed, err := store.Connectivity("", "frontend-7d8fdc9f7c-abc12", "", "frontend-7d8fdc9f7c-def34")
+	if err != nil {
+		t.Fatalf("connectivity: %v", err)
+	}
+	if !allowed.Reachable {
+		t.Fatalf("expected intra-namespace allow, got %+v", allowed)
+	}
+
+	if _, err := store.Connectivity("", "frontend-7d8fdc9f7c-abc12", "", "ghost"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListFiltered(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if all := store.ListFiltered(now, PodFilter{}); len(all) != 4 {
+		t.Fatalf("expected 4 pods without filter, got %d", len(all))
+	}
+
+	onNode3 := store.ListFiltered(now, PodFilter{Node: "NODE-3"})
+	if len(onNode3) != 2 {
+		t.Fatalf("expected 2 pods on node-3, got %d", len(onNode3))
+	}
+
+	prod := store.ListFiltered(now, PodFilter{Namespace: "prod", Node: "node-3", Status: "running"})
+	if len(prod) != 1 || prod[0].Namespace != "prod" {
+		t.Fatalf("unexpected prod pods %+v", prod)
+	}
+
+	if none := store.ListFiltered(now, PodFilter{Namespace: "prod", Node: "node-2"}); len(none) != 0 {
+		t.Fatalf("expected no pods, got %+v", none)
+	}
+}
+
+func TestGetNamespacedDisambiguates(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	store.items[key("prod", "frontend-7d8fdc9f7c-abc12")] = record{
+		Summary: Summary{
+			Name:      "frontend-7d8fdc9f7c-abc12",
+			Namespace: "prod",
+			Status:    "Pending",
+		},
+		CreatedAt: now,
+	}
+
+	if _, err := store.Get("frontend-7d8fdc9f7c-abc12", now); err != ErrAmbiguous {
+		t.Fatalf("expected ErrAmbiguous, got %v", err)
+	}
+
+	detail, err := store.GetNamespaced("prod", "frontend-7d8fdc9f7c-abc12", now)
+	if err != nil {
+		t.Fatalf("get namespaced pod: %v", err)
+	}
+	if detail.Namespace != "prod" || detail.Status != "Pending" {
+		t.Fatalf("unexpected pod %+v", detail.Summary)
+	}
+
+	if _, err := store.GetNamespaced("batch", "frontend-7d8fdc9f7c-abc12", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if !store.Delete("frontend-7d8fdc9f7c-abc12") {
+		t.Fatalf("expected delete to succeed")
+	}
+	if store.Delete("frontend-7d8fdc9f7c-abc12") {
+		t.Fatalf("expected second delete to fail")
+	}
+	for _, p := range store.List(now) {
+		if p.Name == "frontend-7d8fdc9f7c-abc12" {
+			t.Fatalf("expected pod to be removed from list")
+		}
+	}
+
+	if store.DeleteNamespaced("default", "jobs-runner-bb7d67f4f6-123zt") {
+		t.Fatalf("expected namespaced delete in wrong namespace to fail")
+	}
+	if !store.DeleteNamespaced("batch", "jobs-runner-bb7d67f4f6-123zt") {
+		t.Fatalf("expected namespaced delete to succeed")
+	}
+	if len(store.List(now)) != 2 {
+		t.Fatalf("expected 2 pods left, got %d", len(store.List(now)))
+	}
+}
+
+func TestContainerLogs(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	first, err := store.Logs("", "frontend-7d8fdc9f7c-abc12", "")
+	if err != nil {
+		t.Fatalf("get default container logs: %v", err)
+	}
+	if first.Container != "frontend" || len(first.Lines) != 3 {
+		t.Fatalf("unexpected default logs %+v", first)
+	}
+
+	sidecar, err := store.Logs("", "frontend-7d8fdc9f7c-abc12", "sidecar")
+	if err != nil {
+		t.Fatalf("get sidecar logs: %v", err)
+	}
+	for _, line := range sidecar.Lines {
+		if line.Container != "sidecar" {
+			t.Fatalf("expected only sidecar lines, got %+v", line)
+		}
+	}
+	if len(sidecar.Lines) == 0 {
+		t.Fatalf("expected sidecar lines")
+	}
+
+	unknown, err := store.Logs("", "frontend-7d8fdc9f7c-abc12", "proxy")
+	if err != ErrUnknownContainer {
+		t.Fatalf("expected ErrUnknownContainer, got %v", err)
+	}
+	if len(unknown.Containers) != 2 {
+		t.Fatalf("expected valid container names, got %v", unknown.Containers)
+	}
+
+	if _, err := store.Logs("", "missing", ""); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	metrics, err := store.Metrics("", "backend-76c4d5f6d6-xyz89")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	if metrics.CPU.Used != 620 || metrics.CPU.Limit != 1000 || metrics.CPU.Percentage != 62 {
+		t.Fatalf("unexpected cpu metrics %+v", metrics.CPU)
+	}
+	if metrics.Memory.Unit != "MiB" || metrics.Memory.Request != 512 {
+		t.Fatalf("unexpected memory metrics %+v", metrics.Memory)
+	}
+
+	detail, err := store.Get("backend-76c4d5f6d6-xyz89", now)
+	if err != nil {
+		t.Fatalf("get detail: %v", err)
+	}
+	if detail.Metrics != metrics {
+		t.Fatalf("expected detail metrics to match, got %+v", detail.Metrics)
+	}
+
+	if _, err := store.Metrics("", "missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	initial, events, stop := store.Watch(now, PodFilter{Namespace: "default"})
+	defer stop()
+	if len(initial) != 2 {
+		t.Fatalf("expected 2 initial default pods, got %d", len(initial))
+	}
+
+	if err := store.Add(Detail{Summary: Summary{Name: "worker-1", Namespace: "batch"}}, now); err != nil {
+		t.Fatalf("add batch pod: %v", err)
+	}
+	if err := store.Add(Detail{Summary: Summary{Name: "web-1", Namespace: "default"}}, now); err != nil {
+		t.Fatalf("add default pod: %v", err)
+	}
+	store.DeleteNamespaced("default", "web-1")
+
+	if ev := <-events; ev.Type != Added || ev.Object.Name != "web-1" {
+		t.Fatalf("expected ADDED web-1, got %+v", ev)
+	}
+	if ev := <-events; ev.Type != Deleted || ev.Object.Name != "web-1" {
+		t.Fatalf("expected DELETED web-1, got %+v", ev)
+	}
+
+	stop()
+	if _, ok := <-events; ok {
+		t.Fatalf("expected channel closed after stop")
+	}
+}
+
+func TestWatchOverflowCloses(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	_, events, stop := store.Watch(now, PodFilter{Namespace: "overflow"})
+	defer stop()
+	for i := 0; i <= watchBuffer; i++ {
+		if err := store.Add(Detail{Summary: Summary{Name: fmt.Sprintf("burst-%d", i), Namespace: "overflow"}}, now); err != nil {
+			t.Fatalf("add burst-%d: %v", i, err)
+		}
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != watchBuffer {
+		t.Fatalf("expected %d buffered events before close, got %d", watchBuffer, received)
+	}
+}
+
+func TestListPage(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	page, total := store.ListPage(now, PodFilter{}, Page{Limit: 2, Offset: 1})
+	if total != 4 || len(page) != 2 {
+		t.Fatalf("expected 2 of 4 pods, got %d of %d", len(page), total)
+	}
+	all := store.List(now)
+	if page[0].Name != all[1].Name || page[1].Name != all[2].Name {
+		t.Fatalf("expected stable page order, got %s, %s", page[0].Name, page[1].Name)
+	}
+
+	if past, _ := store.ListPage(now, PodFilter{}, Page{Offset: 10}); len(past) != 0 {
+		t.Fatalf("expected empty page past the end, got %d", len(past))
+	}
+
+	if p := (Page{Limit: 500}).Normalize(); p.Limit != MaxPageLimit {
+		t.Fatalf("expected limit capped at %d, got %d", MaxPageLimit, p.Limit)
+	}
+	if p := (Page{}).Normalize(); p.Limit != DefaultPageLimit {
+		t.Fatalf("expected default limit %d, got %d", DefaultPageLimit, p.Limit)
+	}
+}
+
+func TestListFilteredSortByAge(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	for name, age := range map[string]time.Duration{"young": 9 * time.Minute, "old": 12 * time.Minute, "twin": 12 * time.Minute} {
+		if err := store.Add(Detail{Summary: Summary{Name: name, Namespace: "ages"}}, now.Add(-age)); err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+	}
+
+	pods := store.ListFiltered(now, PodFilter{Namespace: "ages", Sort: SortByAge})
+	if len(pods) != 3 {
+		t.Fatalf("expected 3 pods, got %d", len(pods))
+	}
+	if pods[0].Name != "old" || pods[1].Name != "twin" || pods[2].Name != "young" {
+		t.Fatalf("expected old, twin, young, got %s, %s, %s", pods[0].Name, pods[1].Name, pods[2].Name)
+	}
+	if pods[0].AgeSeconds != 720 || pods[2].AgeSeconds != 540 {
+		t.Fatalf("unexpected ages %d and %d", pods[0].AgeSeconds, pods[2].AgeSeconds)
+	}
+
+	if _, err := ParseSortKey("restarts"); err != ErrInvalidSort {
+		t.Fatalf("expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestListNames(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	all := store.ListNames("")
+	if len(all) != 4 {
+		t.Fatalf("expected 4 pod names, got %d", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1], all[i]
+		if prev.Namespace > cur.Namespace || (prev.Namespace == cur.Namespace && prev.Name > cur.Name) {
+			t.Fatalf("expected names sorted by namespace/name, got %+v", all)
+		}
+	}
+
+	prod := store.ListNames("prod")
+	if len(prod) != 1 || prod[0].Namespace != "prod" {
+		t.Fatalf("unexpected prod names %+v", prod)
+	}
+}
+
+func TestListGrouped(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	if err := store.Add(Detail{Summary: Summary{Name: "debug", Namespace: "default"}}, now); err != nil {
+		t.Fatalf("add pod: %v", err)
+	}
+	if err := store.Add(Detail{Summary: Summary{Name: "frontend-7d8fdc9f7c-zz999", Namespace: "staging"}}, now); err != nil {
+		t.Fatalf("add pod: %v", err)
+	}
+
+	grouped := store.ListGrouped(now)
+	if staging := grouped["staging/deployment/frontend"]; len(staging) != 1 || staging[0].Namespace != "staging" {
+		t.Fatalf("expected staging frontend in its own group, got %+v", staging)
+	}
+	frontend := grouped["default/deployment/frontend"]
+	if len(frontend) != 2 || frontend[0].Name != "frontend-7d8fdc9f7c-abc12" || frontend[1].Name != "frontend-7d8fdc9f7c-def34" {
+		t.Fatalf("unexpected frontend group %+v", frontend)
+	}
+	if frontend[0].Owner == nil || frontend[0].Owner.Kind != "Deployment" {
+		t.Fatalf("expected deployment owner, got %+v", frontend[0].Owner)
+	}
+	if none := grouped[NoOwner]; len(none) != 1 || none[0].Name != "debug" {
+		t.Fatalf("unexpected unowned group %+v", none)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	events, total, err := store.Events("", "jobs-runner-bb7d67f4f6-123zt", now, EventFilter{Type: "warning"}, Page{})
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if total != 1 || len(events) != 1 || events[0].Reason != "FailedScheduling" {
+		t.Fatalf("unexpected warning events %+v (total %d)", events, total)
+	}
+
+	events, total, err = store.Events("", "frontend-7d8fdc9f7c-abc12", now, EventFilter{}, Page{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if total < 2 || len(events) != 1 {
+		t.Fatalf("expected a single event page, got %+v (total %d)", events, total)
+	}
+
+	events, _, err = store.Events("", "frontend-7d8fdc9f7c-abc12", now, EventFilter{Type: "Warning"}, Page{})
+	if err != nil || events == nil || len(events) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %v (%v)", events, err)
+	}
+
+	if _, _, err := store.Events("", "missing", now, EventFilter{}, Page{}); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateLabelsBySelector(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	updated, err := store.UpdateLabelsBySelector("app=frontend", map[string]string{"canary": "true"}, PodFilter{})
+	if err != nil {
+		t.Fatalf("update labels: %v", err)
+	}
+	want := []Ref{{Namespace: "default", Name: "frontend-7d8fdc9f7c-abc12"}, {Namespace: "default", Name: "frontend-7d8fdc9f7c-def34"}}
+	if !reflect.DeepEqual(updated, want) {
+		t.Fatalf("expected %v updated, got %v", want, updated)
+	}
+	for _, p := range store.List(now) {
+		want := p.Labels["app"] == "frontend"
+		if (p.Labels["canary"] == "true") != want {
+			t.Fatalf("unexpected labels on %s: %v", p.Name, p.Labels)
+		}
+	}
+
+	for _, key := range []string{"pod-template-hash", "kubernetes.io/hostname", "node.k8s.io/zone"} {
+		if _, err := store.UpdateLabelsBySelector("app=frontend", map[string]string{key: "x"}, PodFilter{}); err != ErrReservedLabel {
+			t.Fatalf("expected ErrReservedLabel for %s, got %v", key, err)
+		}
+	}
+	if _, err := store.UpdateLabelsBySelector("", map[string]string{"canary": "true"}, PodFilter{}); err != ErrInvalidSelector {
+		t.Fatalf("expected ErrInvalidSelector, got %v", err)
+	}
+	if _, err := store.UpdateLabelsBySelector("app=frontend", map[string]string{"bad key": "x"}, PodFilter{}); err != ErrInvalidLabels {
+		t.Fatalf("expected ErrInvalidLabels, got %v", err)
+	}
+	if _, err := store.UpdateLabelsBySelector("app=frontend", nil, PodFilter{}); err != ErrInvalidLabels {
+		t.Fatalf("expected ErrInvalidLabels for empty labels, got %v", err)
+	}
+}
+
+func TestNamesWithPrefix(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	names := store.NamesWithPrefix("FRONT", nil, 10)
+	if len(names) == 0 {
+		t.Fatalf("expected frontend pods")
+	}
+	for i, name := range names {
+		if !strings.HasPrefix(name, "frontend-") {
+			t.Fatalf("unexpected match %q", name)
+		}
+		if i > 0 && names[i-1] >= name {
+			t.Fatalf("expected sorted distinct names, got %v", names)
+		}
+	}
+	if got := store.NamesWithPrefix("front", nil, 1); len(got) != 1 {
+		t.Fatalf("expected limit to cap results, got %v", got)
+	}
+	if got := store.NamesWithPrefix("front", []string{"kube-system"}, 10); len(got) != 0 {
+		t.Fatalf("expected namespace restriction to hide frontend pods, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package pod
+
+import "time"
+
+// watchBuffer is the number of events buffered per watcher. A watcher that
+// falls this far behind is closed so its client re-lists instead of silently
+// missing changes.
+const watchBuffer = 64
+
+// EventType classifies a watch event, mirroring Kubernetes watch semantics.
+type EventType string
+
+// Watch event types.
+const (
+	Added    EventType = "ADDED"
+	Modified EventType = "MODIFIED"
+	Deleted  EventType = "DELETED"
+)
+
+// WatchEvent reports a change to a pod. Object ages are only populated for
+// the initial ADDED events.
+type WatchEvent struct {
+	Type   EventType `json:"type"`
+	Object Summary   `json:"object"`
+}
+
+type watcher struct {
+	filter PodFilter
+	ch     chan WatchEvent
+}
+
+// Watch returns the pods currently matching the filter and a channel of
+// subsequent changes to matching pods. The snapshot and subscription are taken
+// atomically. Call stop to unsubscribe; it closes the channel. The store also
+// closes the channel if the watcher overflows its buffer, after which the
+// caller must start a new Watch to resync.
+func (s *Store) Watch(now time.Time, filter PodFilter) ([]Summary, <-chan WatchEvent, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	initial := make([]Summary, 0, len(s.items))
+	for _, rec := range s.items {
+		if filter.matches(rec.Summary) {
+			initial = append(initial, decorateSummary(rec.Summary, rec.CreatedAt, now))
+		}
+	}
+	sortSummaries(initial)
+
+	w := &watcher{filter: filter, ch: make(chan WatchEvent, watchBuffer)}
+	if s.watchers == nil {
+		s.watchers = make(map[*watcher]struct{})
+	}
+	s.watchers[w] = struct{}{}
+
+	stop := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.watchers[w]; ok {
+			delete(s.watchers, w)
+			close(w.ch)
+		}
+	}
+	return initial, w.ch, stop
+}
+
+// notify fans an event out to matching watchers, dropping and closing any
+// whose buffer is full. Callers must hold the write lock.
+func (s *Store) notify(eventType EventType, rec record) {
+	for w := range s.watchers {
+		if !w.filter.matches(rec.Summary) {
+			continue
+		}
+		select {
+		case w.ch <- WatchEvent{Type: eventType, Object: rec.Summary}:
+		default:
+			delete(s.watchers, w)
+			close(w.ch)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package scope implements the namespace allow-lists shared by the stores
+// and the server.
+package scope
+
+import "strings"
+
+// Allows reports whether namespace is in allowed, ignoring case. An empty
+// allow-list allows every namespace.
+func Allows(allowed []string, namespace string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, ns := range allowed {
+		if strings.EqualFold(ns, namespace) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package scope
+
+import "testing"
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		allowed   []string
+		namespace string
+		want      bool
+	}{
+		{allowed: nil, namespace: "prod", want: true},
+		{allowed: []string{"prod"}, namespace: "prod", want: true},
+		{allowed: []string{"staging", "PROD"}, namespace: "prod", want: true},
+		{allowed: []string{"prod"}, namespace: "batch", want: false},
+		{allowed: []string{"prod"}, namespace: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Allows(tt.allowed, tt.namespace); got != tt.want {
+			t.Errorf("Allows(%v, %q) = %v, want %v", tt.allowed, tt.namespace, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Alert severities, ordered most urgent first in responses.
+const (
+	severityCritical = "critical"
+	severityWarning  = "warning"
+)
+
+// Thresholds used by the built-in alert rules.
+const (
+	crashLoopRestarts = 5
+	highNodeCPU       = 90.0
+)
+
+type alert struct {
+	ID          string `json:"id"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Object      string `json:"object"`
+	Namespace   string `json:"namespace,omitempty"`
+	Description string `json:"description"`
+
+	// FiringSince is when this episode of the alert was first seen firing.
+	FiringSince string `json:"firingSince"`
+
+	Acknowledged bool   `json:"acknowledged"`
+	AckedBy      string `json:"ackedBy,omitempty"`
+	AckedAt      string `json:"ackedAt,omitempty"`
+
+	// changed is when the store last changed the alerting object, or zero
+	// when the store does not track it.
+	changed time.Time
+}
+
+// alertAck records who acknowledged an alert and which firing episode, by
+// its firing-since time, the ack applies to.
+type alertAck struct {
+	By    string
+	At    time.Time
+	Since time.Time
+}
+
+type ackRequest struct {
+	By string `json:"by"`
+}
+
+// alertRule evaluates one condition against the stores, returning an alert
+// for every object currently matching it.
+type alertRule struct {
+	Name     string
+	Severity string
+	Evaluate func(s *Server, now time.Time) []alert
+}
+
+var alertRules = []alertRule{
+	{Name: "NodeNotReady", Severity: severityCritical, Evaluate: evalNodeNotReady},
+	{Name: "PodCrashLooping", Severity: severityWarning, Evaluate: evalPodCrashLooping},
+	{Name: "DeploymentDown", Severity: severityCritical, Evaluate: evalDeploymentDown},
+	{Name: "NodeHighCPU", Severity: severityWarning, Evaluate: evalNodeHighCPU},
+}
+
+func evalNodeNotReady(s *Server, now time.Time) []alert {
+	var out []alert
+	for _, n := range s.nodes.List(now) {
+		if ready, _, _ := strings.Cut(n.Status, ","); ready != "Ready" {
+			out = append(out, alert{
+				Object:      "node/" + n.Name,
+				Description: fmt.Sprintf("节点 %s 状态为 %s", n.Name, n.Status),
+			})
+		}
+	}
+	return out
+}
+
+func evalPodCrashLooping(s *Server, now time.Time) []alert {
+	var out []alert
+	for _, p := range visibleOnly(s, s.pods.List(now), podNamespace) {
+		if p.Status == "CrashLoopBackOff" || p.Restarts >= crashLoopRestarts {
+			out = append(out, alert{
+				Object:      "pod/" + p.Name,
+				Namespace:   p.Namespace,
+				Description: fmt.Sprintf("Pod %s/%s 反复重启 (%d 次)", p.Namespace, p.Name, p.Restarts),
+			})
+		}
+	}
+	return out
+}
+
+func evalDeploymentDown(s *Server, now time.Time) []alert {
+	var out []alert
+	for _, d := range s.deployments.List(now) {
+		if d.Status != "Down" {
+			continue
+		}
+		a := alert{
+			Object:      "deployment/" + d.Name,
+			Namespace:   d.Namespace,
+			Description: fmt.Sprintf("Deployment %s/%s 无可用副本 (0/%d)", d.Namespace, d.Name, d.DesiredReplicas),
+		}
+		if detail, err := s.deployments.GetNamespaced(d.Namespace, d.Name, now); err == nil {
+			a.changed, _ = time.Parse(time.RFC3339, detail.LastUpdated)
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func evalNodeHighCPU(s *Server, now time.Time) []alert {
+	var out []alert
+	for _, n := range s.nodes.List(now) {
+		if n.CPU.Percentage >= highNodeCPU {
+			out = append(out, alert{
+				Object:      "node/" + n.Name,
+				Description: fmt.Sprintf("节点 %s CPU 使用率 %.1f%%", n.Name, n.CPU.Percentage),
+			})
+		}
+	}
+	return out
+}
+
+// evaluateAlerts runs every rule and returns the firing alerts, critical
+// first and then by ID.
+func (s *Server) evaluateAlerts(now time.Time) []alert {
+	s.alertMu.Lock()
+	defer s.alertMu.Unlock()
+	return s.evaluateAlertsLocked(now)
+}
+
+// evaluateAlertsLocked is evaluateAlerts for callers holding alertMu. Each
+// alert keeps the time it started firing until it clears; an ack only
+// applies to the episode it was made against, so an alert that clears and
+// fires again comes back unacknowledged. Alerts are only evaluated when
+// polled, so an object the store changed after the recorded episode began,
+// by the injected clock, also starts a new episode: it may have cleared and
+// fired again between polls.
+func (s *Server) evaluateAlertsLocked(now time.Time) []alert {
+	out := make([]alert, 0)
+	firing := make(map[string]bool)
+	for _, rule := range alertRules {
+		for _, a := range rule.Evaluate(s, now) {
+			a.Rule = rule.Name
+			a.Severity = rule.Severity
+			a.ID = alertID(rule.Name, a.Namespace, a.Object)
+			since, ok := s.alertFiring[a.ID]
+			if !ok || a.changed.After(since) {
+				since = now
+				s.alertFiring[a.ID] = since
+			}
+			a.FiringSince = since.UTC().Format(time.RFC3339)
+			if ack, ok := s.alertAcks[a.ID]; ok && ack.Since.Equal(since) {
+				a.Acknowledged = true
+				a.AckedBy = ack.By
+				a.AckedAt = ack.At.UTC().Format(time.RFC3339)
+			}
+			firing[a.ID] = true
+			out = append(out, a)
+		}
+	}
+	for id := range s.alertFiring {
+		if !firing[id] {
+			delete(s.alertFiring, id)
+			delete(s.alertAcks, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Severity != out[j].Severity {
+			return out[i].Severity == severityCritical
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// ackAlert acknowledges the current episode of the firing alert id,
+// evaluating and recording the ack under one lock. It reports false when no
+// alert with that ID is firing.
+func (s *Server) ackAlert(id, by string, now time.Time) (alert, bool) {
+	s.alertMu.Lock()
+	defer s.alertMu.Unlock()
+
+	for _, a := range s.evaluateAlertsLocked(now) {
+		if a.ID != id {
+			continue
+		}
+		ack := alertAck{By: by, At: now, Since: s.alertFiring[id]}
+		s.alertAcks[id] = ack
+		a.Acknowledged = true
+		a.AckedBy = ack.By
+		a.AckedAt = ack.At.UTC().Format(time.RFC3339)
+		return a, true
+	}
+	return alert{}, false
+}
+
+// alertID builds a stable, URL-safe identifier such as
+// "deploymentdown.batch.deployment.batch-jobs".
+func alertID(rule, namespace, object string) string {
+	parts := []string{strings.ToLower(rule)}
+	if namespace != "" {
+		parts = append(parts, namespace)
+	}
+	parts = append(parts, strings.ReplaceAll(object, "/", "."))
+	return strings.Join(parts, ".")
+}
+
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.evaluateAlerts(s.now()), http.StatusOK)
+}
+
+func (s *Server) handleAlertByID(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/alerts/"), "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] != "ack" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The body is optional; acks without one are attributed to "anonymous".
+	var req ackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.By) == "" {
+		req.By = "anonymous"
+	}
+
+	a, ok := s.ackAlert(segments[0], req.By, s.now())
+	if !ok {
+		writeJSON(w, errorResponse{Error: "告警不存在或已恢复"}, http.StatusNotFound)
+		return
+	}
+	s.recordAudit("ack", "alert", a.Namespace, a.ID)
+	writeJSON(w, a, http.StatusOK)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"k8s_dashboard/internal/audit"
+)
+
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.audit.List(), http.StatusOK)
+	case http.MethodDelete:
+		s.audit.Clear()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// defaultResourceAuditLimit is the number of entries /api/audit/resource
+// returns when no limit is given.
+const defaultResourceAuditLimit = 20
+
+func (s *Server) handleResourceAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	kind, name := strings.TrimSpace(query.Get("kind")), strings.TrimSpace(query.Get("name"))
+	if kind == "" || name == "" {
+		writeJSON(w, errorResponse{Error: "缺少 kind 或 name 参数"}, http.StatusBadRequest)
+		return
+	}
+	limit := defaultResourceAuditLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeJSON(w, errorResponse{Error: "limit 参数无效"}, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	writeJSON(w, s.audit.ListResource(kind, query.Get("namespace"), name, limit), http.StatusOK)
+}
+
+func (s *Server) recordAudit(action, kind, namespace, name string) {
+	s.recordAuditChange(action, kind, namespace, name, nil, nil)
+}
+
+// recordAuditChange is recordAudit with the changed fields before and after
+// the call.
+func (s *Server) recordAuditChange(action, kind, namespace, name string, before, after map[string]any) {
+	s.audit.Record(audit.Entry{
+		Action:    action,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Before:    before,
+		After:     after,
+	}, s.now())
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s_dashboard/internal/cluster"
+)
+
+type statusBreakdown struct {
+	Pods        map[string]int `json:"pods"`
+	Deployments map[string]int `json:"deployments"`
+	Nodes       map[string]int `json:"nodes"`
+	Services    map[string]int `json:"services"`
+}
+
+func (s *Server) handleClusterCapacity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.nodes.CapacitySummary(), http.StatusOK)
+}
+
+func (s *Server) handleClusterStatusBreakdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := s.now()
+	out := statusBreakdown{
+		Pods:        make(map[string]int),
+		Deployments: make(map[string]int),
+		Nodes:       make(map[string]int),
+		Services:    make(map[string]int),
+	}
+	for _, p := range visibleOnly(s, s.pods.List(now), podNamespace) {
+		out.Pods[p.Status]++
+	}
+	for _, d := range s.deployments.List(now) {
+		out.Deployments[d.Status]++
+	}
+	for _, n := range s.nodes.List(now) {
+		// Drop the ",SchedulingDisabled" suffix so cordoned nodes count by readiness.
+		ready, _, _ := strings.Cut(n.Status, ",")
+		out.Nodes[ready]++
+	}
+	for _, svc := range visibleOnly(s, s.services.List(now), serviceNamespace) {
+		out.Services[svc.Status]++
+	}
+
+	writeJSON(w, out, http.StatusOK)
+}
+
+func (s *Server) handleClusterHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.markDegraded(w, s.buildOverview())
+	writeJSON(w, s.healthScore(s.now()), http.StatusOK)
+}
+
+// healthScore scores the live cluster for /api/cluster/health with the
+// configured weights.
+func (s *Server) healthScore(now time.Time) cluster.HealthScore {
+	var in cluster.HealthInputs
+	for _, n := range s.nodes.List(now) {
+		if ready, _, _ := strings.Cut(n.Status, ","); ready != "Ready" {
+			in.NotReadyNodes++
+		}
+	}
+	for _, p := range visibleOnly(s, s.pods.List(now), podNamespace) {
+		switch p.Status {
+		case "Failed":
+			in.FailedPods++
+		case "Pending":
+			in.PendingPods++
+		}
+	}
+	for _, d := range s.deployments.List(now) {
+		if d.Status == "Down" || d.Status == "Updating" {
+			in.UnhealthyDeployments++
+		}
+	}
+
+	return cluster.Score(in, s.health)
+}
+
+// defaultUsageWindow is the /api/cluster/metrics window when none is given;
+// the step defaults to a twelfth of the window.
+const defaultUsageWindow = time.Hour
+
+func (s *Server) handleClusterMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	window := defaultUsageWindow
+	if raw := query.Get("window"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			writeJSON(w, errorResponse{Error: "window 参数无效"}, http.StatusBadRequest)
+			return
+		}
+		window = d
+	}
+	step := window / 12
+	if raw := query.Get("step"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			writeJSON(w, errorResponse{Error: "step 参数无效"}, http.StatusBadRequest)
+			return
+		}
+		step = d
+	}
+
+	now := s.now()
+	history, err := cluster.UsageSeries(now, cluster.NodeUsage(now, s.nodes.List(now)), window, step)
+	if err != nil {
+		writeJSON(w, errorResponse{Error: fmt.Sprintf("window 与 step 必须为正数，step 不得大于 window，且采样点不超过 %d 个", cluster.MaxUsageSamples)}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, history, http.StatusOK)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
@@ -12,45 +13,198 @@ import (
 const maxImportSize = 5 << 20 // 5 MiB
 
 func (s *Server) handleClusterImport(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	switch r.Method {
+	case http.MethodPost:
+		s.idempotent(w, r, s.handleClusterImportCreate)
+	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleClusterImportCreate(w http.ResponseWriter, r *http.Request) {
+	uploads, ok := s.parseUploads(w, r)
+	if !ok {
 		return
 	}
 
+	summary := kubeconfig.Merge(uploads...)
+	s.kubeconfigs.Add(summary)
+	writeJSON(w, importResponse{Summary: summary, Warnings: kubeconfig.ClusterConflicts(uploads...)}, http.StatusCreated)
+}
+
+// importResponse is the stored summary plus any conflicts found while
+// merging several uploaded files.
+type importResponse struct {
+	kubeconfig.Summary
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// parseUpload reads the kubeconfigs sent in the "file" form fields and merges
+// them with kubeconfig.Merge. It answers 400 and returns false when the
+// upload is missing or invalid.
+func (s *Server) parseUpload(w http.ResponseWriter, r *http.Request) (kubeconfig.Summary, bool) {
+	uploads, ok := s.parseUploads(w, r)
+	if !ok {
+		return kubeconfig.Summary{}, false
+	}
+	return kubeconfig.Merge(uploads...), true
+}
+
+// parseUploads parses every "file" form part in upload order, naming each
+// after its file when it has no current context.
+func (s *Server) parseUploads(w http.ResponseWriter, r *http.Request) ([]kubeconfig.Summary, bool) {
 	if err := r.ParseMultipartForm(maxImportSize); err != nil {
 		http.Error(w, "解析上传文件失败", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
-	file, header, err := r.FormFile("file")
-	if err != nil {
+	headers := r.MultipartForm.File["file"]
+	if len(headers) == 0 {
 		http.Error(w, "未找到 kubeconfig 文件", http.StatusBadRequest)
+		return nil, false
+	}
+
+	uploads := make([]kubeconfig.Summary, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "未找到 kubeconfig 文件", http.StatusBadRequest)
+			return nil, false
+		}
+		summary, err := kubeconfig.Parse(limitReader(file, maxImportSize), s.now())
+		file.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+
+		if summary.Name == "" {
+			summary.Name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+		}
+		summary.Name = strings.TrimSpace(summary.Name)
+		uploads = append(uploads, summary)
+	}
+	return uploads, true
+}
+
+func (s *Server) handleClusterImports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	items := s.kubeconfigs.List()
+	writeJSON(w, items, http.StatusOK)
+}
+
+func (s *Server) handleClusterImportByName(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/cluster/imports/")
+	segments := strings.Split(path, "/")
+	name := strings.TrimSpace(segments[0])
+	if name != "" && len(segments) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleClusterImportDetail(w, name)
+		case http.MethodPatch:
+			s.handleClusterImportMerge(w, r, name)
+		case http.MethodDelete:
+			s.handleClusterImportDelete(w, name)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	if name != "" && len(segments) == 2 && segments[1] == "activate" {
+		s.handleClusterImportActivate(w, r, name)
+		return
+	}
+	if name == "" || len(segments) != 2 || segments[1] != "download" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	defer file.Close()
 
-	summary, err := kubeconfig.Parse(limitReader(file, maxImportSize), s.now())
+	summary, ok := s.kubeconfigs.Get(name)
+	if !ok {
+		writeJSON(w, errorResponse{Error: "导入记录不存在"}, http.StatusNotFound)
+		return
+	}
+
+	raw, err := summary.ToYAML()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, "failed to render kubeconfig", http.StatusInternalServerError)
 		return
 	}
 
-	if summary.Name == "" {
-		summary.Name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".yaml"))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(raw)
+}
+
+func (s *Server) handleClusterImportDetail(w http.ResponseWriter, name string) {
+	summary, ok := s.kubeconfigs.Get(name)
+	if !ok {
+		writeJSON(w, errorResponse{Error: "导入记录不存在"}, http.StatusNotFound)
+		return
 	}
 
-	s.kubeconfigs.Add(summary)
-	writeJSON(w, summary, http.StatusCreated)
+	writeJSON(w, summary, http.StatusOK)
 }
 
-func (s *Server) handleClusterImports(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+func (s *Server) handleClusterImportMerge(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.kubeconfigs.Get(name); !ok {
+		writeJSON(w, errorResponse{Error: "导入记录不存在"}, http.StatusNotFound)
+		return
+	}
+
+	other, ok := s.parseUpload(w, r)
+	if !ok {
+		return
+	}
+
+	merged, ok := s.kubeconfigs.Merge(name, other)
+	if !ok {
+		writeJSON(w, errorResponse{Error: "导入记录不存在"}, http.StatusNotFound)
+		return
+	}
+
+	s.recordAudit("merge", "kubeconfig", "", name)
+	writeJSON(w, merged, http.StatusOK)
+}
+
+func (s *Server) handleClusterImportDelete(w http.ResponseWriter, name string) {
+	if !s.kubeconfigs.Delete(name) {
+		writeJSON(w, errorResponse{Error: "导入记录不存在"}, http.StatusNotFound)
+		return
+	}
+
+	s.recordAudit("delete", "kubeconfig", "", name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleClusterImportActivate(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	items := s.kubeconfigs.List()
-	writeJSON(w, items, http.StatusOK)
+	if err := s.kubeconfigs.SetActive(name); err != nil {
+		if err == kubeconfig.ErrNotFound {
+			writeJSON(w, errorResponse{Error: "导入记录不存在"}, http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to activate kubeconfig", http.StatusInternalServerError)
+		return
+	}
+
+	summary, _ := s.kubeconfigs.Get(name)
+	s.recordAudit("activate", "kubeconfig", "", name)
+	writeJSON(w, summary, http.StatusOK)
 }
 
 func limitReader(r io.Reader, n int64) io.Reader {
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders  = "Content-Type, Idempotency-Key, X-Request-ID"
+	corsExposeHeaders = "X-Total-Count, X-Limit, X-Offset, X-Request-ID, X-Cluster-Degraded"
+	corsMaxAge        = "600"
+)
+
+// corsPolicy lists the origins allowed to call the API from a browser.
+type corsPolicy struct {
+	anyOrigin bool
+	origins   map[string]bool
+}
+
+// WithCORSOrigins lets browsers on the given origins call the API. An origin
+// of "*" allows any origin. Without this option no CORS headers are sent, so
+// only same-origin pages can read responses.
+func WithCORSOrigins(origins ...string) Option {
+	return func(s *Server) {
+		policy := &corsPolicy{origins: make(map[string]bool)}
+		for _, origin := range origins {
+			origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+			switch origin {
+			case "":
+			case "*":
+				policy.anyOrigin = true
+			default:
+				policy.origins[origin] = true
+			}
+		}
+		if policy.anyOrigin || len(policy.origins) > 0 {
+			s.cors = policy
+		}
+	}
+}
+
+func (p *corsPolicy) allows(origin string) bool {
+	return origin != "" && (p.anyOrigin || p.origins[origin])
+}
+
+// applyCORS sets the CORS response headers for allowed origins and answers
+// preflight requests itself. It reports true when the request was handled.
+func (s *Server) applyCORS(w http.ResponseWriter, r *http.Request) bool {
+	if s.cors == nil {
+		return false
+	}
+	w.Header().Add("Vary", "Origin")
+
+	origin := r.Header.Get("Origin")
+	if !s.cors.allows(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+		w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+	return false
+}
@@ -2,79 +2,590 @@ package server
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"k8s_dashboard/internal/deploy"
+	"k8s_dashboard/internal/logs"
+	"k8s_dashboard/internal/pod"
 )
 
 type scaleRequest struct {
 	Replicas int `json:"replicas"`
 }
 
+type setImageRequest struct {
+	Container string `json:"container"`
+	Image     string `json:"image"`
+}
+
+type rollbackRequest struct {
+	ToRevision int `json:"toRevision"`
+}
+
+type patchDeploymentRequest struct {
+	RollingUpdate *deploy.RollingUpdate `json:"rollingUpdate"`
+}
+
 func (s *Server) handleDeployments(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		s.idempotent(w, r, s.handleDeploymentCreate)
+		return
+	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	payload := s.deployments.List(s.now())
+	payload := timeStore(s, "deployments.List", func() []deploy.Summary { return s.deployments.List(s.now()) })
+	switch health := r.URL.Query().Get("health"); health {
+	case "":
+	case "healthy", "unhealthy":
+		filtered := make([]deploy.Summary, 0, len(payload))
+		for _, item := range payload {
+			if (item.Status == "Healthy") == (health == "healthy") {
+				filtered = append(filtered, item)
+			}
+		}
+		payload = filtered
+	default:
+		writeJSON(w, errorResponse{Error: "health 参数无效"}, http.StatusBadRequest)
+		return
+	}
 	writeJSON(w, payload, http.StatusOK)
 }
 
+func (s *Server) handleDeploymentCreate(w http.ResponseWriter, r *http.Request) {
+	var spec deploy.CreateSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := s.deployments.Create(spec, s.now())
+	if err != nil {
+		switch err {
+		case deploy.ErrInvalidReplicas:
+			writeJSON(w, errorResponse{Error: "副本数无效"}, http.StatusBadRequest)
+		case deploy.ErrInvalidStrategy:
+			writeJSON(w, errorResponse{Error: "更新策略无效"}, http.StatusBadRequest)
+		case deploy.ErrInvalidSpec:
+			writeJSON(w, errorResponse{Error: "Deployment 名称和容器不能为空"}, http.StatusBadRequest)
+		case deploy.ErrExists:
+			writeJSON(w, errorResponse{Error: "Deployment 已存在"}, http.StatusConflict)
+		default:
+			http.Error(w, "failed to create deployment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.recordAudit("create", "deployment", detail.Namespace, detail.Name)
+	writeJSON(w, detail, http.StatusCreated)
+}
+
+func (s *Server) handleDeploymentCompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	details := make([]deploy.Detail, 0, 2)
+	for _, param := range []string{"a", "b"} {
+		namespace, name, ok := strings.Cut(query.Get(param), "/")
+		if !ok || namespace == "" || name == "" {
+			writeJSON(w, errorResponse{Error: param + " 参数格式应为 namespace/name"}, http.StatusBadRequest)
+			return
+		}
+		detail, err := s.deployments.GetNamespaced(namespace, name, s.now())
+		if err != nil {
+			if err == deploy.ErrNotFound {
+				writeJSON(w, errorResponse{Error: "Deployment 不存在: " + namespace + "/" + name}, http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to load deployment detail", http.StatusInternalServerError)
+			return
+		}
+		details = append(details, detail)
+	}
+
+	writeJSON(w, deploy.Compare(details[0], details[1]), http.StatusOK)
+}
+
+// deploymentSubresources lists the path segments served under
+// /api/deployments/{name}/. Any other second segment is treated as
+// /api/deployments/{namespace}/{name}.
+var deploymentSubresources = map[string]bool{
+	"hpa":            true,
+	"rollback":       true,
+	"restart":        true,
+	"image":          true,
+	"recommendation": true,
+	"events":         true,
+	"logs":           true,
+	"pause":          true,
+	"resume":         true,
+	"status":         true,
+	"revisions":      true,
+	"scale":          true,
+}
+
 func (s *Server) handleDeploymentByName(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/deployments/")
 	if path == "" {
 		http.NotFound(w, r)
 		return
 	}
+	if path == "compare" {
+		s.handleDeploymentCompare(w, r)
+		return
+	}
 
 	segments := strings.Split(path, "/")
 	name := segments[0]
 
+	if len(segments) == 2 && !deploymentSubresources[segments[1]] && segments[1] != "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleDeploymentDetail(w, r, name, segments[1])
+		return
+	}
+	if len(segments) == 2 && segments[1] == "hpa" {
+		s.handleDeploymentAutoscaler(w, r, name)
+		return
+	}
+	if len(segments) == 2 && segments[1] == "rollback" {
+		s.handleDeploymentRollback(w, r, name)
+		return
+	}
+	if len(segments) == 2 && segments[1] == "restart" {
+		s.handleDeploymentRestart(w, r, name)
+		return
+	}
+	if len(segments) == 2 && segments[1] == "image" {
+		s.handleDeploymentImage(w, r, name)
+		return
+	}
+	if len(segments) == 2 && segments[1] == "recommendation" {
+		s.handleDeploymentRecommendation(w, r, name)
+		return
+	}
+	if len(segments) == 2 && segments[1] == "events" {
+		s.handleDeploymentEvents(w, r, name)
+		return
+	}
+	if len(segments) == 2 && segments[1] == "logs" {
+		s.handleDeploymentLogs(w, r, name)
+		return
+	}
+	if len(segments) == 2 && (segments[1] == "pause" || segments[1] == "resume") {
+		s.handleDeploymentPause(w, r, name, segments[1] == "pause")
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
+		if len(segments) == 2 && segments[1] == "status" {
+			s.handleDeploymentStatus(w, name)
+			return
+		}
+		if len(segments) == 2 && segments[1] == "revisions" {
+			s.handleDeploymentRevisions(w, name)
+			return
+		}
 		if len(segments) != 1 {
 			http.NotFound(w, r)
 			return
 		}
-		detail, err := s.deployments.Get(name, s.now())
-		if err != nil {
-			if err == deploy.ErrNotFound {
-				writeJSON(w, errorResponse{Error: "Deployment 不存在"}, http.StatusNotFound)
-				return
-			}
-			http.Error(w, "failed to load deployment detail", http.StatusInternalServerError)
+		s.handleDeploymentDetail(w, r, "", name)
+	case http.MethodPatch:
+		if len(segments) != 1 {
+			http.NotFound(w, r)
 			return
 		}
-		writeJSON(w, detail, http.StatusOK)
-	case http.MethodPut:
+		s.handleDeploymentPatch(w, r, name)
+	case http.MethodPut, http.MethodPost:
 		if len(segments) != 2 || segments[1] != "scale" {
 			http.NotFound(w, r)
 			return
 		}
 
+		dryRun := r.URL.Query().Get("dryRun") == "true"
+		if r.Method == http.MethodPost && !dryRun {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
 		var req scaleRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "invalid JSON payload", http.StatusBadRequest)
 			return
 		}
 
-		detail, err := s.deployments.Scale(name, req.Replicas, s.now())
+		if dryRun {
+			preview, err := s.deployments.PreviewScale(name, req.Replicas, s.now())
+			if err != nil {
+				writeDeploymentError(w, err, "failed to preview scale")
+				return
+			}
+			writeJSON(w, preview, http.StatusOK)
+			return
+		}
+
+		var previous int
+		detail, err := timeStoreErr(s, "deployments.Scale", func() (deploy.Detail, error) {
+			detail, replaced, err := s.deployments.Scale(name, req.Replicas, s.now())
+			previous = replaced
+			return detail, err
+		})
 		if err != nil {
 			switch err {
 			case deploy.ErrInvalidReplicas:
 				writeJSON(w, errorResponse{Error: "副本数无效"}, http.StatusBadRequest)
-			case deploy.ErrNotFound:
-				writeJSON(w, errorResponse{Error: "Deployment 不存在"}, http.StatusNotFound)
 			default:
-				http.Error(w, "failed to scale deployment", http.StatusInternalServerError)
+				writeDeploymentError(w, err, "failed to scale deployment")
 			}
 			return
 		}
 
+		s.recordAuditChange("scale", "deployment", detail.Namespace, detail.Name,
+			map[string]any{"replicas": previous},
+			map[string]any{"replicas": detail.DesiredReplicas})
 		writeJSON(w, detail, http.StatusOK)
 	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
+
+// handleDeploymentDetail serves a deployment addressed by name, or by
+// namespace and name when a namespace is given.
+func (s *Server) handleDeploymentDetail(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	loc, err := displayZone(r)
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "时区无效"}, http.StatusBadRequest)
+		return
+	}
+	detail, err := timeStoreErr(s, "deployments.Get", func() (deploy.Detail, error) {
+		if namespace != "" {
+			return s.deployments.GetNamespaced(namespace, name, s.now())
+		}
+		return s.deployments.Get(name, s.now())
+	})
+	if err != nil {
+		writeDeploymentError(w, err, "failed to load deployment detail")
+		return
+	}
+	if loc != nil {
+		detail.LastUpdatedLocal = localizeTime(detail.LastUpdated, loc)
+	}
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentStatus(w http.ResponseWriter, name string) {
+	status, err := s.deployments.GetStatus(name, s.now())
+	if err != nil {
+		writeDeploymentError(w, err, "failed to load deployment status")
+		return
+	}
+	writeJSON(w, status, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentRevisions(w http.ResponseWriter, name string) {
+	revisions, err := s.deployments.Revisions(name)
+	if err != nil {
+		writeDeploymentError(w, err, "failed to load deployment revisions")
+		return
+	}
+	writeJSON(w, revisions, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentPatch(w http.ResponseWriter, r *http.Request, name string) {
+	var req patchDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	if req.RollingUpdate == nil {
+		writeJSON(w, errorResponse{Error: "缺少 rollingUpdate 参数"}, http.StatusBadRequest)
+		return
+	}
+
+	detail, previous, err := s.deployments.UpdateRollingUpdate(name, *req.RollingUpdate, s.now())
+	if err != nil {
+		switch err {
+		case deploy.ErrInvalidStrategy:
+			writeJSON(w, errorResponse{Error: "滚动更新参数无效"}, http.StatusBadRequest)
+		default:
+			writeDeploymentError(w, err, "failed to patch deployment")
+		}
+		return
+	}
+
+	s.recordAuditChange("patch", "deployment", detail.Namespace, detail.Name,
+		map[string]any{"maxSurge": previous.MaxSurge, "maxUnavailable": previous.MaxUnavailable},
+		map[string]any{"maxSurge": detail.RollingUpdate.MaxSurge, "maxUnavailable": detail.RollingUpdate.MaxUnavailable})
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentRollback(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The body is optional; without one the previous revision is restored.
+	var req rollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	detail, previous, err := s.deployments.Rollback(name, req.ToRevision, s.now())
+	if err != nil {
+		switch err {
+		case deploy.ErrUnknownRevision:
+			writeJSON(w, errorResponse{Error: "目标版本不存在"}, http.StatusBadRequest)
+		default:
+			writeDeploymentError(w, err, "failed to roll back deployment")
+		}
+		return
+	}
+
+	s.recordAuditChange("rollback", "deployment", detail.Namespace, detail.Name,
+		map[string]any{"revision": previous},
+		map[string]any{"revision": detail.Revision})
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentRestart(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	detail, err := s.deployments.Restart(name, s.now())
+	if err != nil {
+		writeDeploymentError(w, err, "failed to restart deployment")
+		return
+	}
+
+	s.recordAudit("restart", "deployment", detail.Namespace, detail.Name)
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentImage(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req setImageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	detail, previous, err := s.deployments.SetImage(name, req.Container, req.Image, s.now())
+	if err != nil {
+		switch err {
+		case deploy.ErrUnknownContainer:
+			writeJSON(w, errorResponse{Error: "容器不存在"}, http.StatusBadRequest)
+		case deploy.ErrInvalidSpec:
+			writeJSON(w, errorResponse{Error: "镜像不能为空"}, http.StatusBadRequest)
+		default:
+			writeDeploymentError(w, err, "failed to update deployment image")
+		}
+		return
+	}
+
+	s.recordAuditChange("set-image", "deployment", detail.Namespace, detail.Name,
+		map[string]any{"container": req.Container, "image": previous},
+		map[string]any{"container": req.Container, "image": req.Image})
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentPause(w http.ResponseWriter, r *http.Request, name string, paused bool) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	detail, previous, err := s.deployments.SetPaused(name, paused, s.now())
+	if err != nil {
+		writeDeploymentError(w, err, "failed to update deployment")
+		return
+	}
+
+	action := "resume"
+	if paused {
+		action = "pause"
+	}
+	s.recordAuditChange(action, "deployment", detail.Namespace, detail.Name,
+		map[string]any{"paused": previous},
+		map[string]any{"paused": detail.Paused})
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentAutoscaler(w http.ResponseWriter, r *http.Request, name string) {
+	var (
+		hpa      deploy.Autoscaler
+		previous *deploy.AutoscalerSpec
+		err      error
+		status   = http.StatusOK
+	)
+	switch r.Method {
+	case http.MethodGet:
+		hpa, err = s.deployments.GetAutoscaler(name, s.now())
+	case http.MethodPost:
+		var spec deploy.AutoscalerSpec
+		if decodeErr := json.NewDecoder(r.Body).Decode(&spec); decodeErr != nil {
+			http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+			return
+		}
+		hpa, previous, err = s.deployments.SetAutoscaler(name, spec, s.now())
+		status = http.StatusCreated
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		switch err {
+		case deploy.ErrInvalidAutoscaler:
+			writeJSON(w, errorResponse{Error: "自动扩缩容参数无效"}, http.StatusBadRequest)
+		case deploy.ErrNoAutoscaler:
+			writeJSON(w, errorResponse{Error: "未配置自动扩缩容"}, http.StatusNotFound)
+		default:
+			writeDeploymentError(w, err, "failed to handle autoscaler")
+		}
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		var before map[string]any
+		if previous != nil {
+			before = autoscalerAudit(previous.Min, previous.Max, previous.TargetCPU)
+		}
+		s.recordAuditChange("autoscale", "deployment", hpa.Namespace, hpa.Name,
+			before, autoscalerAudit(hpa.MinReplicas, hpa.MaxReplicas, hpa.TargetCPU))
+	}
+	writeJSON(w, hpa, status)
+}
+
+// autoscalerAudit is the audit view of an autoscaler's bounds and target.
+func autoscalerAudit(minReplicas, maxReplicas, targetCPU int) map[string]any {
+	return map[string]any{"min": minReplicas, "max": maxReplicas, "targetCPU": targetCPU}
+}
+
+// handleDeploymentEvents returns events about the deployment itself or the
+// pods it owns, newest first.
+func (s *Server) handleDeploymentEvents(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := s.now()
+	detail, err := s.deployments.Get(name, now)
+	if err != nil {
+		writeDeploymentError(w, err, "failed to load deployment")
+		return
+	}
+
+	owned := make(map[string]bool)
+	for _, p := range s.ownedPods(detail) {
+		owned[p] = true
+	}
+
+	events := make([]logs.Event, 0)
+	for _, ev := range s.logs.ListEventsFiltered(now, logs.EventFilter{Namespace: detail.Namespace}) {
+		if (ev.Kind == "Deployment" && ev.Name == detail.Name) || (ev.Kind == "Pod" && owned[ev.Name]) {
+			events = append(events, ev)
+		}
+	}
+
+	writeJSON(w, visibleOnly(s, events, eventNamespace), http.StatusOK)
+}
+
+// ownedPods returns the names of the pods whose owner reference points at
+// the deployment.
+func (s *Server) ownedPods(d deploy.Detail) []string {
+	var names []string
+	for _, p := range s.pods.ListFiltered(s.now(), pod.PodFilter{Namespace: d.Namespace}) {
+		if p.Owner != nil && p.Owner.Kind == "Deployment" && p.Owner.Name == d.Name {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// handleDeploymentLogs merges the log lines of every pod the deployment owns,
+// newest first. Each entry names its source pod.
+func (s *Server) handleDeploymentLogs(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			writeJSON(w, errorResponse{Error: "limit 参数无效"}, http.StatusBadRequest)
+			return
+		}
+		limit = v
+	}
+
+	detail, err := s.deployments.Get(name, s.now())
+	if err != nil {
+		writeDeploymentError(w, err, "failed to load deployment")
+		return
+	}
+
+	pods := s.ownedPods(detail)
+	if len(pods) == 0 {
+		writeJSON(w, []logs.LogEntry{}, http.StatusOK)
+		return
+	}
+	entries := s.logs.ListLogs(s.now(), logs.LogFilter{
+		Namespace:  detail.Namespace,
+		Pods:       pods,
+		Limit:      limit,
+		Namespaces: s.visibleNamespaces,
+	})
+	writeJSON(w, entries, http.StatusOK)
+}
+
+func (s *Server) handleDeploymentRecommendation(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rec, err := s.deployments.Recommend(name)
+	if err != nil {
+		writeDeploymentError(w, err, "failed to compute recommendation")
+		return
+	}
+
+	writeJSON(w, rec, http.StatusOK)
+}
+
+// writeDeploymentError maps deployment lookup errors to responses. A name
+// shared by several namespaces is a conflict the caller resolves with
+// /api/deployments/{namespace}/{name}.
+func writeDeploymentError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case deploy.ErrNotFound:
+		writeJSON(w, errorResponse{Error: "Deployment 不存在"}, http.StatusNotFound)
+	case deploy.ErrAmbiguous:
+		writeJSON(w, errorResponse{Error: "Deployment 名称在多个命名空间中重复，请使用 /api/deployments/{namespace}/{name}"}, http.StatusConflict)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
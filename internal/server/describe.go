@@ -0,0 +1,246 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"k8s_dashboard/internal/deploy"
+	"k8s_dashboard/internal/node"
+	"k8s_dashboard/internal/pod"
+	"k8s_dashboard/internal/service"
+)
+
+type describer func(s *Server, name string) (string, error)
+
+var describers = map[string]describer{
+	"pod":        describePod,
+	"node":       describeNode,
+	"service":    describeService,
+	"deployment": describeDeployment,
+}
+
+func (s *Server) handleDescribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	describe, ok := describers[strings.ToLower(query.Get("kind"))]
+	if !ok {
+		writeJSON(w, errorResponse{Error: "不支持的资源类型"}, http.StatusBadRequest)
+		return
+	}
+	name := query.Get("name")
+	if name == "" {
+		writeJSON(w, errorResponse{Error: "缺少 name 参数"}, http.StatusBadRequest)
+		return
+	}
+
+	text, err := describe(s, name)
+	if err != nil {
+		switch err {
+		case pod.ErrNotFound, node.ErrNotFound, service.ErrNotFound, deploy.ErrNotFound:
+			writeJSON(w, errorResponse{Error: "资源不存在"}, http.StatusNotFound)
+		case pod.ErrAmbiguous, deploy.ErrAmbiguous:
+			writeJSON(w, errorResponse{Error: "资源名称在多个命名空间中重复"}, http.StatusConflict)
+		default:
+			http.Error(w, "failed to describe resource", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(text))
+}
+
+// description renders kubectl-describe style "Key:  value" lines, aligning
+// values within each block.
+type description struct {
+	buf bytes.Buffer
+	tw  *tabwriter.Writer
+}
+
+func newDescription() *description {
+	d := &description{}
+	d.tw = tabwriter.NewWriter(&d.buf, 0, 8, 2, ' ', 0)
+	return d
+}
+
+func (d *description) field(indent int, key string, value any) {
+	fmt.Fprintf(d.tw, "%s%s:\t%v\n", strings.Repeat("  ", indent), key, value)
+}
+
+func (d *description) section(key string) {
+	fmt.Fprintf(d.tw, "%s:\n", key)
+}
+
+func (d *description) line(indent int, cols ...any) {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintf(d.tw, "%s%s\n", strings.Repeat("  ", indent), strings.Join(parts, "\t"))
+}
+
+func (d *description) String() string {
+	_ = d.tw.Flush()
+	return d.buf.String()
+}
+
+func describeMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>"
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+m[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func describeList(items []string) string {
+	if len(items) == 0 {
+		return "<none>"
+	}
+	return strings.Join(items, ", ")
+}
+
+func describePod(s *Server, name string) (string, error) {
+	detail, err := s.visiblePod(name)
+	if err != nil {
+		return "", err
+	}
+
+	d := newDescription()
+	d.field(0, "Name", detail.Name)
+	d.field(0, "Namespace", detail.Namespace)
+	d.field(0, "Node", detail.Node)
+	d.field(0, "Status", detail.Status)
+	d.field(0, "Ready", detail.ReadyContainers)
+	d.field(0, "Restarts", detail.Restarts)
+	d.field(0, "Age", detail.Age)
+	d.section("Containers")
+	for _, c := range detail.Containers {
+		d.line(1, c.Name+":")
+		d.field(2, "Image", c.Image)
+		d.field(2, "Ready", c.Ready)
+		d.field(2, "Restart Count", c.RestartCount)
+	}
+	d.section("Events")
+	if len(detail.Events) == 0 {
+		d.line(1, "<none>")
+	}
+	for _, ev := range detail.Events {
+		d.line(1, ev.Type, ev.Reason, ev.Timestamp, ev.Message)
+	}
+	return d.String(), nil
+}
+
+func describeNode(s *Server, name string) (string, error) {
+	detail, err := s.nodes.Get(name, s.now())
+	if err != nil {
+		return "", err
+	}
+
+	d := newDescription()
+	d.field(0, "Name", detail.Name)
+	d.field(0, "Roles", describeList(node.CanonicalRoles(detail.Roles)))
+	d.field(0, "Status", detail.Status)
+	d.field(0, "Labels", describeMap(detail.Labels))
+	d.field(0, "Taints", describeList(detail.Taints))
+	d.field(0, "Unschedulable", detail.Unschedulable)
+	d.field(0, "Kubelet Version", detail.KubeletVersion)
+	d.field(0, "OS Image", detail.OSImage)
+	d.field(0, "Kernel Version", detail.KernelVersion)
+	d.field(0, "Container Runtime", detail.ContainerRuntime)
+	d.section("Capacity")
+	d.field(1, "cpu", fmt.Sprintf("%g %s", detail.CPU.Capacity, detail.CPU.Unit))
+	d.field(1, "memory", fmt.Sprintf("%g %s", detail.Memory.Capacity, detail.Memory.Unit))
+	d.field(1, "pods", detail.Pods.Capacity)
+	d.section("Allocatable")
+	d.field(1, "cpu", fmt.Sprintf("%g %s", detail.CPU.Allocatable, detail.CPU.Unit))
+	d.field(1, "memory", fmt.Sprintf("%g %s", detail.Memory.Allocatable, detail.Memory.Unit))
+	d.section("Conditions")
+	for _, c := range detail.Conditions {
+		d.line(1, c.Type, c.Status, c.LastHeartbeat, c.Message)
+	}
+	return d.String(), nil
+}
+
+func describeService(s *Server, name string) (string, error) {
+	detail, err := s.services.Get(name, s.now())
+	if err != nil {
+		return "", err
+	}
+	if !s.namespaceVisible(detail.Namespace) {
+		return "", service.ErrNotFound
+	}
+
+	d := newDescription()
+	d.field(0, "Name", detail.Name)
+	d.field(0, "Namespace", detail.Namespace)
+	d.field(0, "Type", detail.Type)
+	d.field(0, "Selector", describeMap(detail.Selector))
+	d.field(0, "IP", detail.ClusterIP)
+	d.field(0, "External IPs", describeList(detail.ExternalIPs))
+	d.field(0, "Status", detail.Status)
+	for _, p := range detail.Ports {
+		d.field(0, "Port", fmt.Sprintf("%s %d/%s", p.Name, p.Port, p.Protocol))
+		d.field(0, "TargetPort", p.TargetPort)
+		if p.NodePort != nil {
+			d.field(0, "NodePort", *p.NodePort)
+		}
+	}
+	endpoints := make([]string, 0, len(detail.Endpoints))
+	for _, ep := range detail.Endpoints {
+		if !ep.Ready {
+			endpoints = append(endpoints, ep.Address+" (not ready)")
+			continue
+		}
+		endpoints = append(endpoints, ep.Address)
+	}
+	d.field(0, "Endpoints", describeList(endpoints))
+	return d.String(), nil
+}
+
+func describeDeployment(s *Server, name string) (string, error) {
+	detail, err := s.deployments.Get(name, s.now())
+	if err != nil {
+		return "", err
+	}
+
+	d := newDescription()
+	d.field(0, "Name", detail.Name)
+	d.field(0, "Namespace", detail.Namespace)
+	d.field(0, "Labels", describeMap(detail.Labels))
+	d.field(0, "Selector", describeMap(detail.Selector))
+	d.field(0, "Replicas", fmt.Sprintf("%d desired | %d updated | %d ready", detail.DesiredReplicas, detail.UpdatedReplicas, detail.ReadyReplicas))
+	d.field(0, "StrategyType", detail.Strategy)
+	if detail.RollingUpdate != nil {
+		d.field(0, "RollingUpdateStrategy", fmt.Sprintf("%s max unavailable, %s max surge", detail.RollingUpdate.MaxUnavailable, detail.RollingUpdate.MaxSurge))
+	}
+	d.field(0, "Status", detail.Status)
+	d.field(0, "Revision", detail.Revision)
+	d.section("Containers")
+	for _, c := range detail.Containers {
+		d.line(1, c.Name+":")
+		d.field(2, "Image", c.Image)
+		d.field(2, "Ports", fmt.Sprint(c.Ports))
+	}
+	d.section("Conditions")
+	for _, c := range detail.Conditions {
+		d.line(1, c.Type, c.Status, c.Message)
+	}
+	return d.String(), nil
+}
@@ -0,0 +1,47 @@
+package server
+
+import "net/http"
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// Drain marks the server as draining so /readyz reports 503 and load balancers
+// stop routing new traffic, while in-flight and existing requests keep being
+// served.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, healthResponse{Status: "ok"}, http.StatusOK)
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.draining.Load() {
+		writeJSON(w, healthResponse{Status: "draining"}, http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, healthResponse{Status: "ready"}, http.StatusOK)
+}
+
+func (s *Server) handleAdminDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.Drain()
+	s.recordAudit("drain", "server", "", "")
+	writeJSON(w, healthResponse{Status: "draining"}, http.StatusAccepted)
+}
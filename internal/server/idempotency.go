@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyTTL is how long a cached response is replayed for its key.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyMaxBody caps the buffered request body; it leaves room for
+	// multipart framing around a full-size kubeconfig import.
+	idempotencyMaxBody = maxImportSize + 1<<20
+)
+
+// idempotencyCache remembers create responses by method, path and key so a
+// retried request gets the original answer instead of creating twice.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	entries map[string]*idempotentResponse
+}
+
+// idempotentResponse is reserved while its first request is still running;
+// done is closed once status and body are filled in, or once the key is
+// released again after a server error (status stays 0).
+type idempotentResponse struct {
+	bodyHash    [sha256.Size]byte
+	done        chan struct{}
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// bufferedResponse captures a handler's response so it can be cached before
+// being written to the client.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// idempotent runs create with Idempotency-Key support. Requests without the
+// header, and anything but POST, pass straight through. The first request for
+// a key reserves it; a repeat with the same body waits for that request and
+// replays its response, while a repeat with a different body answers 409.
+// Server errors release the key so the client can retry them.
+func (s *Server) idempotent(w http.ResponseWriter, r *http.Request, create http.HandlerFunc) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" || r.Method != http.MethodPost {
+		create(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, idempotencyMaxBody+1))
+	if err != nil {
+		http.Error(w, "unable to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > idempotencyMaxBody {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	hash := sha256.Sum256(body)
+	cacheKey := r.Method + " " + r.URL.Path + " " + key
+
+	for {
+		now := s.now()
+		s.idempotency.mu.Lock()
+		cached, ok := s.idempotency.entries[cacheKey]
+		if ok && cached.status != 0 && !now.Before(cached.expiresAt) {
+			// Expired entries are dropped lazily when their key comes back.
+			delete(s.idempotency.entries, cacheKey)
+			ok = false
+		}
+		if !ok {
+			reserved := &idempotentResponse{bodyHash: hash, done: make(chan struct{})}
+			s.idempotency.entries[cacheKey] = reserved
+			s.idempotency.mu.Unlock()
+			s.runIdempotent(w, r, create, cacheKey, reserved)
+			return
+		}
+		s.idempotency.mu.Unlock()
+
+		if cached.bodyHash != hash {
+			writeJSON(w, errorResponse{Error: "Idempotency-Key 已用于不同的请求内容"}, http.StatusConflict)
+			return
+		}
+		select {
+		case <-cached.done:
+		case <-r.Context().Done():
+			return
+		}
+		if cached.status == 0 {
+			// The first attempt failed and released the key; try again.
+			continue
+		}
+		if cached.contentType != "" {
+			w.Header().Set("Content-Type", cached.contentType)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(cached.status)
+		_, _ = w.Write(cached.body)
+		return
+	}
+}
+
+// runIdempotent runs create for the request holding the reservation, then
+// either caches its response or releases the key, and wakes any waiters. The
+// release is deferred so a panicking handler cannot leave the key reserved.
+func (s *Server) runIdempotent(w http.ResponseWriter, r *http.Request, create http.HandlerFunc, cacheKey string, reserved *idempotentResponse) {
+	rec := &bufferedResponse{header: make(http.Header)}
+	cached := false
+	defer func() {
+		if cached {
+			return
+		}
+		s.idempotency.mu.Lock()
+		delete(s.idempotency.entries, cacheKey)
+		s.idempotency.mu.Unlock()
+		close(reserved.done)
+	}()
+
+	create(rec, r)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	if rec.status < http.StatusInternalServerError {
+		s.idempotency.mu.Lock()
+		reserved.status = rec.status
+		reserved.contentType = rec.header.Get("Content-Type")
+		reserved.body = rec.body.Bytes()
+		reserved.expiresAt = s.now().Add(idempotencyTTL)
+		s.idempotency.mu.Unlock()
+		close(reserved.done)
+		cached = true
+	}
+
+	for k, values := range rec.header {
+		w.Header()[k] = values
+	}
+	w.WriteHeader(rec.status)
+	_, _ = w.Write(rec.body.Bytes())
+}
@@ -1,9 +1,14 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s_dashboard/internal/logs"
 )
@@ -15,6 +20,77 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := r.URL.Query()
+	filter, ok := s.parseLogFilter(w, query)
+	if !ok {
+		return
+	}
+
+	// A cursor parameter, even an empty one, switches to cursor pages
+	// wrapped with the token for the next request.
+	if query.Has("cursor") {
+		page, err := s.logs.ListLogsPage(s.now(), filter, query.Get("cursor"))
+		if err != nil {
+			if err == logs.ErrInvalidCursor {
+				writeJSON(w, errorResponse{Error: "cursor 无效或已过期"}, http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "failed to load logs", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, page, http.StatusOK)
+		return
+	}
+
+	entries := s.logs.ListLogs(s.now(), filter)
+	if query.Get("download") == "true" {
+		writeLogDownload(w, filter.Pod, entries)
+		return
+	}
+	writeJSON(w, entries, http.StatusOK)
+}
+
+func (s *Server) handleLogExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	format := logs.ExportFormat(strings.ToLower(query.Get("format")))
+	if format == "" {
+		format = logs.ExportText
+		if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+			format = logs.ExportCSV
+		}
+	}
+
+	filter, ok := s.parseLogFilter(w, query)
+	if !ok {
+		return
+	}
+
+	raw, err := s.logs.ExportLogs(s.now(), filter, format)
+	if err != nil {
+		if err == logs.ErrUnknownFormat {
+			writeJSON(w, errorResponse{Error: "format 参数无效，可选 text 或 csv"}, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "failed to export logs", http.StatusInternalServerError)
+		return
+	}
+
+	if format == logs.ExportCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(raw)
+}
+
+// parseLogFilter builds the log filter shared by the stream and export
+// endpoints, answering 400 and returning false on invalid parameters.
+func (s *Server) parseLogFilter(w http.ResponseWriter, query url.Values) (logs.LogFilter, bool) {
 	limit := 0
 	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
 		if v, err := strconv.Atoi(raw); err == nil {
@@ -23,14 +99,125 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filter := logs.LogFilter{
-		Namespace: query.Get("namespace"),
-		Pod:       query.Get("pod"),
-		Level:     query.Get("level"),
-		Limit:     limit,
+		Namespace:    query.Get("namespace"),
+		Pod:          query.Get("pod"),
+		ExcludeLevel: query.Get("excludeLevel"),
+		Limit:        limit,
+		Namespaces:   s.visibleNamespaces,
+		Search:       query.Get("q"),
+	}
+	levels, ok := parseLevels(query.Get("level"))
+	if !ok {
+		writeJSON(w, levelErrorResponse{Error: "日志级别无效", Levels: knownLevels}, http.StatusBadRequest)
+		return logs.LogFilter{}, false
+	}
+	filter.Levels = levels
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		writeJSON(w, errorResponse{Error: "order 参数无效，可选 asc 或 desc"}, http.StatusBadRequest)
+		return logs.LogFilter{}, false
 	}
+	if len(levels) > 0 && strings.TrimSpace(filter.ExcludeLevel) != "" {
+		writeJSON(w, errorResponse{Error: "level 与 excludeLevel 不能同时使用"}, http.StatusBadRequest)
+		return logs.LogFilter{}, false
+	}
+	return filter, true
+}
 
-	entries := s.logs.ListLogs(s.now(), filter)
-	writeJSON(w, entries, http.StatusOK)
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.idempotent(w, r, s.handleLogAppend)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleLogAppend(w http.ResponseWriter, r *http.Request) {
+	var entry logs.LogEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	entry.Namespace = strings.TrimSpace(entry.Namespace)
+	entry.Pod = strings.TrimSpace(entry.Pod)
+	if entry.Namespace == "" || entry.Pod == "" {
+		writeJSON(w, errorResponse{Error: "namespace 和 pod 不能为空"}, http.StatusBadRequest)
+		return
+	}
+	entry.Level = logs.Level(strings.ToUpper(strings.TrimSpace(string(entry.Level))))
+	if !knownLevel(entry.Level) {
+		writeJSON(w, levelErrorResponse{Error: "日志级别无效", Levels: knownLevels}, http.StatusBadRequest)
+		return
+	}
+	if entry.Timestamp == "" {
+		entry.Timestamp = s.now().UTC().Format(time.RFC3339)
+	} else if ts, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+		entry.Timestamp = ts.UTC().Format(time.RFC3339)
+	} else {
+		writeJSON(w, errorResponse{Error: "timestamp 需为 RFC3339 格式"}, http.StatusBadRequest)
+		return
+	}
+
+	stored := s.logs.AppendLog(entry)
+	s.recordAudit("append", "log", stored.Namespace, stored.Pod)
+	writeJSON(w, stored, http.StatusCreated)
+}
+
+type levelErrorResponse struct {
+	Error  string       `json:"error"`
+	Levels []logs.Level `json:"levels"`
+}
+
+// knownLevels are the severities a log entry may carry.
+var knownLevels = []logs.Level{logs.LevelInfo, logs.LevelWarn, logs.LevelError}
+
+func knownLevel(level logs.Level) bool {
+	for _, known := range knownLevels {
+		if level == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLevels turns a comma-separated level list such as "ERROR,WARN" into a
+// set, skipping empty entries. It returns false if any token is not one of
+// the known levels.
+func parseLevels(raw string) (map[logs.Level]bool, bool) {
+	var out map[logs.Level]bool
+	for _, token := range strings.Split(raw, ",") {
+		level := logs.Level(strings.ToUpper(strings.TrimSpace(token)))
+		if level == "" {
+			continue
+		}
+		if !knownLevel(level) {
+			return nil, false
+		}
+		if out == nil {
+			out = make(map[logs.Level]bool)
+		}
+		out[level] = true
+	}
+	return out, true
+}
+
+func writeLogDownload(w http.ResponseWriter, pod string, entries []logs.LogEntry) {
+	name := strings.TrimSpace(pod)
+	if name == "" {
+		name = "logs"
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".log"))
+	w.WriteHeader(http.StatusOK)
+	for _, entry := range entries {
+		_, _ = io.WriteString(w, logs.FormatLine(entry)+"\n")
+	}
 }
 
 func (s *Server) handleLogMeta(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +226,7 @@ func (s *Server) handleLogMeta(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	meta := s.logs.DescribeFilters()
+	meta := s.logs.DescribeFiltersIn(s.visibleNamespaces)
 	writeJSON(w, meta, http.StatusOK)
 }
 
@@ -49,6 +236,18 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items := s.logs.ListEvents(s.now())
+	if r.URL.Query().Get("groupBy") == "object" {
+		writeJSON(w, visibleGroups(s, s.logs.ListEventsGrouped(s.now()), eventNamespace), http.StatusOK)
+		return
+	}
+
+	query := r.URL.Query()
+	items := visibleOnly(s, s.logs.ListEventsFiltered(s.now(), logs.EventFilter{
+		Type:      query.Get("type"),
+		Namespace: query.Get("namespace"),
+		Reason:    query.Get("reason"),
+	}), eventNamespace)
 	writeJSON(w, items, http.StatusOK)
 }
+
+func eventNamespace(ev logs.Event) string { return ev.Namespace }
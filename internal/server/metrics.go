@@ -0,0 +1,167 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	prometheusContentType  = "text/plain; version=0.0.4; charset=utf-8"
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// metricFamily is one metric exposed on /metrics. Counter names omit the
+// _total suffix; the writers add it where each format expects it.
+type metricFamily struct {
+	Name    string
+	Help    string
+	Type    string
+	Unit    string
+	Samples []metricSample
+}
+
+type metricSample struct {
+	Labels [][2]string
+	Value  float64
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	families := s.collectMetrics()
+	if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+		w.Header().Set("Content-Type", openMetricsContentType)
+		writeOpenMetrics(w, families)
+		return
+	}
+	w.Header().Set("Content-Type", prometheusContentType)
+	writePrometheus(w, families)
+}
+
+func (s *Server) collectMetrics() []metricFamily {
+	now := s.now()
+
+	nodes := metricFamily{Name: "kubedash_nodes", Help: "Number of nodes by status.", Type: "gauge"}
+	nodeCounts := make(map[string]int)
+	for _, n := range s.nodes.List(now) {
+		nodeCounts[n.Status]++
+	}
+	for _, status := range sortedKeys(nodeCounts) {
+		nodes.Samples = append(nodes.Samples, metricSample{
+			Labels: [][2]string{{"status", status}},
+			Value:  float64(nodeCounts[status]),
+		})
+	}
+
+	pods := metricFamily{Name: "kubedash_pods", Help: "Number of pods by namespace and status.", Type: "gauge"}
+	restarts := metricFamily{Name: "kubedash_pod_restarts", Help: "Container restarts per pod.", Type: "counter"}
+	age := metricFamily{Name: "kubedash_pod_age_seconds", Help: "Time since the pod was created.", Type: "gauge", Unit: "seconds"}
+	podCounts := make(map[string]int)
+	for _, p := range visibleOnly(s, s.pods.List(now), podNamespace) {
+		podCounts[p.Namespace+"/"+p.Status]++
+		labels := [][2]string{{"namespace", p.Namespace}, {"pod", p.Name}}
+		restarts.Samples = append(restarts.Samples, metricSample{Labels: labels, Value: float64(p.Restarts)})
+		age.Samples = append(age.Samples, metricSample{Labels: labels, Value: float64(p.AgeSeconds)})
+	}
+	for _, key := range sortedKeys(podCounts) {
+		ns, status, _ := strings.Cut(key, "/")
+		pods.Samples = append(pods.Samples, metricSample{
+			Labels: [][2]string{{"namespace", ns}, {"status", status}},
+			Value:  float64(podCounts[key]),
+		})
+	}
+
+	desired := metricFamily{Name: "kubedash_deployment_replicas_desired", Help: "Desired replicas per deployment.", Type: "gauge"}
+	ready := metricFamily{Name: "kubedash_deployment_replicas_ready", Help: "Ready replicas per deployment.", Type: "gauge"}
+	for _, d := range s.deployments.List(now) {
+		labels := [][2]string{{"namespace", d.Namespace}, {"deployment", d.Name}}
+		desired.Samples = append(desired.Samples, metricSample{Labels: labels, Value: float64(d.DesiredReplicas)})
+		ready.Samples = append(ready.Samples, metricSample{Labels: labels, Value: float64(d.ReadyReplicas)})
+	}
+
+	alerts := metricFamily{Name: "kubedash_alerts_firing", Help: "Number of firing alerts by severity.", Type: "gauge"}
+	alertCounts := map[string]int{severityCritical: 0, severityWarning: 0}
+	for _, a := range s.evaluateAlerts(now) {
+		alertCounts[a.Severity]++
+	}
+	for _, severity := range sortedKeys(alertCounts) {
+		alerts.Samples = append(alerts.Samples, metricSample{
+			Labels: [][2]string{{"severity", severity}},
+			Value:  float64(alertCounts[severity]),
+		})
+	}
+
+	families := []metricFamily{nodes, pods, restarts, age, desired, ready, alerts}
+	sort.SliceStable(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families
+}
+
+// writePrometheus renders families in the Prometheus text exposition format.
+func writePrometheus(w io.Writer, families []metricFamily) {
+	for _, f := range families {
+		name := f.Name
+		if f.Type == "counter" {
+			name += "_total"
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", name, f.Help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, f.Type)
+		for _, sample := range f.Samples {
+			writeSample(w, name, sample)
+		}
+	}
+}
+
+// writeOpenMetrics renders families in the OpenMetrics text format, which
+// adds unit metadata and requires the trailing # EOF line.
+func writeOpenMetrics(w io.Writer, families []metricFamily) {
+	for _, f := range families {
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.Name, f.Type)
+		if f.Unit != "" {
+			fmt.Fprintf(w, "# UNIT %s %s\n", f.Name, f.Unit)
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", f.Name, f.Help)
+		name := f.Name
+		if f.Type == "counter" {
+			name += "_total"
+		}
+		for _, sample := range f.Samples {
+			writeSample(w, name, sample)
+		}
+	}
+	fmt.Fprint(w, "# EOF\n")
+}
+
+func writeSample(w io.Writer, name string, sample metricSample) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(sample.Labels) > 0 {
+		b.WriteByte('{')
+		for i, label := range sample.Labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(label[0])
+			b.WriteString(`="`)
+			b.WriteString(escapeLabelValue(label[1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(sample.Value, 'g', -1, 64))
+	b.WriteByte('\n')
+	io.WriteString(w, b.String())
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
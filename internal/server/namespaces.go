@@ -4,16 +4,27 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"k8s_dashboard/internal/cluster"
+	"k8s_dashboard/internal/deploy"
 	"k8s_dashboard/internal/namespace"
 )
 
+// namespaceGraceSeconds is how long a deleted namespace stays Terminating
+// unless the request overrides it with gracePeriodSeconds.
+const namespaceGraceSeconds = 5
+
 type createNamespaceRequest struct {
 	Name   string            `json:"name"`
 	Labels map[string]string `json:"labels"`
 }
 
+type updateNamespaceRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
 type errorResponse struct {
 	Error string `json:"error"`
 }
@@ -23,34 +34,162 @@ func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		s.handleNamespacesList(w, r)
 	case http.MethodPost:
-		s.handleNamespaceCreate(w, r)
+		s.idempotent(w, r, s.handleNamespaceCreate)
 	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
 func (s *Server) handleNamespaceByName(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
+	name := strings.TrimPrefix(r.URL.Path, "/api/namespaces/")
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if ns, ok := strings.CutSuffix(name, "/overview"); ok && ns != "" {
+		s.handleNamespaceOverview(w, r, ns)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.handleNamespaceGet(w, r, name)
+	case http.MethodPut:
+		s.handleNamespaceUpdate(w, r, name)
+	case http.MethodDelete:
+		s.handleNamespaceDelete(w, r, name)
+	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleNamespaceGet(w http.ResponseWriter, r *http.Request, name string) {
+	loc, err := displayZone(r)
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "时区无效"}, http.StatusBadRequest)
 		return
 	}
 
-	name := strings.TrimPrefix(r.URL.Path, "/api/namespaces/")
-	if name == "" {
-		http.NotFound(w, r)
+	var ns namespace.Namespace
+	if r.URL.Query().Get("inherited") == "true" {
+		ns, err = s.namespaces.GetInherited(name, s.now())
+	} else {
+		ns, err = s.namespaces.Get(name, s.now())
+	}
+	if err != nil {
+		switch err {
+		case namespace.ErrNotFound:
+			writeJSON(w, errorResponse{Error: "命名空间不存在"}, http.StatusNotFound)
+		case namespace.ErrCycle:
+			writeJSON(w, errorResponse{Error: "命名空间父级关系存在循环"}, http.StatusConflict)
+		default:
+			http.Error(w, "failed to load namespace", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	if deleted := s.namespaces.Delete(name); !deleted {
-		http.Error(w, "namespace not found", http.StatusNotFound)
+	if loc != nil {
+		ns.CreatedAtLocal = localizeTime(ns.CreatedAt, loc)
+	}
+	writeJSON(w, ns, http.StatusOK)
+}
+
+// namespaceOverview bundles what the namespace detail page shows: the
+// cluster.NamespaceOverview aggregate plus the namespace itself. The mock API
+// has no ResourceQuota objects, so Resources reports the requests and limits
+// summed across the namespace's deployments.
+type namespaceOverview struct {
+	cluster.NamespaceOverviewData
+	Metadata  namespace.Namespace `json:"metadata"`
+	Resources deploy.Resources    `json:"resources"`
+}
+
+func (s *Server) handleNamespaceOverview(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	now := s.now()
+	ns, err := s.namespaces.Get(name, now)
+	if err == namespace.ErrNotFound || (err == nil && !s.namespaceVisible(name)) {
+		writeJSON(w, errorResponse{Error: "命名空间不存在"}, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to load namespace", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, namespaceOverview{
+		NamespaceOverviewData: cluster.NamespaceOverview(now, name, s.overviewSources()),
+		Metadata:              ns,
+		Resources:             s.deployments.NamespaceResources(name, now),
+	}, http.StatusOK)
+}
+
+func (s *Server) handleNamespaceUpdate(w http.ResponseWriter, r *http.Request, name string) {
+	var req updateNamespaceRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	ns, err := s.namespaces.Update(name, req.Labels, s.now())
+	if err != nil {
+		switch err {
+		case namespace.ErrNotFound:
+			writeJSON(w, errorResponse{Error: "命名空间不存在"}, http.StatusNotFound)
+		case namespace.ErrInvalidLabels:
+			writeJSON(w, errorResponse{Error: "标签格式不正确，请遵循 Kubernetes 标签规范"}, http.StatusBadRequest)
+		default:
+			http.Error(w, "failed to update namespace", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.recordAudit("update", "namespace", "", ns.Name)
+	writeJSON(w, ns, http.StatusOK)
+}
+
+func (s *Server) handleNamespaceDelete(w http.ResponseWriter, r *http.Request, name string) {
+	opts := namespace.DeleteOptions{GraceSeconds: namespaceGraceSeconds}
+	if raw := strings.TrimSpace(r.URL.Query().Get("gracePeriodSeconds")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			writeJSON(w, errorResponse{Error: "gracePeriodSeconds 必须为非负整数"}, http.StatusBadRequest)
+			return
+		}
+		opts.GraceSeconds = v
+	}
+
+	ns, err := s.namespaces.Delete(name, s.now(), opts)
+	if err != nil {
+		switch err {
+		case namespace.ErrNotFound:
+			http.Error(w, "namespace not found", http.StatusNotFound)
+		case namespace.ErrProtected:
+			writeJSON(w, errorResponse{Error: "系统命名空间受保护，禁止删除"}, http.StatusForbidden)
+		default:
+			http.Error(w, "failed to delete namespace", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.recordAudit("delete", "namespace", "", name)
+	if ns.Status == "Terminating" {
+		writeJSON(w, ns, http.StatusAccepted)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (s *Server) handleNamespacesList(w http.ResponseWriter, r *http.Request) {
-	payload := s.namespaces.List(s.now())
+	payload, err := s.namespaces.ListFiltered(s.now(), r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "标签选择器格式不正确，请使用 key=value 形式"}, http.StatusBadRequest)
+		return
+	}
 	writeJSON(w, payload, http.StatusOK)
 }
 
@@ -81,6 +220,7 @@ func (s *Server) handleNamespaceCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit("create", "namespace", "", ns.Name)
 	writeJSON(w, ns, http.StatusCreated)
 }
 
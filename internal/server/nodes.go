@@ -1,43 +1,187 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"k8s_dashboard/internal/node"
 )
 
+type taintsRequest struct {
+	Taints []string `json:"taints"`
+}
+
 func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	payload := s.nodes.List(s.now())
+	query := r.URL.Query()
+	key, err := node.ParseSortKey(query.Get("sort"))
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "不支持的排序字段"}, http.StatusBadRequest)
+		return
+	}
+	order := node.SortOrder{Key: key}
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		order.Desc = true
+	default:
+		writeJSON(w, errorResponse{Error: "排序方向无效"}, http.StatusBadRequest)
+		return
+	}
+
+	filter := node.NodeFilter{
+		Status: query.Get("status"),
+		Role:   query.Get("role"),
+	}
+	if raw := query.Get("minHeadroom"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 || v > 100 {
+			writeJSON(w, errorResponse{Error: "minHeadroom 参数无效，应为 0-100"}, http.StatusBadRequest)
+			return
+		}
+		filter.MinHeadroom = v
+	}
+	payload := timeStore(s, "nodes.List", func() []node.NodeSummary { return s.nodes.ListFiltered(s.now(), filter, order) })
+	if query.Get("rawRoles") != "true" {
+		for i := range payload {
+			payload[i].Roles = node.CanonicalRoles(payload[i].Roles)
+		}
+	}
 	writeJSON(w, payload, http.StatusOK)
 }
 
 func (s *Server) handleNodeByName(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	if r.URL.Path == "/api/nodes/conditions" {
+		s.handleNodeConditions(w, r)
 		return
 	}
 
-	name := strings.TrimPrefix(r.URL.Path, "/api/nodes/")
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/nodes/"), "/")
+	name := segments[0]
 	if name == "" {
 		http.NotFound(w, r)
 		return
 	}
 
-	detail, err := s.nodes.Get(name, s.now())
+	switch {
+	case len(segments) == 1:
+		s.handleNodeDetail(w, r, name)
+	case len(segments) == 2 && (segments[1] == "cordon" || segments[1] == "uncordon"):
+		s.handleNodeCordon(w, r, name, segments[1] == "uncordon")
+	case len(segments) == 2 && segments[1] == "drain":
+		s.handleNodeDrain(w, r, name)
+	case len(segments) == 2 && segments[1] == "taints":
+		s.handleNodeTaints(w, r, name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleNodeConditions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.nodes.ConditionMatrix(s.now()), http.StatusOK)
+}
+
+func (s *Server) handleNodeDetail(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	detail, err := timeStoreErr(s, "nodes.Get", func() (node.NodeDetail, error) { return s.nodes.Get(name, s.now()) })
 	if err != nil {
-		if err == node.ErrNotFound {
-			writeJSON(w, errorResponse{Error: "节点不存在"}, http.StatusNotFound)
+		writeNodeError(w, err, "failed to load node detail")
+		return
+	}
+
+	writeNodeDetail(w, r, detail)
+}
+
+func (s *Server) handleNodeCordon(w http.ResponseWriter, r *http.Request, name string, schedulable bool) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	detail, err := s.nodes.SetSchedulable(name, schedulable, s.now())
+	if err != nil {
+		writeNodeError(w, err, "failed to update node")
+		return
+	}
+
+	action := "cordon"
+	if schedulable {
+		action = "uncordon"
+	}
+	s.recordAudit(action, "node", "", detail.Name)
+	writeNodeDetail(w, r, detail)
+}
+
+func (s *Server) handleNodeDrain(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	detail, err := s.nodes.Drain(name, s.now())
+	if err != nil {
+		writeNodeError(w, err, "failed to drain node")
+		return
+	}
+
+	s.recordAudit("drain", "node", "", detail.Name)
+	writeNodeDetail(w, r, detail)
+}
+
+func (s *Server) handleNodeTaints(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req taintsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := s.nodes.SetTaints(name, req.Taints, s.now())
+	if err != nil {
+		if errors.Is(err, node.ErrInvalidTaint) {
+			writeJSON(w, errorResponse{Error: "污点无效: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, "failed to load node detail", http.StatusInternalServerError)
+		writeNodeError(w, err, "failed to update node taints")
 		return
 	}
 
+	s.recordAudit("taint", "node", "", detail.Name)
+	writeNodeDetail(w, r, detail)
+}
+
+// writeNodeDetail renders roles canonically unless rawRoles=true is set.
+func writeNodeDetail(w http.ResponseWriter, r *http.Request, detail node.NodeDetail) {
+	if r.URL.Query().Get("rawRoles") != "true" {
+		detail.Roles = node.CanonicalRoles(detail.Roles)
+	}
 	writeJSON(w, detail, http.StatusOK)
 }
+
+func writeNodeError(w http.ResponseWriter, err error, fallback string) {
+	if err == node.ErrNotFound {
+		writeJSON(w, errorResponse{Error: "节点不存在"}, http.StatusNotFound)
+		return
+	}
+	http.Error(w, fallback, http.StatusInternalServerError)
+}
@@ -1,9 +1,13 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"k8s_dashboard/internal/deploy"
 	"k8s_dashboard/internal/pod"
 )
 
@@ -13,31 +17,425 @@ func (s *Server) handlePods(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	payload := s.pods.List(s.now())
+	query := r.URL.Query()
+	filter := pod.PodFilter{
+		Namespace: query.Get("namespace"),
+		Node:      query.Get("node"),
+		Status:    query.Get("status"),
+
+		Namespaces: s.visibleNamespaces,
+	}
+	if query.Get("watch") == "true" {
+		s.handlePodWatch(w, r, filter)
+		return
+	}
+
+	page, ok := parsePage(w, query)
+	if !ok {
+		return
+	}
+	key, err := pod.ParseSortKey(query.Get("sort"))
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "不支持的排序字段"}, http.StatusBadRequest)
+		return
+	}
+	filter.Sort = key
+	expand := query.Get("expand")
+	if expand != "" && expand != "owner" {
+		writeJSON(w, errorResponse{Error: "expand 参数无效，仅支持 owner"}, http.StatusBadRequest)
+		return
+	}
+
+	var total int
+	payload := timeStore(s, "pods.List", func() []pod.Summary {
+		items, n := s.pods.ListPage(s.now(), filter, page)
+		total = n
+		return items
+	})
+	writePageHeaders(w, page, total)
+	if expand == "owner" {
+		writeJSON(w, s.expandPodOwners(payload), http.StatusOK)
+		return
+	}
 	writeJSON(w, payload, http.StatusOK)
 }
 
+// podWithOwner is a pod summary whose owner reference is replaced by the
+// owning deployment's summary, or null when it has none.
+type podWithOwner struct {
+	pod.Summary
+	Owner *deploy.Summary `json:"owner"`
+}
+
+func (s *Server) expandPodOwners(pods []pod.Summary) []podWithOwner {
+	owners := make(map[string]deploy.Summary)
+	for _, d := range s.deployments.List(s.now()) {
+		owners[d.Namespace+"/"+d.Name] = d
+	}
+
+	out := make([]podWithOwner, 0, len(pods))
+	for _, p := range pods {
+		item := podWithOwner{Summary: p}
+		if p.Owner != nil && p.Owner.Kind == "Deployment" {
+			if d, ok := owners[p.Namespace+"/"+p.Owner.Name]; ok {
+				item.Owner = &d
+			}
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// parsePage reads the limit and offset query parameters, answering 400 and
+// returning false when either is negative or not a number. They are checked
+// in that order so the reported parameter is stable.
+func parsePage(w http.ResponseWriter, query url.Values) (pod.Page, bool) {
+	var page pod.Page
+	params := []struct {
+		name string
+		dst  *int
+	}{{"limit", &page.Limit}, {"offset", &page.Offset}}
+	for _, param := range params {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeJSON(w, errorResponse{Error: param.name + " 参数无效"}, http.StatusBadRequest)
+			return pod.Page{}, false
+		}
+		*param.dst = n
+	}
+	return page.Normalize(), true
+}
+
+func writePageHeaders(w http.ResponseWriter, page pod.Page, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("X-Limit", strconv.Itoa(page.Limit))
+	w.Header().Set("X-Offset", strconv.Itoa(page.Offset))
+}
+
+type podLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+type podLabelsResponse struct {
+	Updated int `json:"updated"`
+}
+
+type batchGetRequest struct {
+	Names []string `json:"names"`
+}
+
+type containerErrorResponse struct {
+	Error      string   `json:"error"`
+	Containers []string `json:"containers"`
+}
+
+// batchGetResult reports one requested name. Ambiguous marks a name found in
+// several visible namespaces, which Found alone cannot tell from a miss.
+type batchGetResult struct {
+	Name      string      `json:"name"`
+	Found     bool        `json:"found"`
+	Ambiguous bool        `json:"ambiguous,omitempty"`
+	Pod       *pod.Detail `json:"pod,omitempty"`
+}
+
+// podSubresources lists the path segments served under /api/pods/{name}/.
+// Any other second segment is treated as /api/pods/{namespace}/{name}.
+var podSubresources = map[string]bool{
+	"connectivity": true,
+	"events":       true,
+	"logs":         true,
+	"metrics":      true,
+}
+
 func (s *Server) handlePodByName(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	switch r.URL.Path {
+	case "/api/pods/batch-get":
+		s.handlePodBatchGet(w, r)
+		return
+	case "/api/pods/grouped":
+		s.handlePodsGrouped(w, r)
+		return
+	case "/api/pods/labels":
+		s.handlePodLabels(w, r)
+		return
+	case "/api/pods/names":
+		s.handlePodNames(w, r)
 		return
 	}
 
-	name := strings.TrimPrefix(r.URL.Path, "/api/pods/")
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/pods/"), "/")
+	name := segments[0]
 	if name == "" {
 		http.NotFound(w, r)
 		return
 	}
 
-	detail, err := s.pods.Get(name, s.now())
+	namespace, podName := "", name
+	if len(segments) == 2 && !podSubresources[segments[1]] {
+		namespace, podName = name, segments[1]
+	}
+	namespace, err := s.resolvePod(namespace, podName)
+	if err != nil {
+		writePodError(w, err, "failed to resolve pod")
+		return
+	}
+
+	switch {
+	case len(segments) == 1:
+		s.handlePod(w, r, namespace, name)
+	case len(segments) == 2 && segments[1] == "connectivity":
+		s.handlePodConnectivity(w, r, namespace, name)
+	case len(segments) == 2 && segments[1] == "events":
+		s.handlePodEvents(w, r, namespace, name)
+	case len(segments) == 2 && segments[1] == "logs":
+		s.handlePodLogs(w, r, namespace, name)
+	case len(segments) == 2 && segments[1] == "metrics":
+		s.handlePodMetrics(w, r, namespace, name)
+	case len(segments) == 3 && segments[1] == "logs" && segments[2] == "stream":
+		s.handlePodLogStream(w, r, namespace, name)
+	case len(segments) == 2 && !podSubresources[segments[1]] && segments[1] != "":
+		s.handlePod(w, r, namespace, podName)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handlePod serves a pod addressed by name, or by namespace and name when a
+// namespace is given.
+func (s *Server) handlePod(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handlePodDetail(w, namespace, name)
+	case http.MethodDelete:
+		s.handlePodDelete(w, namespace, name)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handlePodDetail(w http.ResponseWriter, namespace, name string) {
+	var (
+		detail pod.Detail
+		err    error
+	)
+	if namespace != "" {
+		detail, err = timeStoreErr(s, "pods.Get", func() (pod.Detail, error) { return s.pods.GetNamespaced(namespace, name, s.now()) })
+	} else {
+		detail, err = timeStoreErr(s, "pods.Get", func() (pod.Detail, error) { return s.pods.Get(name, s.now()) })
+	}
+	if err != nil {
+		writePodError(w, err, "failed to load pod detail")
+		return
+	}
+
+	writeJSON(w, detail, http.StatusOK)
+}
+
+func (s *Server) handlePodDelete(w http.ResponseWriter, namespace, name string) {
+	var deleted bool
+	if namespace != "" {
+		deleted = s.pods.DeleteNamespaced(namespace, name)
+	} else {
+		deleted = s.pods.Delete(name)
+	}
+	if !deleted {
+		err := pod.ErrNotFound
+		if namespace == "" {
+			// Surface ErrAmbiguous for duplicated names instead of a plain 404.
+			_, err = s.pods.Get(name, s.now())
+		}
+		if err == nil {
+			err = pod.ErrNotFound
+		}
+		writePodError(w, err, "failed to delete pod")
+		return
+	}
+
+	s.recordAudit("delete", "pod", namespace, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePodConnectivity(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	target := strings.TrimSpace(r.URL.Query().Get("target"))
+	if target == "" {
+		writeJSON(w, errorResponse{Error: "缺少 target 参数"}, http.StatusBadRequest)
+		return
+	}
+
+	targetNamespace, err := s.resolvePod("", target)
+	if err != nil {
+		writePodError(w, err, "failed to resolve pod")
+		return
+	}
+
+	result, err := s.pods.Connectivity(namespace, name, targetNamespace, target)
+	if err != nil {
+		writePodError(w, err, "failed to evaluate connectivity")
+		return
+	}
+
+	writeJSON(w, result, http.StatusOK)
+}
+
+func (s *Server) handlePodEvents(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	page, ok := parsePage(w, query)
+	if !ok {
+		return
+	}
+
+	events, total, err := s.pods.Events(namespace, name, s.now(), pod.EventFilter{
+		Type:   query.Get("type"),
+		Reason: query.Get("reason"),
+	}, page)
 	if err != nil {
-		if err == pod.ErrNotFound {
-			writeJSON(w, errorResponse{Error: "Pod 不存在"}, http.StatusNotFound)
+		writePodError(w, err, "failed to load pod events")
+		return
+	}
+
+	writePageHeaders(w, page, total)
+	writeJSON(w, events, http.StatusOK)
+}
+
+func (s *Server) handlePodLogs(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := s.pods.Logs(namespace, name, r.URL.Query().Get("container"))
+	if err != nil {
+		if err == pod.ErrUnknownContainer {
+			writeJSON(w, containerErrorResponse{Error: "容器不存在", Containers: result.Containers}, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, "failed to load pod detail", http.StatusInternalServerError)
+		writePodError(w, err, "failed to load pod logs")
 		return
 	}
 
-	writeJSON(w, detail, http.StatusOK)
+	w.Header().Set("X-Container", result.Container)
+	writeJSON(w, result, http.StatusOK)
+}
+
+func (s *Server) handlePodMetrics(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	metrics, err := s.pods.Metrics(namespace, name)
+	if err != nil {
+		writePodError(w, err, "failed to load pod metrics")
+		return
+	}
+
+	writeJSON(w, metrics, http.StatusOK)
+}
+
+func (s *Server) handlePodsGrouped(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, visibleGroups(s, s.pods.ListGrouped(s.now()), podNamespace), http.StatusOK)
+}
+
+func (s *Server) handlePodNames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	names := s.pods.ListNames(r.URL.Query().Get("namespace"))
+	writeJSON(w, visibleOnly(s, names, func(p pod.PodName) string { return p.Namespace }), http.StatusOK)
+}
+
+func (s *Server) handlePodLabels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req podLabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	selector := r.URL.Query().Get("labelSelector")
+	updated, err := s.pods.UpdateLabelsBySelector(selector, req.Labels, pod.PodFilter{Namespaces: s.visibleNamespaces})
+	if err != nil {
+		switch err {
+		case pod.ErrInvalidSelector:
+			writeJSON(w, errorResponse{Error: "标签选择器格式不正确，请使用 key=value 形式"}, http.StatusBadRequest)
+		case pod.ErrReservedLabel:
+			writeJSON(w, errorResponse{Error: "不允许修改系统保留标签"}, http.StatusBadRequest)
+		case pod.ErrInvalidLabels:
+			writeJSON(w, errorResponse{Error: "标签格式不正确"}, http.StatusBadRequest)
+		default:
+			http.Error(w, "failed to update pod labels", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	for _, p := range updated {
+		s.recordAudit("label", "pod", p.Namespace, p.Name)
+	}
+	writeJSON(w, podLabelsResponse{Updated: len(updated)}, http.StatusOK)
+}
+
+func (s *Server) handlePodBatchGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req batchGetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]batchGetResult, 0, len(req.Names))
+	for _, name := range req.Names {
+		result := batchGetResult{Name: name}
+		switch detail, err := s.visiblePod(name); err {
+		case nil:
+			result.Found = true
+			result.Pod = &detail
+		case pod.ErrAmbiguous:
+			result.Ambiguous = true
+		}
+		results = append(results, result)
+	}
+
+	writeJSON(w, results, http.StatusOK)
+}
+
+func podNamespace(p pod.Summary) string { return p.Namespace }
+
+func writePodError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case pod.ErrNotFound:
+		writeJSON(w, errorResponse{Error: "Pod 不存在"}, http.StatusNotFound)
+	case pod.ErrAmbiguous:
+		writeJSON(w, errorResponse{Error: "Pod 名称在多个命名空间中重复，请使用 /api/pods/{namespace}/{name}"}, http.StatusConflict)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
 }
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s_dashboard/internal/pod"
+)
+
+// podLogStreamInterval is how often, by the injected clock, a followed log
+// stream emits the next line.
+const podLogStreamInterval = time.Second
+
+// handlePodLogStream tails a container's logs as server-sent events. The
+// seeded lines are replayed one per interval, followed by synthetic lines until
+// the client disconnects. With follow=false the backlog is sent at once and the
+// stream closes.
+func (s *Server) handlePodLogStream(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := s.pods.Logs(namespace, name, r.URL.Query().Get("container"))
+	if err != nil {
+		if err == pod.ErrUnknownContainer {
+			writeJSON(w, containerErrorResponse{Error: "容器不存在", Containers: result.Containers}, http.StatusBadRequest)
+			return
+		}
+		writePodError(w, err, "failed to load pod logs")
+		return
+	}
+
+	stream, ok := openSSE(w)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("follow") == "false" {
+		for _, line := range result.Lines {
+			sendLogLine(stream, line)
+		}
+		return
+	}
+
+	backlog := result.Lines
+	if len(backlog) > 0 {
+		sendLogLine(stream, backlog[0])
+		backlog = backlog[1:]
+	}
+
+	last := s.now()
+	seq := 0
+	s.runSSE(r.Context(), stream, func(now time.Time) bool {
+		for now.Sub(last) >= podLogStreamInterval {
+			last = last.Add(podLogStreamInterval)
+			if len(backlog) > 0 {
+				sendLogLine(stream, backlog[0])
+				backlog = backlog[1:]
+				continue
+			}
+			seq++
+			sendLogLine(stream, pod.LogLine{
+				Container: result.Container,
+				Message:   fmt.Sprintf("[INFO] %s synthetic log line %d", last.Format("15:04:05"), seq),
+			})
+		}
+		return true
+	})
+}
+
+// handlePodWatch streams pod changes as newline-delimited JSON watch events,
+// starting with an ADDED event for every pod currently matching the filter.
+func (s *Server) handlePodWatch(w http.ResponseWriter, r *http.Request, filter pod.PodFilter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	initial, events, stop := s.pods.Watch(s.now(), filter)
+	defer stop()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for _, item := range initial {
+		if err := enc.Encode(pod.WatchEvent{Type: pod.Added, Object: item}); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func sendLogLine(stream *sseStream, line pod.LogLine) {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	stream.send("log", string(data))
+}
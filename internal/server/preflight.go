@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s_dashboard/internal/logs"
+)
+
+var logsProbeFilter = logs.LogFilter{Limit: 1}
+
+type preflightCheck struct {
+	Store     string  `json:"store"`
+	OK        bool    `json:"ok"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type preflightResponse struct {
+	OK     bool             `json:"ok"`
+	Checks []preflightCheck `json:"checks"`
+}
+
+func (s *Server) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := s.now()
+	probes := []struct {
+		name string
+		read func()
+	}{
+		{"namespaces", func() { s.namespaces.List(now) }},
+		{"nodes", func() { s.nodes.List(now) }},
+		{"pods", func() { s.pods.List(now) }},
+		{"deployments", func() { s.deployments.List(now) }},
+		{"services", func() { s.services.List(now) }},
+		{"logs", func() { s.logs.ListLogs(now, logsProbeFilter) }},
+		{"kubeconfigs", func() { s.kubeconfigs.List() }},
+		{"audit", func() { s.audit.List() }},
+	}
+
+	resp := preflightResponse{OK: true, Checks: make([]preflightCheck, 0, len(probes))}
+	for _, probe := range probes {
+		check := runProbe(probe.name, probe.read)
+		if !check.OK {
+			resp.OK = false
+		}
+		resp.Checks = append(resp.Checks, check)
+	}
+
+	status := http.StatusOK
+	if !resp.OK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, resp, status)
+}
+
+func runProbe(name string, read func()) (check preflightCheck) {
+	check = preflightCheck{Store: name}
+	start := time.Now()
+	defer func() {
+		check.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+		if rec := recover(); rec != nil {
+			check.OK = false
+			check.Error = fmt.Sprint(rec)
+		}
+	}()
+
+	read()
+	check.OK = true
+	return check
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// prefixSearchLimit caps the names returned per kind by /api/search/prefix.
+const prefixSearchLimit = 10
+
+type prefixSearchResponse struct {
+	Prefix      string   `json:"prefix"`
+	Namespaces  []string `json:"namespaces"`
+	Nodes       []string `json:"nodes"`
+	Pods        []string `json:"pods"`
+	Deployments []string `json:"deployments"`
+	Services    []string `json:"services"`
+}
+
+func (s *Server) handlePrefixSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prefix := strings.TrimSpace(r.URL.Query().Get("p"))
+	if prefix == "" {
+		writeJSON(w, errorResponse{Error: "缺少 p 参数"}, http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, prefixSearchResponse{
+		Prefix:      prefix,
+		Namespaces:  s.namespaces.NamesWithPrefix(prefix, s.visibleNamespaces, s.now(), prefixSearchLimit),
+		Nodes:       s.nodes.NamesWithPrefix(prefix, prefixSearchLimit),
+		Pods:        s.pods.NamesWithPrefix(prefix, s.visibleNamespaces, prefixSearchLimit),
+		Deployments: s.deployments.NamesWithPrefix(prefix, prefixSearchLimit),
+		Services:    s.services.NamesWithPrefix(prefix, s.visibleNamespaces, prefixSearchLimit),
+	}, http.StatusOK)
+}
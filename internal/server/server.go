@@ -1,9 +1,14 @@
 package server
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"k8s_dashboard/internal/audit"
 	"k8s_dashboard/internal/cluster"
 	"k8s_dashboard/internal/deploy"
 	"k8s_dashboard/internal/kubeconfig"
@@ -25,15 +30,103 @@ type Server struct {
 	services    *service.Store
 	logs        *logs.Store
 	kubeconfigs *kubeconfig.Store
+	audit       *audit.Store
+
+	sseKeepalive time.Duration
+	ssePoll      time.Duration
+
+	draining atomic.Bool
+
+	health    cluster.HealthConfig
+	accessLog *jsonLogger
+	cors      *corsPolicy
+
+	// degradedThreshold is the overview health score below which API
+	// responses carry the degraded header.
+	degradedThreshold int
+
+	slowThreshold time.Duration
+	slowLog       *log.Logger
+
+	alertMu     sync.Mutex
+	alertFiring map[string]time.Time
+	alertAcks   map[string]alertAck
+
+	idempotency idempotencyCache
+
+	// visibleNamespaces limits logs, events, pods and services to these
+	// namespaces; empty means everything is visible.
+	visibleNamespaces []string
+}
+
+// Option customises a Server during construction.
+type Option func(*Server)
+
+// WithAuditRetention caps the number of audit entries kept in memory.
+func WithAuditRetention(n int) Option {
+	return func(s *Server) {
+		s.audit = audit.NewStore(n)
+	}
+}
+
+// WithVisibleNamespaces hides logs, events, pods and services outside the
+// given namespaces from every endpoint. An empty list shows everything.
+func WithVisibleNamespaces(namespaces []string) Option {
+	return func(s *Server) {
+		s.visibleNamespaces = append([]string(nil), namespaces...)
+	}
+}
+
+// WithHealthWeights overrides the deductions used by /api/cluster/health.
+// It panics, like WithNamespaceNameRule, when cfg fails Validate.
+func WithHealthWeights(cfg cluster.HealthConfig) Option {
+	return func(s *Server) {
+		if err := cfg.Validate(); err != nil {
+			panic(fmt.Errorf("server: WithHealthWeights: %w", err))
+		}
+		s.health = cfg
+	}
+}
+
+// defaultDegradedThreshold flags the cluster as degraded whenever its
+// overview health is not Healthy.
+const defaultDegradedThreshold = 80
+
+// degradedHeader is set on the overview and health responses while the
+// cluster is degraded.
+const degradedHeader = "X-Cluster-Degraded"
+
+// WithDegradedThreshold sets the overview health score below which the
+// cluster counts as degraded. It panics on scores outside 0-100.
+func WithDegradedThreshold(score int) Option {
+	return func(s *Server) {
+		if score < 0 || score > 100 {
+			panic(fmt.Sprintf("server: WithDegradedThreshold: score %d outside 0-100", score))
+		}
+		s.degradedThreshold = score
+	}
+}
+
+// WithNamespaceNameRule replaces the naming rule enforced when creating
+// namespaces. Like regexp.MustCompile, it panics when
+// namespace.NewStoreWithNameRule rejects the rule.
+func WithNamespaceNameRule(rule namespace.NameRule) Option {
+	return func(s *Server) {
+		store, err := namespace.NewStoreWithNameRule(s.now(), rule)
+		if err != nil {
+			panic(fmt.Errorf("server: WithNamespaceNameRule: %w", err))
+		}
+		s.namespaces = store
+	}
 }
 
 // New constructs a server with default dependencies.
-func New() *Server {
-	return NewWithClock(time.Now)
+func New(opts ...Option) *Server {
+	return NewWithClock(time.Now, opts...)
 }
 
 // NewWithClock allows injection of a deterministic time source for testing.
-func NewWithClock(now func() time.Time) *Server {
+func NewWithClock(now func() time.Time, opts ...Option) *Server {
 	s := &Server{
 		mux:         http.NewServeMux(),
 		now:         now,
@@ -44,6 +137,19 @@ func NewWithClock(now func() time.Time) *Server {
 		services:    service.NewStore(now()),
 		logs:        logs.NewStore(now()),
 		kubeconfigs: kubeconfig.NewStore(),
+		audit:       audit.NewStore(audit.DefaultRetention),
+		health:      cluster.DefaultHealthConfig(),
+		alertFiring: make(map[string]time.Time),
+		alertAcks:   make(map[string]alertAck),
+		idempotency: idempotencyCache{entries: make(map[string]*idempotentResponse)},
+
+		degradedThreshold: defaultDegradedThreshold,
+
+		sseKeepalive: defaultSSEKeepalive,
+		ssePoll:      defaultSSEPoll,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 	s.registerRoutes()
 	return s
@@ -51,12 +157,29 @@ func NewWithClock(now func() time.Time) *Server {
 
 // ServeHTTP makes Server implement http.Handler.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.applyCORS(w, r) {
+		return
+	}
+	if s.accessLog != nil {
+		s.serveLogged(w, r)
+		return
+	}
 	s.mux.ServeHTTP(w, r)
 }
 
 func (s *Server) registerRoutes() {
 	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mux.HandleFunc("/api/admin/drain", s.handleAdminDrain)
 	s.mux.HandleFunc("/api/cluster/overview", s.handleClusterOverview)
+	s.mux.HandleFunc("/api/cluster/capacity", s.handleClusterCapacity)
+	s.mux.HandleFunc("/api/cluster/metrics", s.handleClusterMetrics)
+	s.mux.HandleFunc("/api/cluster/status-breakdown", s.handleClusterStatusBreakdown)
+	s.mux.HandleFunc("/api/cluster/health", s.handleClusterHealth)
+	s.mux.HandleFunc("/api/cluster/snapshot", s.handleClusterSnapshot)
+	s.mux.HandleFunc("/api/cluster/diff", s.handleClusterDiff)
 	s.mux.HandleFunc("/api/namespaces", s.handleNamespaces)
 	s.mux.HandleFunc("/api/namespaces/", s.handleNamespaceByName)
 	s.mux.HandleFunc("/api/nodes", s.handleNodes)
@@ -67,11 +190,21 @@ func (s *Server) registerRoutes() {
 	s.mux.HandleFunc("/api/deployments/", s.handleDeploymentByName)
 	s.mux.HandleFunc("/api/services", s.handleServices)
 	s.mux.HandleFunc("/api/services/", s.handleServiceByName)
+	s.mux.HandleFunc("/api/logs", s.handleLogs)
 	s.mux.HandleFunc("/api/logs/stream", s.handleLogStream)
 	s.mux.HandleFunc("/api/logs/meta", s.handleLogMeta)
+	s.mux.HandleFunc("/api/logs/export", s.handleLogExport)
 	s.mux.HandleFunc("/api/events", s.handleEvents)
 	s.mux.HandleFunc("/api/cluster/import", s.handleClusterImport)
 	s.mux.HandleFunc("/api/cluster/imports", s.handleClusterImports)
+	s.mux.HandleFunc("/api/cluster/imports/", s.handleClusterImportByName)
+	s.mux.HandleFunc("/api/audit", s.handleAudit)
+	s.mux.HandleFunc("/api/audit/resource", s.handleResourceAudit)
+	s.mux.HandleFunc("/api/alerts", s.handleAlerts)
+	s.mux.HandleFunc("/api/alerts/", s.handleAlertByID)
+	s.mux.HandleFunc("/api/preflight", s.handlePreflight)
+	s.mux.HandleFunc("/api/describe", s.handleDescribe)
+	s.mux.HandleFunc("/api/search/prefix", s.handlePrefixSearch)
 }
 
 func (s *Server) handleClusterOverview(w http.ResponseWriter, r *http.Request) {
@@ -80,7 +213,45 @@ func (s *Server) handleClusterOverview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	overview := cluster.MockOverview(s.now())
+	if ns := r.URL.Query().Get("namespace"); ns != "" {
+		scoped := cluster.EmptyNamespaceOverview(ns)
+		if s.namespaceVisible(ns) {
+			scoped = cluster.NamespaceOverview(s.now(), ns, s.overviewSources())
+		}
+		writeJSON(w, scoped, http.StatusOK)
+		return
+	}
 
+	overview := s.buildOverview()
+	s.markDegraded(w, overview)
 	writeJSON(w, overview, http.StatusOK)
 }
+
+func (s *Server) overviewSources() cluster.OverviewSources {
+	return cluster.OverviewSources{
+		Namespaces:        s.namespaces,
+		Nodes:             s.nodes,
+		Pods:              s.pods,
+		Deployments:       s.deployments,
+		Services:          s.services,
+		Events:            s.logs,
+		VisibleNamespaces: s.visibleNamespaces,
+	}
+}
+
+// buildOverview computes the live overview and flags it as degraded when
+// its health score falls below the configured threshold.
+func (s *Server) buildOverview() cluster.ClusterOverview {
+	overview := cluster.BuildOverview(s.now(), s.overviewSources())
+	overview.Degraded = overview.Health.Score < s.degradedThreshold
+	return overview
+}
+
+// markDegraded sets degradedHeader when overview is degraded. Only the
+// overview and health handlers call it, after building the overview from the
+// current stores, so the header always matches the body it comes with.
+func (s *Server) markDegraded(w http.ResponseWriter, overview cluster.ClusterOverview) {
+	if overview.Degraded {
+		w.Header().Set(degradedHeader, "true")
+	}
+}
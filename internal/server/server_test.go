@@ -1,17 +1,30 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"k8s_dashboard/internal/age"
 	"k8s_dashboard/internal/cluster"
+	"k8s_dashboard/internal/deploy"
+	"k8s_dashboard/internal/kubeconfig"
+	"k8s_dashboard/internal/logs"
+	"k8s_dashboard/internal/namespace"
+	"k8s_dashboard/internal/node"
+	"k8s_dashboard/internal/pod"
 )
 
 func TestHandleClusterOverview(t *testing.T) {
@@ -45,6 +58,210 @@ func TestHandleClusterOverview(t *testing.T) {
 	if payload.ResourceUsage.Memory.Timestamp != "2024-07-12T15:30:00Z" {
 		t.Errorf("unexpected timestamp %s", payload.ResourceUsage.Memory.Timestamp)
 	}
+
+	if want := len(srv.nodes.List(fixedTime)); payload.Info.NodeCount != want {
+		t.Errorf("expected node count %d from the node store, got %d", want, payload.Info.NodeCount)
+	}
+	if want := len(srv.namespaces.List(fixedTime)); payload.Info.NamespaceCount != want {
+		t.Errorf("expected namespace count %d from the namespace store, got %d", want, payload.Info.NamespaceCount)
+	}
+	if payload.Health.Status != cluster.StatusDegraded || payload.Health.Score <= 0 {
+		t.Errorf("unexpected health %+v", payload.Health)
+	}
+}
+
+func TestDegradedHeader(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	get := func(srv *Server, path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	srv := NewWithClock(func() time.Time { return fixedTime })
+	rr := get(srv, "/api/cluster/overview")
+	if rr.Header().Get("X-Cluster-Degraded") != "true" {
+		t.Fatal("expected seeded cluster to be flagged as degraded")
+	}
+	var overview cluster.ClusterOverview
+	if err := json.NewDecoder(rr.Body).Decode(&overview); err != nil {
+		t.Fatalf("decode overview: %v", err)
+	}
+	if !overview.Degraded {
+		t.Fatalf("expected degraded flag in overview, health %+v", overview.Health)
+	}
+	if get(srv, "/api/cluster/health").Header().Get("X-Cluster-Degraded") != "true" {
+		t.Fatal("expected degraded header on the health response")
+	}
+	for _, path := range []string{"/api/pods", "/healthz"} {
+		if get(srv, path).Header().Get("X-Cluster-Degraded") != "" {
+			t.Fatalf("%s: expected no degraded header outside the overview and health", path)
+		}
+	}
+
+	// Removing the pending pod lifts the seeded score from 70 to 75.
+	relaxed := NewWithClock(func() time.Time { return fixedTime }, WithDegradedThreshold(75))
+	if get(relaxed, "/api/cluster/overview").Header().Get("X-Cluster-Degraded") != "true" {
+		t.Fatal("expected header before clearing the pending pod")
+	}
+	delRR := httptest.NewRecorder()
+	relaxed.ServeHTTP(delRR, httptest.NewRequest(http.MethodDelete, "/api/pods/jobs-runner-bb7d67f4f6-123zt", nil))
+	if delRR.Code >= 300 {
+		t.Fatalf("delete pending pod: status %d", delRR.Code)
+	}
+	cleared := get(relaxed, "/api/cluster/overview")
+	if cleared.Header().Get("X-Cluster-Degraded") != "" {
+		t.Fatal("expected header to clear once the pending pod is gone")
+	}
+	var after cluster.ClusterOverview
+	if err := json.NewDecoder(cleared.Body).Decode(&after); err != nil {
+		t.Fatalf("decode overview: %v", err)
+	}
+	if after.Degraded {
+		t.Fatalf("expected degraded flag to clear, health %+v", after.Health)
+	}
+}
+
+func TestHandleClusterMetrics(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/cluster/metrics?window=15m&step=5m", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var history cluster.UsageHistory
+	if err := json.NewDecoder(rr.Body).Decode(&history); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(history.CPU) != 4 || history.CPU[0].Timestamp != "2024-07-12T15:30:00Z" || history.CPU[3].Timestamp != "2024-07-12T15:15:00Z" {
+		t.Fatalf("unexpected cpu series %+v", history.CPU)
+	}
+	overviewRR := httptest.NewRecorder()
+	srv.ServeHTTP(overviewRR, httptest.NewRequest(http.MethodGet, "/api/cluster/overview", nil))
+	var overview cluster.ClusterOverview
+	if err := json.NewDecoder(overviewRR.Body).Decode(&overview); err != nil {
+		t.Fatalf("decode overview: %v", err)
+	}
+	if history.CPU[0].Total != overview.ResourceUsage.CPU.Total || history.Memory[0].Total != overview.ResourceUsage.Memory.Total {
+		t.Fatalf("expected series totals to match the live overview, got %+v and %+v", history.CPU[0], overview.ResourceUsage)
+	}
+
+	defaultRR := httptest.NewRecorder()
+	srv.ServeHTTP(defaultRR, httptest.NewRequest(http.MethodGet, "/api/cluster/metrics", nil))
+	var defaults cluster.UsageHistory
+	if err := json.NewDecoder(defaultRR.Body).Decode(&defaults); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if defaults.Window != "1h0m0s" || len(defaults.Memory) != 13 {
+		t.Fatalf("unexpected default series window %s with %d samples", defaults.Window, len(defaults.Memory))
+	}
+
+	for _, query := range []string{"window=soon", "step=5", "window=5m&step=1h"} {
+		badRR := httptest.NewRecorder()
+		srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/cluster/metrics?"+query, nil))
+		if badRR.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for %s, got %d", query, badRR.Code)
+		}
+	}
+}
+
+func TestHandleClusterOverviewNamespace(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/cluster/overview?namespace=prod", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var prod cluster.NamespaceOverviewData
+	if err := json.NewDecoder(rr.Body).Decode(&prod); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if prod.Namespace != "prod" || prod.DeploymentCount != 1 || len(prod.RecentEvents) == 0 {
+		t.Fatalf("unexpected prod overview %+v", prod)
+	}
+
+	emptyRR := httptest.NewRecorder()
+	srv.ServeHTTP(emptyRR, httptest.NewRequest(http.MethodGet, "/api/cluster/overview?namespace=ghost", nil))
+	if emptyRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for unknown namespace, got %d", emptyRR.Code)
+	}
+	var empty map[string]any
+	if err := json.NewDecoder(emptyRR.Body).Decode(&empty); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if empty["deploymentCount"] != float64(0) || len(empty["recentEvents"].([]any)) != 0 {
+		t.Fatalf("expected zeroed overview, got %v", empty)
+	}
+}
+
+func TestHandleClusterCapacity(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cluster/capacity", nil)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var payload node.CapacitySummary
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.Capacity != 370 {
+		t.Fatalf("expected capacity 370, got %d", payload.Capacity)
+	}
+
+	if payload.Free != payload.Capacity-payload.Running {
+		t.Fatalf("expected free to equal capacity minus running, got %+v", payload)
+	}
+}
+
+func TestHandleClusterStatusBreakdown(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	cordonRR := httptest.NewRecorder()
+	srv.ServeHTTP(cordonRR, httptest.NewRequest(http.MethodPut, "/api/nodes/node-1/cordon", nil))
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/cluster/status-breakdown", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var breakdown map[string]map[string]int
+	if err := json.NewDecoder(rr.Body).Decode(&breakdown); err != nil {
+		t.Fatalf("decode status breakdown: %v", err)
+	}
+
+	if breakdown["pods"]["Running"] != 3 || breakdown["pods"]["Pending"] != 1 {
+		t.Fatalf("unexpected pod breakdown %v", breakdown["pods"])
+	}
+	if breakdown["deployments"]["Healthy"] != 1 || breakdown["deployments"]["Updating"] != 1 || breakdown["deployments"]["Down"] != 1 {
+		t.Fatalf("unexpected deployment breakdown %v", breakdown["deployments"])
+	}
+	if breakdown["nodes"]["Ready"] != 2 || breakdown["nodes"]["NotReady"] != 1 {
+		t.Fatalf("unexpected node breakdown %v", breakdown["nodes"])
+	}
+	if len(breakdown["services"]) == 0 {
+		t.Fatalf("expected service breakdown")
+	}
 }
 
 func TestHandleIndex(t *testing.T) {
@@ -98,326 +315,465 @@ func TestHandleNamespacesList(t *testing.T) {
 	}
 }
 
-func TestHandleNamespaceCreateAndDelete(t *testing.T) {
+func TestHandleNamespaceDeleteProtected(t *testing.T) {
 	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
 	srv := NewWithClock(func() time.Time {
 		return fixedTime
 	})
 
-	body := map[string]any{"name": "staging"}
-	raw, _ := json.Marshal(body)
-	req := httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw))
 	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/namespaces/kube-system", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rr.Code)
+	}
 
-	srv.ServeHTTP(rr, req)
+	okRR := httptest.NewRecorder()
+	srv.ServeHTTP(okRR, httptest.NewRequest(http.MethodDelete, "/api/namespaces/monitoring?gracePeriodSeconds=0", nil))
+	if okRR.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", okRR.Code)
+	}
+}
 
-	if rr.Code != http.StatusCreated {
-		t.Fatalf("expected status 201, got %d", rr.Code)
+func TestHandleNamespacesLabelSelector(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/namespaces?labelSelector=team%3Dsre", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
 
-	var created map[string]any
-	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
-		t.Fatalf("decode create response: %v", err)
+	var payload []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload) != 1 || payload[0]["name"] != "monitoring" {
+		t.Fatalf("expected only monitoring namespace, got %v", payload)
 	}
 
-	if created["name"] != "staging" {
-		t.Fatalf("unexpected namespace name %v", created["name"])
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/namespaces?labelSelector=team", nil))
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
 	}
+}
 
-	delReq := httptest.NewRequest(http.MethodDelete, "/api/namespaces/staging", nil)
-	delRR := httptest.NewRecorder()
-	srv.ServeHTTP(delRR, delReq)
+func TestHandleNamespaceCreateWithNameRule(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	rule := namespace.NameRule{MaxLength: 100, Pattern: `^[a-z0-9.-]+$`}
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	}, WithNamespaceNameRule(rule))
 
-	if delRR.Code != http.StatusNoContent {
-		t.Fatalf("expected status 204, got %d", delRR.Code)
+	name := "platform.team-" + strings.Repeat("x", 60)
+	raw, _ := json.Marshal(map[string]any{"name": name})
+	req := httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw))
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
 	}
+}
 
-	delReq2 := httptest.NewRequest(http.MethodDelete, "/api/namespaces/staging", nil)
-	delRR2 := httptest.NewRecorder()
-	srv.ServeHTTP(delRR2, delReq2)
+func TestWithNamespaceNameRulePanicsOnInvalidRule(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, namespace.ErrInvalidNameRule) {
+			t.Fatalf("expected panic with ErrInvalidNameRule, got %v", err)
+		}
+	}()
 
-	if delRR2.Code != http.StatusNotFound {
-		t.Fatalf("expected status 404, got %d", delRR2.Code)
-	}
+	NewWithClock(func() time.Time {
+		return fixedTime
+	}, WithNamespaceNameRule(namespace.NameRule{MaxLength: 10, Pattern: "(["}))
 }
 
-func TestHandleNodesEndpoints(t *testing.T) {
+func TestHandleNamespaceCreateIdempotencyKey(t *testing.T) {
 	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
 	srv := NewWithClock(func() time.Time {
 		return fixedTime
 	})
 
-	listReq := httptest.NewRequest(http.MethodGet, "/api/nodes", nil)
-	listRR := httptest.NewRecorder()
-	srv.ServeHTTP(listRR, listReq)
-
-	if listRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", listRR.Code)
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/namespaces", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		return rr
 	}
 
-	var nodes []map[string]any
-	if err := json.NewDecoder(listRR.Body).Decode(&nodes); err != nil {
-		t.Fatalf("decode nodes list: %v", err)
+	first := post("retry-1", `{"name":"staging"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", first.Code)
 	}
-
-	if len(nodes) == 0 {
-		t.Fatalf("expected nodes in list")
+	second := post("retry-1", `{"name":"staging"}`)
+	if second.Code != http.StatusCreated {
+		t.Fatalf("expected replayed status 201, got %d", second.Code)
 	}
-
-	detailReq := httptest.NewRequest(http.MethodGet, "/api/nodes/node-1", nil)
-	detailRR := httptest.NewRecorder()
-	srv.ServeHTTP(detailRR, detailReq)
-
-	if detailRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("expected identical responses, got %s and %s", first.Body.String(), second.Body.String())
 	}
-
-	var detail map[string]any
-	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
-		t.Fatalf("decode node detail: %v", err)
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected replayed response to be marked")
 	}
 
-	if detail["name"] != "node-1" {
-		t.Fatalf("expected node-1 detail, got %v", detail["name"])
+	created := 0
+	for _, entry := range srv.audit.List() {
+		if entry.Action == "create" && entry.Kind == "namespace" && entry.Name == "staging" {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected a single creation, got %d", created)
 	}
 
-	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/nodes/ghost", nil)
-	notFoundRR := httptest.NewRecorder()
-	srv.ServeHTTP(notFoundRR, notFoundReq)
-
-	if notFoundRR.Code != http.StatusNotFound {
-		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	if conflict := post("retry-1", `{"name":"qa"}`); conflict.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for reused key, got %d", conflict.Code)
+	}
+	if dup := post("", `{"name":"staging"}`); dup.Code != http.StatusConflict {
+		t.Fatalf("expected duplicate without key to hit the store, got %d", dup.Code)
 	}
 }
 
-func TestHandlePodsEndpoints(t *testing.T) {
+func TestIdempotencyKeyConcurrentRetries(t *testing.T) {
 	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
 	srv := NewWithClock(func() time.Time {
 		return fixedTime
 	})
 
-	listReq := httptest.NewRequest(http.MethodGet, "/api/pods", nil)
-	listRR := httptest.NewRecorder()
-	srv.ServeHTTP(listRR, listReq)
-
-	if listRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", listRR.Code)
-	}
-
-	var pods []map[string]any
-	if err := json.NewDecoder(listRR.Body).Decode(&pods); err != nil {
-		t.Fatalf("decode pods list: %v", err)
+	const retries = 8
+	codes := make([]int, retries)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/api/namespaces", strings.NewReader(`{"name":"staging"}`))
+			req.Header.Set("Idempotency-Key", "race-1")
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, req)
+			codes[i] = rr.Code
+		}(i)
 	}
+	wg.Wait()
 
-	if len(pods) == 0 {
-		t.Fatalf("expected pods in list")
+	for i, code := range codes {
+		if code != http.StatusCreated {
+			t.Fatalf("retry %d: expected status 201, got %d", i, code)
+		}
 	}
+}
 
-	detailReq := httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12", nil)
-	detailRR := httptest.NewRecorder()
-	srv.ServeHTTP(detailRR, detailReq)
+func TestIdempotencyKeyReleasedAfterPanic(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
 
-	if detailRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	post := func(create http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/namespaces", strings.NewReader(`{"name":"staging"}`))
+		req.Header.Set("Idempotency-Key", "panic-1")
+		rr := httptest.NewRecorder()
+		srv.idempotent(rr, req, create)
+		return rr
 	}
 
-	var detail map[string]any
-	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
-		t.Fatalf("decode pod detail: %v", err)
-	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the handler panic to propagate")
+			}
+		}()
+		post(func(http.ResponseWriter, *http.Request) { panic("boom") })
+	}()
 
-	if detail["name"] != "frontend-7d8fdc9f7c-abc12" {
-		t.Fatalf("unexpected pod name %v", detail["name"])
+	rr := post(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) })
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected the key to be released after a panic, got %d", rr.Code)
 	}
-
-	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/pods/missing", nil)
-	notFoundRR := httptest.NewRecorder()
-	srv.ServeHTTP(notFoundRR, notFoundReq)
-
-	if notFoundRR.Code != http.StatusNotFound {
-		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	if rr.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatal("expected the retry to run the handler, not replay")
 	}
 }
 
-func TestHandleServicesEndpoints(t *testing.T) {
+func TestHandleLogAppendIdempotencyKey(t *testing.T) {
 	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
 	srv := NewWithClock(func() time.Time {
 		return fixedTime
 	})
 
-	listReq := httptest.NewRequest(http.MethodGet, "/api/services", nil)
-	listRR := httptest.NewRecorder()
-	srv.ServeHTTP(listRR, listReq)
-
-	if listRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", listRR.Code)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/logs", strings.NewReader(`{"namespace":"prod","pod":"api-0","level":"info","message":"retried once"}`))
+		req.Header.Set("Idempotency-Key", "log-1")
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("attempt %d: expected status 201, got %d: %s", i, rr.Code, rr.Body.String())
+		}
 	}
 
-	var services []map[string]any
-	if err := json.NewDecoder(listRR.Body).Decode(&services); err != nil {
-		t.Fatalf("decode services list: %v", err)
+	stored := 0
+	for _, entry := range srv.logs.ListLogs(fixedTime, logs.LogFilter{Pod: "api-0"}) {
+		if entry.Message == "retried once" {
+			stored++
+		}
 	}
-
-	if len(services) == 0 {
-		t.Fatalf("expected services in list")
+	if stored != 1 {
+		t.Fatalf("expected a single stored entry, got %d", stored)
 	}
+}
 
-	detailReq := httptest.NewRequest(http.MethodGet, "/api/services/frontend", nil)
-	detailRR := httptest.NewRecorder()
-	srv.ServeHTTP(detailRR, detailReq)
+func TestHandleNamespaceOverview(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
 
-	if detailRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	raw, _ := json.Marshal(map[string]any{"name": "prod"})
+	createRR := httptest.NewRecorder()
+	srv.ServeHTTP(createRR, httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw)))
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", createRR.Code)
 	}
 
-	var detail map[string]any
-	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
-		t.Fatalf("decode service detail: %v", err)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/namespaces/prod/overview", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
 
-	if detail["name"] != "frontend" {
-		t.Fatalf("expected frontend detail, got %v", detail["name"])
+	var overview map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&overview); err != nil {
+		t.Fatalf("decode overview: %v", err)
+	}
+	if overview["namespace"] != "prod" {
+		t.Fatalf("unexpected namespace %v", overview["namespace"])
+	}
+	if meta := overview["metadata"].(map[string]any); meta["name"] != "prod" {
+		t.Fatalf("unexpected namespace metadata %v", meta)
+	}
+	if overview["deploymentCount"] != float64(1) {
+		t.Fatalf("expected cluster aggregate counts, got %v", overview["deploymentCount"])
+	}
+	var deployments float64
+	for _, n := range overview["deployments"].(map[string]any) {
+		deployments += n.(float64)
+	}
+	if deployments != 1 {
+		t.Fatalf("expected 1 prod deployment, got %v", overview["deployments"])
+	}
+	events := overview["recentEvents"].([]any)
+	if len(events) == 0 {
+		t.Fatal("expected recent prod events")
+	}
+	for _, ev := range events {
+		if ev.(map[string]any)["namespace"] != "prod" {
+			t.Fatalf("unexpected event outside prod: %v", ev)
+		}
 	}
 
-	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/services/ghost", nil)
-	notFoundRR := httptest.NewRecorder()
-	srv.ServeHTTP(notFoundRR, notFoundReq)
-
-	if notFoundRR.Code != http.StatusNotFound {
-		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/namespaces/ghost/overview", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
 	}
 }
 
-func TestHandleLogsAndEvents(t *testing.T) {
-	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+func TestHandleNamespaceCreateAndDelete(t *testing.T) {
+	current := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
 	srv := NewWithClock(func() time.Time {
-		return fixedTime
+		return current
 	})
 
-	metaReq := httptest.NewRequest(http.MethodGet, "/api/logs/meta", nil)
-	metaRR := httptest.NewRecorder()
-	srv.ServeHTTP(metaRR, metaReq)
+	body := map[string]any{"name": "staging"}
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw))
+	rr := httptest.NewRecorder()
 
-	if metaRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", metaRR.Code)
+	srv.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
 	}
 
-	var meta map[string]any
-	if err := json.NewDecoder(metaRR.Body).Decode(&meta); err != nil {
-		t.Fatalf("decode meta response: %v", err)
+	var created map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("decode create response: %v", err)
 	}
 
-	if _, ok := meta["namespaces"]; !ok {
-		t.Fatalf("expected namespaces in meta response")
+	if created["name"] != "staging" {
+		t.Fatalf("unexpected namespace name %v", created["name"])
 	}
 
-	logReq := httptest.NewRequest(http.MethodGet, "/api/logs/stream?namespace=prod&level=ERROR&limit=5", nil)
-	logRR := httptest.NewRecorder()
-	srv.ServeHTTP(logRR, logReq)
+	delReq := httptest.NewRequest(http.MethodDelete, "/api/namespaces/staging", nil)
+	delRR := httptest.NewRecorder()
+	srv.ServeHTTP(delRR, delReq)
 
-	if logRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", logRR.Code)
+	if delRR.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d", delRR.Code)
 	}
 
-	var logsPayload []map[string]any
-	if err := json.NewDecoder(logRR.Body).Decode(&logsPayload); err != nil {
-		t.Fatalf("decode logs response: %v", err)
+	var terminating map[string]any
+	if err := json.NewDecoder(delRR.Body).Decode(&terminating); err != nil {
+		t.Fatalf("decode delete response: %v", err)
 	}
 
-	if len(logsPayload) == 0 {
-		t.Fatalf("expected filtered logs")
+	if terminating["status"] != "Terminating" {
+		t.Fatalf("expected Terminating status, got %v", terminating["status"])
 	}
 
-	eventsReq := httptest.NewRequest(http.MethodGet, "/api/events", nil)
-	eventsRR := httptest.NewRecorder()
-	srv.ServeHTTP(eventsRR, eventsReq)
+	delReq2 := httptest.NewRequest(http.MethodDelete, "/api/namespaces/staging", nil)
+	delRR2 := httptest.NewRecorder()
+	srv.ServeHTTP(delRR2, delReq2)
 
-	if eventsRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", eventsRR.Code)
+	if delRR2.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202 while terminating, got %d", delRR2.Code)
 	}
 
-	var events []map[string]any
-	if err := json.NewDecoder(eventsRR.Body).Decode(&events); err != nil {
-		t.Fatalf("decode events response: %v", err)
-	}
+	current = current.Add(time.Duration(namespaceGraceSeconds) * time.Second)
 
-	if len(events) == 0 {
-		t.Fatalf("expected events from API")
+	delReq3 := httptest.NewRequest(http.MethodDelete, "/api/namespaces/staging", nil)
+	delRR3 := httptest.NewRecorder()
+	srv.ServeHTTP(delRR3, delReq3)
+
+	if delRR3.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 after grace, got %d", delRR3.Code)
 	}
 }
 
-func TestHandleClusterImport(t *testing.T) {
-	const kubeconfigYAML = `apiVersion: v1
-clusters:
-- name: test
-  cluster:
-    server: https://example.test
-contexts:
-- name: test-context
-  context:
-    cluster: test
-    user: test-user
-current-context: test-context
-`
-
+func TestHandleNamespaceDeleteImmediate(t *testing.T) {
 	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
-	srv := NewWithClock(func() time.Time { return fixedTime })
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", "config.yaml")
-	if err != nil {
-		t.Fatalf("create form file: %v", err)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/namespaces/monitoring?gracePeriodSeconds=0", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rr.Code)
 	}
-	if _, err := io.WriteString(part, kubeconfigYAML); err != nil {
-		t.Fatalf("write kubeconfig: %v", err)
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodDelete, "/api/namespaces/monitoring", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
 	}
-	writer.Close()
 
-	req := httptest.NewRequest(http.MethodPost, "/api/cluster/import", body)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodDelete, "/api/namespaces/default?gracePeriodSeconds=-1", nil))
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
+	}
+}
+
+func TestHandleNamespaceDetail(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
 	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/namespaces/kube-system", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
 
-	srv.ServeHTTP(rr, req)
+	var ns map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&ns); err != nil {
+		t.Fatalf("decode namespace: %v", err)
+	}
+	if ns["name"] != "kube-system" || ns["age"] != "3d0h" {
+		t.Fatalf("unexpected namespace detail %v", ns)
+	}
 
-	if rr.Code != http.StatusCreated {
-		t.Fatalf("expected 201, got %d", rr.Code)
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/namespaces/ghost", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
 	}
 
-	var summary map[string]any
-	if err := json.NewDecoder(rr.Body).Decode(&summary); err != nil {
-		t.Fatalf("decode summary: %v", err)
+	var errPayload map[string]string
+	if err := json.NewDecoder(missingRR.Body).Decode(&errPayload); err != nil || errPayload["error"] == "" {
+		t.Fatalf("expected error payload, got %v (%v)", errPayload, err)
 	}
+}
 
-	if summary["name"].(string) == "" {
-		t.Fatalf("expected summary name")
+func TestHandleNamespaceInheritedLabels(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"name":"sre-alerts","labels":{"namespace.kubernetes.io/parent":"monitoring"}}`)
+	createRR := httptest.NewRecorder()
+	srv.ServeHTTP(createRR, httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw)))
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", createRR.Code)
 	}
 
-	listReq := httptest.NewRequest(http.MethodGet, "/api/cluster/imports", nil)
-	listRR := httptest.NewRecorder()
-	srv.ServeHTTP(listRR, listReq)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/namespaces/sre-alerts?inherited=true", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
 
-	if listRR.Code != http.StatusOK {
-		t.Fatalf("expected 200, got %d", listRR.Code)
+	var ns map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&ns); err != nil {
+		t.Fatalf("decode namespace: %v", err)
 	}
 
-	var imports []map[string]any
-	if err := json.NewDecoder(listRR.Body).Decode(&imports); err != nil {
-		t.Fatalf("decode imports: %v", err)
+	labels := ns["labels"].(map[string]any)
+	if labels["team"] != "sre" {
+		t.Fatalf("expected inherited team label, got %v", labels)
 	}
+}
 
-	if len(imports) != 1 {
-		t.Fatalf("expected 1 import, got %d", len(imports))
+func TestHandleNamespaceUpdate(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	body := []byte(`{"labels":{"team":"observability"}}`)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/namespaces/monitoring", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var ns map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&ns); err != nil {
+		t.Fatalf("decode namespace: %v", err)
+	}
+	if ns["labels"].(map[string]any)["team"] != "observability" {
+		t.Fatalf("expected updated label, got %v", ns["labels"])
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodPut, "/api/namespaces/ghost", bytes.NewReader(body)))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodPut, "/api/namespaces/monitoring", bytes.NewReader([]byte(`{"labels":{"bad key":"x"}}`))))
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
 	}
 }
 
-func TestHandleDeploymentsEndpoints(t *testing.T) {
+func TestHandleNodesEndpoints(t *testing.T) {
 	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
 	srv := NewWithClock(func() time.Time {
 		return fixedTime
 	})
 
-	listReq := httptest.NewRequest(http.MethodGet, "/api/deployments", nil)
+	listReq := httptest.NewRequest(http.MethodGet, "/api/nodes", nil)
 	listRR := httptest.NewRecorder()
 	srv.ServeHTTP(listRR, listReq)
 
@@ -425,16 +781,16 @@ func TestHandleDeploymentsEndpoints(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", listRR.Code)
 	}
 
-	var deployments []map[string]any
-	if err := json.NewDecoder(listRR.Body).Decode(&deployments); err != nil {
-		t.Fatalf("decode deployments list: %v", err)
+	var nodes []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&nodes); err != nil {
+		t.Fatalf("decode nodes list: %v", err)
 	}
 
-	if len(deployments) == 0 {
-		t.Fatalf("expected deployments in list")
+	if len(nodes) == 0 {
+		t.Fatalf("expected nodes in list")
 	}
 
-	detailReq := httptest.NewRequest(http.MethodGet, "/api/deployments/frontend", nil)
+	detailReq := httptest.NewRequest(http.MethodGet, "/api/nodes/node-1", nil)
 	detailRR := httptest.NewRecorder()
 	srv.ServeHTTP(detailRR, detailReq)
 
@@ -444,45 +800,3298 @@ func TestHandleDeploymentsEndpoints(t *testing.T) {
 
 	var detail map[string]any
 	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
-		t.Fatalf("decode deployment detail: %v", err)
+		t.Fatalf("decode node detail: %v", err)
 	}
 
-	if detail["name"] != "frontend" {
-		t.Fatalf("unexpected deployment name %v", detail["name"])
+	if detail["name"] != "node-1" {
+		t.Fatalf("expected node-1 detail, got %v", detail["name"])
 	}
 
-	scaleBody := map[string]any{"replicas": 6}
-	payload, _ := json.Marshal(scaleBody)
-	scaleReq := httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", bytes.NewReader(payload))
-	scaleRR := httptest.NewRecorder()
-	srv.ServeHTTP(scaleRR, scaleReq)
+	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/nodes/ghost", nil)
+	notFoundRR := httptest.NewRecorder()
+	srv.ServeHTTP(notFoundRR, notFoundReq)
 
-	if scaleRR.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", scaleRR.Code)
+	if notFoundRR.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", notFoundRR.Code)
 	}
+}
 
-	var scaled map[string]any
-	if err := json.NewDecoder(scaleRR.Body).Decode(&scaled); err != nil {
-		t.Fatalf("decode scaled detail: %v", err)
+func TestHandleNodesFiltered(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/nodes?status=ready&role=worker", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
 
-	if scaled["desiredReplicas"].(float64) != 6 {
-		t.Fatalf("expected desired replicas 6, got %v", scaled["desiredReplicas"])
+	var nodes []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&nodes); err != nil {
+		t.Fatalf("decode nodes list: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0]["name"] != "node-2" {
+		t.Fatalf("expected only node-2, got %v", nodes)
 	}
+}
 
-	invalidReq := httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", bytes.NewReader([]byte(`{"replicas":-1}`)))
-	invalidRR := httptest.NewRecorder()
-	srv.ServeHTTP(invalidRR, invalidReq)
+func TestHandleNodesSorted(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/nodes?sort=cpu&order=desc", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var nodes []node.NodeSummary
+	if err := json.NewDecoder(rr.Body).Decode(&nodes); err != nil {
+		t.Fatalf("decode nodes list: %v", err)
+	}
+	for i := 1; i < len(nodes); i++ {
+		if nodes[i-1].CPU.Percentage < nodes[i].CPU.Percentage {
+			t.Fatalf("expected descending cpu order, got %v then %v", nodes[i-1].CPU.Percentage, nodes[i].CPU.Percentage)
+		}
+	}
 
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/nodes?sort=disk", nil))
 	if invalidRR.Code != http.StatusBadRequest {
 		t.Fatalf("expected status 400, got %d", invalidRR.Code)
 	}
+}
 
-	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/deployments/missing", nil)
-	notFoundRR := httptest.NewRecorder()
-	srv.ServeHTTP(notFoundRR, notFoundReq)
+func TestHandleNodeRoles(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
 
-	if notFoundRR.Code != http.StatusNotFound {
-		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/nodes/node-1", nil))
+	var detail node.NodeDetail
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode node detail: %v", err)
+	}
+	if detail.Roles[0] != "node-role.kubernetes.io/control-plane" {
+		t.Fatalf("expected canonical role, got %v", detail.Roles)
+	}
+
+	rawRR := httptest.NewRecorder()
+	srv.ServeHTTP(rawRR, httptest.NewRequest(http.MethodGet, "/api/nodes?rawRoles=true", nil))
+	var nodes []node.NodeSummary
+	if err := json.NewDecoder(rawRR.Body).Decode(&nodes); err != nil {
+		t.Fatalf("decode nodes list: %v", err)
+	}
+	if nodes[0].Name != "node-1" || nodes[0].Roles[0] != "control-plane" {
+		t.Fatalf("expected raw role, got %v", nodes[0].Roles)
+	}
+}
+
+func TestHandleNodeCordon(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/nodes/node-1/cordon", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var detail map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode node detail: %v", err)
+	}
+	if detail["status"] != "Ready,SchedulingDisabled" || detail["unschedulable"] != true {
+		t.Fatalf("unexpected cordoned node %v", detail)
+	}
+
+	uncordonRR := httptest.NewRecorder()
+	srv.ServeHTTP(uncordonRR, httptest.NewRequest(http.MethodPut, "/api/nodes/node-1/uncordon", nil))
+	if uncordonRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", uncordonRR.Code)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodPut, "/api/nodes/ghost/cordon", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleNodeDrain(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/nodes/node-2/drain", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var detail node.NodeDetail
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode node detail: %v", err)
+	}
+	if !detail.Unschedulable || detail.Pods.Running != 0 {
+		t.Fatalf("unexpected drained node %+v", detail.NodeSummary)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodPost, "/api/nodes/ghost/drain", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleNodeTaints(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/nodes/node-2/taints", strings.NewReader(`{"taints":["dedicated=gpu:NoSchedule"]}`))
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var detail node.NodeDetail
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode node detail: %v", err)
+	}
+	if len(detail.Taints) != 1 || detail.Taints[0] != "dedicated=gpu:NoSchedule" {
+		t.Fatalf("unexpected taints %v", detail.Taints)
+	}
+
+	duplicateReq := httptest.NewRequest(http.MethodPut, "/api/nodes/node-2/taints", strings.NewReader(`{"taints":["dedicated=gpu:NoSchedule","dedicated=cpu:NoExecute"]}`))
+	duplicateRR := httptest.NewRecorder()
+	srv.ServeHTTP(duplicateRR, duplicateReq)
+	if duplicateRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", duplicateRR.Code)
+	}
+	if !strings.Contains(duplicateRR.Body.String(), "duplicate key") {
+		t.Fatalf("expected descriptive duplicate error, got %s", duplicateRR.Body.String())
+	}
+
+	malformedReq := httptest.NewRequest(http.MethodPut, "/api/nodes/node-2/taints", strings.NewReader(`{"taints":["dedicated"]}`))
+	malformedRR := httptest.NewRecorder()
+	srv.ServeHTTP(malformedRR, malformedReq)
+	if malformedRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", malformedRR.Code)
+	}
+}
+
+func TestHandlePodsEndpoints(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	listReq := httptest.NewRequest(http.MethodGet, "/api/pods", nil)
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, listReq)
+
+	if listRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", listRR.Code)
+	}
+
+	var pods []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&pods); err != nil {
+		t.Fatalf("decode pods list: %v", err)
+	}
+
+	if len(pods) == 0 {
+		t.Fatalf("expected pods in list")
+	}
+
+	detailReq := httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12", nil)
+	detailRR := httptest.NewRecorder()
+	srv.ServeHTTP(detailRR, detailReq)
+
+	if detailRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	}
+
+	var detail map[string]any
+	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode pod detail: %v", err)
+	}
+
+	if detail["name"] != "frontend-7d8fdc9f7c-abc12" {
+		t.Fatalf("unexpected pod name %v", detail["name"])
+	}
+
+	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/pods/missing", nil)
+	notFoundRR := httptest.NewRecorder()
+	srv.ServeHTTP(notFoundRR, notFoundReq)
+
+	if notFoundRR.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	}
+}
+
+func TestHandlePodsFiltered(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods?namespace=default&status=running", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var pods []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&pods); err != nil {
+		t.Fatalf("decode pods list: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 running default pods, got %d", len(pods))
+	}
+	for _, p := range pods {
+		if p["namespace"] != "default" {
+			t.Fatalf("unexpected pod %v", p)
+		}
+	}
+}
+
+func TestHandlePodNamespacedLookup(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	duplicate := pod.Detail{Summary: pod.Summary{
+		Name:      "frontend-7d8fdc9f7c-abc12",
+		Namespace: "prod",
+		Status:    "Pending",
+	}}
+	if err := srv.pods.Add(duplicate, fixedTime); err != nil {
+		t.Fatalf("add duplicate pod: %v", err)
+	}
+
+	ambiguousRR := httptest.NewRecorder()
+	srv.ServeHTTP(ambiguousRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12", nil))
+	if ambiguousRR.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", ambiguousRR.Code)
+	}
+
+	for _, ns := range []string{"default", "prod"} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/"+ns+"/frontend-7d8fdc9f7c-abc12", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d", ns, rr.Code)
+		}
+		var detail map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+			t.Fatalf("decode pod detail: %v", err)
+		}
+		if detail["namespace"] != ns {
+			t.Fatalf("expected namespace %s, got %v", ns, detail["namespace"])
+		}
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/pods/batch/frontend-7d8fdc9f7c-abc12", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandlePodDelete(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/pods/frontend-7d8fdc9f7c-abc12", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rr.Code)
+	}
+
+	nsRR := httptest.NewRecorder()
+	srv.ServeHTTP(nsRR, httptest.NewRequest(http.MethodDelete, "/api/pods/batch/jobs-runner-bb7d67f4f6-123zt", nil))
+	if nsRR.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", nsRR.Code)
+	}
+
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, httptest.NewRequest(http.MethodGet, "/api/pods", nil))
+	var pods []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&pods); err != nil {
+		t.Fatalf("decode pods list: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods after delete, got %d", len(pods))
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodDelete, "/api/pods/frontend-7d8fdc9f7c-abc12", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandlePodContainerLogs(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/logs?container=sidecar", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var logs pod.ContainerLogs
+	if err := json.NewDecoder(rr.Body).Decode(&logs); err != nil {
+		t.Fatalf("decode pod logs: %v", err)
+	}
+	if logs.Container != "sidecar" || len(logs.Lines) == 0 || logs.Lines[0].Container != "sidecar" {
+		t.Fatalf("unexpected sidecar logs %+v", logs)
+	}
+
+	defaultRR := httptest.NewRecorder()
+	srv.ServeHTTP(defaultRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/logs", nil))
+	if got := defaultRR.Header().Get("X-Container"); got != "frontend" {
+		t.Fatalf("expected default container frontend, got %q", got)
+	}
+
+	unknownRR := httptest.NewRecorder()
+	srv.ServeHTTP(unknownRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/logs?container=proxy", nil))
+	if unknownRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", unknownRR.Code)
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(unknownRR.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode error payload: %v", err)
+	}
+	if containers, ok := payload["containers"].([]any); !ok || len(containers) != 2 {
+		t.Fatalf("expected valid container names, got %v", payload["containers"])
+	}
+}
+
+func TestHandlePodLogStreamBacklog(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/logs/stream?follow=false", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+	if got := strings.Count(rr.Body.String(), "event: log\n"); got != 3 {
+		t.Fatalf("expected 3 backlog events, got %d:\n%s", got, rr.Body.String())
+	}
+}
+
+func TestHandlePodLogStreamFollow(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)}
+	srv := NewWithClock(clock.Now, WithSSEKeepalive(0))
+	srv.ssePoll = 5 * time.Millisecond
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/pods/backend-76c4d5f6d6-xyz89/logs/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	readData := func() pod.LogLine {
+		t.Helper()
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read stream: %v", err)
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				var out pod.LogLine
+				if err := json.Unmarshal([]byte(data), &out); err != nil {
+					t.Fatalf("decode log line: %v", err)
+				}
+				return out
+			}
+		}
+	}
+
+	if first := readData(); first.Message != "[INFO] 09:10:04 processed job 2384" {
+		t.Fatalf("unexpected first line %+v", first)
+	}
+
+	clock.Advance(3 * time.Second)
+	readData()
+	readData()
+	if synthetic := readData(); !strings.Contains(synthetic.Message, "synthetic log line 1") {
+		t.Fatalf("expected synthetic line after backlog, got %+v", synthetic)
+	}
+}
+
+func TestHandlePodMetrics(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var metrics map[string]map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&metrics); err != nil {
+		t.Fatalf("decode pod metrics: %v", err)
+	}
+	if metrics["cpu"]["unit"] != "m" || metrics["memory"]["limit"] != float64(512) {
+		t.Fatalf("unexpected metrics %v", metrics)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/pods/ghost/metrics", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandlePodsPagination(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods?limit=2&offset=2", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if rr.Header().Get("X-Total-Count") != "4" || rr.Header().Get("X-Limit") != "2" || rr.Header().Get("X-Offset") != "2" {
+		t.Fatalf("unexpected pagination headers %v", rr.Header())
+	}
+
+	var pods []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&pods); err != nil {
+		t.Fatalf("decode pods list: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(pods))
+	}
+
+	cappedRR := httptest.NewRecorder()
+	srv.ServeHTTP(cappedRR, httptest.NewRequest(http.MethodGet, "/api/pods?limit=1000", nil))
+	if cappedRR.Header().Get("X-Limit") != "200" {
+		t.Fatalf("expected limit capped at 200, got %s", cappedRR.Header().Get("X-Limit"))
+	}
+
+	for query, param := range map[string]string{"limit=-1": "limit", "offset=abc": "offset", "offset=-1&limit=x": "limit"} {
+		invalidRR := httptest.NewRecorder()
+		srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/pods?"+query, nil))
+		if invalidRR.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for %s, got %d", query, invalidRR.Code)
+		}
+		if !strings.Contains(invalidRR.Body.String(), param+" 参数无效") {
+			t.Fatalf("expected %s to be reported for %s, got %s", param, query, invalidRR.Body.String())
+		}
+	}
+}
+
+func TestSummaryAgeSecondsMatchesAge(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	for _, path := range []string{"/api/nodes", "/api/pods", "/api/services", "/api/deployments", "/api/namespaces"} {
+		t.Run(path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rr.Code)
+			}
+			var items []map[string]any
+			if err := json.NewDecoder(rr.Body).Decode(&items); err != nil {
+				t.Fatalf("decode summaries: %v", err)
+			}
+			if len(items) == 0 {
+				t.Fatal("expected seeded summaries")
+			}
+			for _, item := range items {
+				seconds, ok := item["ageSeconds"].(float64)
+				if !ok {
+					t.Fatalf("expected ageSeconds on %v", item["name"])
+				}
+				if want := age.Format(time.Duration(seconds) * time.Second); item["age"] != want {
+					t.Fatalf("%v: age %v does not match ageSeconds %v (%s)", item["name"], item["age"], seconds, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlePodsSortByAge(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods?sort=age", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var pods []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&pods); err != nil {
+		t.Fatalf("decode pods list: %v", err)
+	}
+	for i := 1; i < len(pods); i++ {
+		if pods[i-1]["ageSeconds"].(float64) < pods[i]["ageSeconds"].(float64) {
+			t.Fatalf("expected oldest pods first, got %v before %v", pods[i-1]["name"], pods[i]["name"])
+		}
+	}
+
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/pods?sort=restarts", nil))
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown sort, got %d", invalidRR.Code)
+	}
+}
+
+func TestHandlePodNames(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/names?namespace=default", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var names []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&names); err != nil {
+		t.Fatalf("decode pod names: %v", err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected 2 default pods, got %d", len(names))
+	}
+	for _, item := range names {
+		if len(item) != 2 || item["name"] == nil || item["namespace"] != "default" {
+			t.Fatalf("expected only name and namespace keys, got %v", item)
+		}
+	}
+}
+
+func TestHandlePodsGrouped(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/grouped", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var grouped map[string][]pod.Summary
+	if err := json.NewDecoder(rr.Body).Decode(&grouped); err != nil {
+		t.Fatalf("decode grouped pods: %v", err)
+	}
+	if len(grouped["default/deployment/frontend"]) != 2 || len(grouped["prod/deployment/backend"]) != 1 {
+		t.Fatalf("unexpected groups %v", grouped)
+	}
+}
+
+func TestHandlePodWatch(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/pods?watch=true&namespace=prod", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open watch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	var initial pod.WatchEvent
+	if err := dec.Decode(&initial); err != nil {
+		t.Fatalf("decode initial event: %v", err)
+	}
+	if initial.Type != pod.Added || initial.Object.Namespace != "prod" {
+		t.Fatalf("unexpected initial event %+v", initial)
+	}
+
+	go func() {
+		_ = srv.pods.Add(pod.Detail{Summary: pod.Summary{Name: "ignored-1", Namespace: "batch"}}, fixedTime)
+		_ = srv.pods.Add(pod.Detail{Summary: pod.Summary{Name: "api-2", Namespace: "prod", Status: "Pending"}}, fixedTime)
+	}()
+
+	var added pod.WatchEvent
+	if err := dec.Decode(&added); err != nil {
+		t.Fatalf("decode added event: %v", err)
+	}
+	if added.Type != pod.Added || added.Object.Name != "api-2" {
+		t.Fatalf("expected ADDED api-2, got %+v", added)
+	}
+}
+
+func TestHandlePodBatchGet(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	body := []byte(`{"names":["frontend-7d8fdc9f7c-abc12","ghost","backend-76c4d5f6d6-xyz89"]}`)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/pods/batch-get", bytes.NewReader(body)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var results []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&results); err != nil {
+		t.Fatalf("decode batch response: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	expected := []bool{true, false, true}
+	for i, result := range results {
+		if result["found"] != expected[i] {
+			t.Fatalf("unexpected found flag for %v: %v", result["name"], result["found"])
+		}
+		if _, ok := result["pod"]; ok != expected[i] {
+			t.Fatalf("unexpected pod payload presence for %v", result["name"])
+		}
+	}
+
+	duplicate := pod.Detail{Summary: pod.Summary{Name: "frontend-7d8fdc9f7c-abc12", Namespace: "prod", Status: "Pending"}}
+	if err := srv.pods.Add(duplicate, fixedTime); err != nil {
+		t.Fatalf("add duplicate pod: %v", err)
+	}
+	dupRR := httptest.NewRecorder()
+	srv.ServeHTTP(dupRR, httptest.NewRequest(http.MethodPost, "/api/pods/batch-get", bytes.NewReader([]byte(`{"names":["frontend-7d8fdc9f7c-abc12","ghost"]}`))))
+	var dupResults []map[string]any
+	if err := json.NewDecoder(dupRR.Body).Decode(&dupResults); err != nil {
+		t.Fatalf("decode batch response: %v", err)
+	}
+	if len(dupResults) != 2 || dupResults[0]["found"] != false || dupResults[0]["ambiguous"] != true {
+		t.Fatalf("expected the duplicated name to be reported as ambiguous, got %v", dupResults)
+	}
+	if _, ok := dupResults[1]["ambiguous"]; ok {
+		t.Fatalf("expected a plain miss for ghost, got %v", dupResults[1])
+	}
+}
+
+func TestHandlePodConnectivity(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/jobs-runner-bb7d67f4f6-123zt/connectivity?target=frontend-7d8fdc9f7c-abc12", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var result map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("decode connectivity: %v", err)
+	}
+	if result["reachable"] != false || result["reason"] != "NetworkPolicy denies batch->default" {
+		t.Fatalf("unexpected connectivity result %v", result)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/connectivity", nil))
+	if missingRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleServicesEndpoints(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	listReq := httptest.NewRequest(http.MethodGet, "/api/services", nil)
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, listReq)
+
+	if listRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", listRR.Code)
+	}
+
+	var services []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&services); err != nil {
+		t.Fatalf("decode services list: %v", err)
+	}
+
+	if len(services) == 0 {
+		t.Fatalf("expected services in list")
+	}
+
+	detailReq := httptest.NewRequest(http.MethodGet, "/api/services/frontend", nil)
+	detailRR := httptest.NewRecorder()
+	srv.ServeHTTP(detailRR, detailReq)
+
+	if detailRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	}
+
+	var detail map[string]any
+	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode service detail: %v", err)
+	}
+
+	if detail["name"] != "frontend" {
+		t.Fatalf("expected frontend detail, got %v", detail["name"])
+	}
+
+	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/services/ghost", nil)
+	notFoundRR := httptest.NewRecorder()
+	srv.ServeHTTP(notFoundRR, notFoundReq)
+
+	if notFoundRR.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	}
+}
+
+func TestHandleServicesPortFilter(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/services?port=443", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var services []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&services); err != nil {
+		t.Fatalf("decode services list: %v", err)
+	}
+	if len(services) != 1 || services[0]["name"] != "edge-gateway" {
+		t.Fatalf("expected only edge-gateway, got %v", services)
+	}
+
+	for query, param := range map[string]string{"targetPort=http": "targetPort", "targetPort=0&port=70000": "port"} {
+		invalidRR := httptest.NewRecorder()
+		srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/services?"+query, nil))
+		if invalidRR.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for %s, got %d", query, invalidRR.Code)
+		}
+		if !strings.Contains(invalidRR.Body.String(), `"`+param+" 参数无效") {
+			t.Fatalf("expected %s to be reported for %s, got %s", param, query, invalidRR.Body.String())
+		}
+	}
+}
+
+func TestHandleLogsAndEvents(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	metaReq := httptest.NewRequest(http.MethodGet, "/api/logs/meta", nil)
+	metaRR := httptest.NewRecorder()
+	srv.ServeHTTP(metaRR, metaReq)
+
+	if metaRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", metaRR.Code)
+	}
+
+	var meta map[string]any
+	if err := json.NewDecoder(metaRR.Body).Decode(&meta); err != nil {
+		t.Fatalf("decode meta response: %v", err)
+	}
+
+	if _, ok := meta["namespaces"]; !ok {
+		t.Fatalf("expected namespaces in meta response")
+	}
+
+	logReq := httptest.NewRequest(http.MethodGet, "/api/logs/stream?namespace=prod&level=ERROR&limit=5", nil)
+	logRR := httptest.NewRecorder()
+	srv.ServeHTTP(logRR, logReq)
+
+	if logRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", logRR.Code)
+	}
+
+	var logsPayload []map[string]any
+	if err := json.NewDecoder(logRR.Body).Decode(&logsPayload); err != nil {
+		t.Fatalf("decode logs response: %v", err)
+	}
+
+	if len(logsPayload) == 0 {
+		t.Fatalf("expected filtered logs")
+	}
+
+	eventsReq := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	eventsRR := httptest.NewRecorder()
+	srv.ServeHTTP(eventsRR, eventsReq)
+
+	if eventsRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", eventsRR.Code)
+	}
+
+	var events []map[string]any
+	if err := json.NewDecoder(eventsRR.Body).Decode(&events); err != nil {
+		t.Fatalf("decode events response: %v", err)
+	}
+
+	if len(events) == 0 {
+		t.Fatalf("expected events from API")
+	}
+}
+
+func TestHandleLogStreamDownload(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/stream?pod=backend-76c4d5f6d6-xyz89&download=true", nil)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+
+	if cd := rr.Header().Get("Content-Disposition"); cd != `attachment; filename="backend-76c4d5f6d6-xyz89.log"` {
+		t.Fatalf("unexpected content disposition: %s", cd)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "backend-76c4d5f6d6-xyz89") {
+			t.Fatalf("unexpected log line: %s", line)
+		}
+	}
+}
+
+func TestHandleLogStreamExcludeLevel(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/logs/stream?excludeLevel=INFO", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var entries []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode logs response: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatalf("expected WARN/ERROR logs")
+	}
+	for _, entry := range entries {
+		if entry["level"] == "INFO" {
+			t.Fatalf("unexpected INFO entry: %v", entry)
+		}
+	}
+
+	conflictRR := httptest.NewRecorder()
+	srv.ServeHTTP(conflictRR, httptest.NewRequest(http.MethodGet, "/api/logs/stream?level=ERROR&excludeLevel=INFO", nil))
+	if conflictRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", conflictRR.Code)
+	}
+}
+
+func TestHandleLogStreamLevelList(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/logs/stream?level=ERROR,,warn&limit=50", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var entries []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode logs response: %v", err)
+	}
+	seen := map[any]bool{}
+	for _, entry := range entries {
+		seen[entry["level"]] = true
+	}
+	if seen["INFO"] || !seen["ERROR"] || !seen["WARN"] {
+		t.Fatalf("expected only ERROR and WARN entries, got levels %v", seen)
+	}
+
+	orderRR := httptest.NewRecorder()
+	srv.ServeHTTP(orderRR, httptest.NewRequest(http.MethodGet, "/api/logs/stream?order=sideways", nil))
+	if orderRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for invalid order, got %d", orderRR.Code)
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/logs/stream?level=ERROR,FATAL", nil))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", badRR.Code)
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(badRR.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if levels, ok := payload["levels"].([]any); !ok || len(levels) != 3 {
+		t.Fatalf("expected valid levels in error, got %v", payload)
+	}
+}
+
+func TestHandleEventsGroupedByObject(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	srv.logs.AppendEvent(logs.Event{
+		Namespace: "prod",
+		Kind:      "Pod",
+		Name:      "edge-gateway-7d8fdc9f7c-9012a",
+		Type:      "Normal",
+		Reason:    "Scheduled",
+		Message:   "Successfully assigned prod/edge-gateway-7d8fdc9f7c-9012a to node-1",
+		Timestamp: fixedTime.Format(time.RFC3339),
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/events?groupBy=object", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var grouped map[string][]map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&grouped); err != nil {
+		t.Fatalf("decode grouped events: %v", err)
+	}
+
+	gateway := grouped["pod/edge-gateway-7d8fdc9f7c-9012a"]
+	if len(gateway) != 2 {
+		t.Fatalf("expected 2 edge-gateway events, got %v", gateway)
+	}
+	if gateway[0]["reason"] != "Scheduled" || gateway[1]["reason"] != "FailedScheduling" {
+		t.Fatalf("expected newest event first, got %v", gateway)
+	}
+}
+
+func TestHandleClusterImport(t *testing.T) {
+	const kubeconfigYAML = `apiVersion: v1
+clusters:
+- name: test
+  cluster:
+    server: https://example.test
+contexts:
+- name: test-context
+  context:
+    cluster: test
+    user: test-user
+current-context: test-context
+`
+
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "config.yaml")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := io.WriteString(part, kubeconfigYAML); err != nil {
+		t.Fatalf("write kubeconfig: %v", err)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cluster/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+
+	srv.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+
+	var summary map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&summary); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+
+	if summary["name"].(string) == "" {
+		t.Fatalf("expected summary name")
+	}
+
+	listReq := httptest.NewRequest(http.MethodGet, "/api/cluster/imports", nil)
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, listReq)
+
+	if listRR.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", listRR.Code)
+	}
+
+	var imports []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&imports); err != nil {
+		t.Fatalf("decode imports: %v", err)
+	}
+
+	if len(imports) != 1 {
+		t.Fatalf("expected 1 import, got %d", len(imports))
+	}
+}
+
+func importKubeconfig(t *testing.T, srv *Server, filename, content string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := io.WriteString(part, content); err != nil {
+		t.Fatalf("write kubeconfig: %v", err)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cluster/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandleClusterImportMultipleFiles(t *testing.T) {
+	const kubeconfigYAML = `apiVersion: v1
+clusters:
+- name: %[1]s
+  cluster:
+    server: %[2]s
+contexts:
+- name: %[1]s-admin
+  context:
+    cluster: %[1]s
+    user: admin
+`
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	files := []struct{ name, content string }{
+		{"first.yaml", fmt.Sprintf(kubeconfigYAML, "shared", "https://one.example.test")},
+		{"second.yaml", fmt.Sprintf(kubeconfigYAML, "shared", "https://two.example.test") +
+			"- name: qa-admin\n  context:\n    cluster: shared\n    user: admin\n"},
+	}
+	for _, f := range files {
+		part, err := writer.CreateFormFile("file", f.name)
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		io.WriteString(part, f.content)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cluster/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var created map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("decode import response: %v", err)
+	}
+	clusters := created["clusters"].([]any)
+	if len(clusters) != 1 || clusters[0].(map[string]any)["server"] != "https://one.example.test" {
+		t.Fatalf("expected first cluster definition to win, got %v", clusters)
+	}
+	if contexts := created["contexts"].([]any); len(contexts) != 2 {
+		t.Fatalf("expected contexts from both files, got %v", contexts)
+	}
+	warnings, _ := created["warnings"].([]any)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].(string), "shared") {
+		t.Fatalf("expected a conflict warning for shared, got %v", created["warnings"])
+	}
+
+	singleRR := importKubeconfig(t, srv, "solo.yaml", fmt.Sprintf(kubeconfigYAML, "solo", "https://solo.example.test"))
+	if strings.Contains(singleRR.Body.String(), "warnings") {
+		t.Fatalf("expected no warnings for a single file, got %s", singleRR.Body.String())
+	}
+}
+
+func TestHandleClusterImportMerge(t *testing.T) {
+	const baseYAML = `apiVersion: v1
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.test
+contexts:
+- name: staging-admin
+  context:
+    cluster: staging
+    user: admin
+current-context: staging-admin
+`
+	const extraYAML = `apiVersion: v1
+clusters:
+- name: qa
+  cluster:
+    server: https://qa.example.test
+contexts:
+- name: qa-admin
+  context:
+    cluster: qa
+    user: admin
+`
+
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	if rr := importKubeconfig(t, srv, "staging.yaml", baseYAML); rr.Code != http.StatusCreated {
+		t.Fatalf("import: expected 201, got %d", rr.Code)
+	}
+
+	patch := func(name string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "qa.yaml")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		if _, err := io.WriteString(part, extraYAML); err != nil {
+			t.Fatalf("write kubeconfig: %v", err)
+		}
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/cluster/imports/"+name, body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := patch("staging-admin")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var merged map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&merged); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+	contexts := merged["contexts"].([]any)
+	if len(contexts) != 2 {
+		t.Fatalf("expected 2 contexts, got %v", contexts)
+	}
+	names := []string{contexts[0].(map[string]any)["name"].(string), contexts[1].(map[string]any)["name"].(string)}
+	if names[0] != "staging-admin" || names[1] != "qa-admin" {
+		t.Fatalf("unexpected merged contexts: %v", names)
+	}
+	if merged["currentContext"] != "staging-admin" {
+		t.Fatalf("expected current context to be kept, got %v", merged["currentContext"])
+	}
+
+	if missing := patch("ghost"); missing.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", missing.Code)
+	}
+}
+
+func TestHandleClusterImportDownload(t *testing.T) {
+	const kubeconfigYAML = `apiVersion: v1
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.test
+contexts:
+- name: staging-admin
+  context:
+    cluster: staging
+    user: admin
+current-context: staging-admin
+`
+
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	if rr := importKubeconfig(t, srv, "staging.yaml", kubeconfigYAML); rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/cluster/imports/staging-admin/download", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	if cd := rr.Header().Get("Content-Disposition"); cd != `attachment; filename="staging-admin.yaml"` {
+		t.Fatalf("unexpected content disposition: %s", cd)
+	}
+
+	summary, err := kubeconfig.Parse(rr.Body, fixedTime)
+	if err != nil {
+		t.Fatalf("reparse download: %v", err)
+	}
+
+	if summary.Clusters[0].Name != "staging" || summary.Contexts[0].Name != "staging-admin" {
+		t.Fatalf("round trip mismatch: %+v", summary)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/cluster/imports/ghost/download", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentsEndpoints(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	listReq := httptest.NewRequest(http.MethodGet, "/api/deployments", nil)
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, listReq)
+
+	if listRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", listRR.Code)
+	}
+
+	var deployments []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&deployments); err != nil {
+		t.Fatalf("decode deployments list: %v", err)
+	}
+
+	if len(deployments) == 0 {
+		t.Fatalf("expected deployments in list")
+	}
+
+	detailReq := httptest.NewRequest(http.MethodGet, "/api/deployments/frontend", nil)
+	detailRR := httptest.NewRecorder()
+	srv.ServeHTTP(detailRR, detailReq)
+
+	if detailRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	}
+
+	var detail map[string]any
+	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment detail: %v", err)
+	}
+
+	if detail["name"] != "frontend" {
+		t.Fatalf("unexpected deployment name %v", detail["name"])
+	}
+
+	scaleBody := map[string]any{"replicas": 6}
+	payload, _ := json.Marshal(scaleBody)
+	scaleReq := httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", bytes.NewReader(payload))
+	scaleRR := httptest.NewRecorder()
+	srv.ServeHTTP(scaleRR, scaleReq)
+
+	if scaleRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", scaleRR.Code)
+	}
+
+	var scaled map[string]any
+	if err := json.NewDecoder(scaleRR.Body).Decode(&scaled); err != nil {
+		t.Fatalf("decode scaled detail: %v", err)
+	}
+
+	if scaled["desiredReplicas"].(float64) != 6 {
+		t.Fatalf("expected desired replicas 6, got %v", scaled["desiredReplicas"])
+	}
+
+	invalidReq := httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", bytes.NewReader([]byte(`{"replicas":-1}`)))
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, invalidReq)
+
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
+	}
+
+	notFoundReq := httptest.NewRequest(http.MethodGet, "/api/deployments/missing", nil)
+	notFoundRR := httptest.NewRecorder()
+	srv.ServeHTTP(notFoundRR, notFoundReq)
+
+	if notFoundRR.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", notFoundRR.Code)
+	}
+}
+
+func TestHandleDetailTimezone(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend?tz=Asia/Shanghai", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var detail map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment detail: %v", err)
+	}
+	if detail["lastUpdated"] != "2024-07-12T15:00:00Z" {
+		t.Fatalf("expected UTC lastUpdated, got %v", detail["lastUpdated"])
+	}
+	if detail["lastUpdatedLocal"] != "2024-07-12T23:00:00+08:00" {
+		t.Fatalf("expected +08:00 lastUpdatedLocal, got %v", detail["lastUpdatedLocal"])
+	}
+
+	nsRR := httptest.NewRecorder()
+	srv.ServeHTTP(nsRR, httptest.NewRequest(http.MethodGet, "/api/namespaces/default?tz=Asia/Shanghai", nil))
+	var ns map[string]any
+	if err := json.NewDecoder(nsRR.Body).Decode(&ns); err != nil {
+		t.Fatalf("decode namespace: %v", err)
+	}
+	if local, _ := ns["createdAtLocal"].(string); !strings.HasSuffix(local, "+08:00") {
+		t.Fatalf("expected +08:00 createdAtLocal, got %v", ns["createdAtLocal"])
+	}
+
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/services/frontend?tz=Mars/Olympus", nil))
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
+	}
+}
+
+func TestHandleDeploymentsHealthFilter(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments?health=unhealthy", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var deployments []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&deployments); err != nil {
+		t.Fatalf("decode deployments list: %v", err)
+	}
+
+	names := make(map[any]bool)
+	for _, d := range deployments {
+		names[d["name"]] = true
+	}
+	if len(deployments) != 2 || !names["backend"] || !names["batch-jobs"] || names["frontend"] {
+		t.Fatalf("expected backend and batch-jobs only, got %v", deployments)
+	}
+
+	healthyRR := httptest.NewRecorder()
+	srv.ServeHTTP(healthyRR, httptest.NewRequest(http.MethodGet, "/api/deployments?health=healthy", nil))
+	var healthy []map[string]any
+	if err := json.NewDecoder(healthyRR.Body).Decode(&healthy); err != nil {
+		t.Fatalf("decode healthy deployments: %v", err)
+	}
+	if len(healthy) != 1 || healthy[0]["name"] != "frontend" {
+		t.Fatalf("expected frontend only, got %v", healthy)
+	}
+
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, httptest.NewRequest(http.MethodGet, "/api/deployments?health=maybe", nil))
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
+	}
+}
+
+func TestHandleDeploymentScaleDryRun(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	cases := []struct {
+		replicas int
+		valid    bool
+	}{
+		{replicas: 200, valid: true},
+		{replicas: 201, valid: false},
+	}
+
+	for _, tc := range cases {
+		body, _ := json.Marshal(map[string]any{"replicas": tc.replicas})
+		req := httptest.NewRequest(http.MethodPost, "/api/deployments/frontend/scale?dryRun=true", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %d replicas, got %d", tc.replicas, rr.Code)
+		}
+
+		var preview map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&preview); err != nil {
+			t.Fatalf("decode preview: %v", err)
+		}
+
+		if preview["valid"] != tc.valid {
+			t.Fatalf("expected valid=%v for %d replicas, got %v", tc.valid, tc.replicas, preview["valid"])
+		}
+	}
+
+	detailRR := httptest.NewRecorder()
+	srv.ServeHTTP(detailRR, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend", nil))
+
+	var detail map[string]any
+	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment detail: %v", err)
+	}
+
+	if detail["desiredReplicas"].(float64) != 4 {
+		t.Fatalf("expected dry run to leave replicas untouched, got %v", detail["desiredReplicas"])
+	}
+}
+
+func TestHandleDeploymentRollingUpdate(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	detailRR := httptest.NewRecorder()
+	srv.ServeHTTP(detailRR, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend", nil))
+	if detailRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", detailRR.Code)
+	}
+
+	var detail map[string]any
+	if err := json.NewDecoder(detailRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment detail: %v", err)
+	}
+	rolling, ok := detail["rollingUpdate"].(map[string]any)
+	if !ok || rolling["maxSurge"] != "25%" {
+		t.Fatalf("expected maxSurge 25%%, got %v", detail["rollingUpdate"])
+	}
+
+	invalidReq := httptest.NewRequest(http.MethodPatch, "/api/deployments/frontend", strings.NewReader(`{"rollingUpdate":{"maxSurge":"150%"}}`))
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, invalidReq)
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
+	}
+
+	patchReq := httptest.NewRequest(http.MethodPatch, "/api/deployments/frontend", strings.NewReader(`{"rollingUpdate":{"maxUnavailable":"1"}}`))
+	patchRR := httptest.NewRecorder()
+	srv.ServeHTTP(patchRR, patchReq)
+	if patchRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", patchRR.Code)
+	}
+}
+
+func TestHandleDeploymentStatus(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend/status", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var status map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&status); err != nil {
+		t.Fatalf("decode deployment status: %v", err)
+	}
+	if _, ok := status["labels"]; ok {
+		t.Fatalf("expected status to omit labels")
+	}
+	if _, ok := status["selector"]; ok {
+		t.Fatalf("expected status to omit selector")
+	}
+	if conditions, ok := status["conditions"].([]any); !ok || len(conditions) == 0 {
+		t.Fatalf("expected conditions in status, got %v", status["conditions"])
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/missing/status", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDescribe(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/describe?kind=pod&name=frontend-7d8fdc9f7c-def34", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected text/plain, got %s", ct)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{"Name:", "Status:", "Containers:", "frontend:", "sidecar:", "Image:"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in description:\n%s", want, body)
+		}
+	}
+
+	for _, kind := range []string{"node", "service", "deployment"} {
+		name := map[string]string{"node": "node-1", "service": "frontend", "deployment": "frontend"}[kind]
+		kindRR := httptest.NewRecorder()
+		srv.ServeHTTP(kindRR, httptest.NewRequest(http.MethodGet, "/api/describe?kind="+kind+"&name="+name, nil))
+		if kindRR.Code != http.StatusOK {
+			t.Fatalf("expected status 200 describing %s, got %d", kind, kindRR.Code)
+		}
+	}
+
+	unknownKindRR := httptest.NewRecorder()
+	srv.ServeHTTP(unknownKindRR, httptest.NewRequest(http.MethodGet, "/api/describe?kind=secret&name=x", nil))
+	if unknownKindRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", unknownKindRR.Code)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/describe?kind=pod&name=ghost", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+
+	duplicate := pod.Detail{Summary: pod.Summary{Name: "frontend-7d8fdc9f7c-def34", Namespace: "prod", Status: "Pending"}}
+	if err := srv.pods.Add(duplicate, fixedTime); err != nil {
+		t.Fatalf("add duplicate pod: %v", err)
+	}
+	ambiguousRR := httptest.NewRecorder()
+	srv.ServeHTTP(ambiguousRR, httptest.NewRequest(http.MethodGet, "/api/describe?kind=pod&name=frontend-7d8fdc9f7c-def34", nil))
+	if ambiguousRR.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for a duplicated name, got %d", ambiguousRR.Code)
+	}
+}
+
+func TestHandleDeploymentAutoscaler(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)}
+	srv := NewWithClock(clock.Now)
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/deployments/frontend/hpa", strings.NewReader(`{"min":2,"max":10,"targetCPU":70}`))
+	createRR := httptest.NewRecorder()
+	srv.ServeHTTP(createRR, createReq)
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", createRR.Code)
+	}
+
+	if err := srv.deployments.SetCPULoad("frontend", 95); err != nil {
+		t.Fatalf("set cpu load: %v", err)
+	}
+	clock.Advance(30 * time.Second)
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend/hpa", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var hpa map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&hpa); err != nil {
+		t.Fatalf("decode autoscaler: %v", err)
+	}
+	current, _ := hpa["currentReplicas"].(float64)
+	if current <= 4 || current > 10 {
+		t.Fatalf("expected replicas scaled up within bounds, got %v", hpa["currentReplicas"])
+	}
+
+	invalidReq := httptest.NewRequest(http.MethodPost, "/api/deployments/frontend/hpa", strings.NewReader(`{"min":0,"max":10,"targetCPU":70}`))
+	invalidRR := httptest.NewRecorder()
+	srv.ServeHTTP(invalidRR, invalidReq)
+	if invalidRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidRR.Code)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/backend/hpa", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleAuditRetention(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	}, WithAuditRetention(2))
+
+	for _, name := range []string{"audit-a", "audit-b", "audit-c"} {
+		raw, _ := json.Marshal(map[string]any{"name": name})
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw)))
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d", rr.Code)
+		}
+	}
+
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, httptest.NewRequest(http.MethodGet, "/api/audit", nil))
+
+	var entries []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode audit entries: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+
+	if entries[0]["name"] != "audit-c" || entries[1]["name"] != "audit-b" {
+		t.Fatalf("expected oldest entry dropped, got %v", entries)
+	}
+
+	clearRR := httptest.NewRecorder()
+	srv.ServeHTTP(clearRR, httptest.NewRequest(http.MethodDelete, "/api/audit", nil))
+	if clearRR.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", clearRR.Code)
+	}
+
+	emptyRR := httptest.NewRecorder()
+	srv.ServeHTTP(emptyRR, httptest.NewRequest(http.MethodGet, "/api/audit", nil))
+
+	entries = nil
+	if err := json.NewDecoder(emptyRR.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode audit entries: %v", err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("expected empty audit trail, got %d entries", len(entries))
+	}
+}
+
+func TestHandleAdminDrain(t *testing.T) {
+	srv := New()
+
+	readyRR := httptest.NewRecorder()
+	srv.ServeHTTP(readyRR, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if readyRR.Code != http.StatusOK {
+		t.Fatalf("expected readyz 200 before drain, got %d", readyRR.Code)
+	}
+
+	drainRR := httptest.NewRecorder()
+	srv.ServeHTTP(drainRR, httptest.NewRequest(http.MethodPost, "/api/admin/drain", nil))
+	if drainRR.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d", drainRR.Code)
+	}
+
+	drainedRR := httptest.NewRecorder()
+	srv.ServeHTTP(drainedRR, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if drainedRR.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected readyz 503 after drain, got %d", drainedRR.Code)
+	}
+
+	healthRR := httptest.NewRecorder()
+	srv.ServeHTTP(healthRR, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if healthRR.Code != http.StatusOK {
+		t.Fatalf("expected healthz 200 after drain, got %d", healthRR.Code)
+	}
+
+	apiRR := httptest.NewRecorder()
+	srv.ServeHTTP(apiRR, httptest.NewRequest(http.MethodGet, "/api/nodes", nil))
+	if apiRR.Code != http.StatusOK {
+		t.Fatalf("expected API to keep serving after drain, got %d", apiRR.Code)
+	}
+}
+
+func TestHandlePreflight(t *testing.T) {
+	srv := New()
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/preflight", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var payload struct {
+		OK     bool `json:"ok"`
+		Checks []struct {
+			Store string `json:"store"`
+			OK    bool   `json:"ok"`
+		} `json:"checks"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode preflight: %v", err)
+	}
+
+	if !payload.OK || len(payload.Checks) == 0 {
+		t.Fatalf("expected healthy preflight, got %+v", payload)
+	}
+	for _, check := range payload.Checks {
+		if !check.OK {
+			t.Fatalf("store %s reported unhealthy", check.Store)
+		}
+	}
+}
+
+func TestHandlePreflightReportsBrokenStore(t *testing.T) {
+	srv := New()
+	srv.services = nil
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/preflight", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rr.Code)
+	}
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestSSEKeepalive(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)}
+	srv := NewWithClock(clock.Now, WithSSEKeepalive(30*time.Second))
+	srv.ssePoll = 5 * time.Millisecond
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, ok := openSSE(w)
+		if !ok {
+			t.Errorf("expected flusher support")
+			return
+		}
+		stream.comment("ready")
+		srv.runSSE(r.Context(), stream, nil)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); line != ": ready\n" {
+		t.Fatalf("unexpected first line %q", line)
+	}
+	reader.ReadString('\n')
+
+	clock.Advance(29 * time.Second)
+	time.Sleep(20 * time.Millisecond)
+	clock.Advance(time.Second)
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read heartbeat: %v", err)
+	}
+	if line != ": keepalive\n" {
+		t.Fatalf("expected keepalive comment, got %q", line)
+	}
+}
+
+func TestHandleClusterDiff(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	snapRR := httptest.NewRecorder()
+	srv.ServeHTTP(snapRR, httptest.NewRequest(http.MethodGet, "/api/cluster/snapshot", nil))
+	if snapRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", snapRR.Code)
+	}
+	var snap map[string]any
+	if err := json.NewDecoder(snapRR.Body).Decode(&snap); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+	token, _ := snap["token"].(string)
+	if token == "" {
+		t.Fatalf("expected snapshot token, got %v", snap)
+	}
+
+	raw, _ := json.Marshal(map[string]any{"name": "staging"})
+	createRR := httptest.NewRecorder()
+	srv.ServeHTTP(createRR, httptest.NewRequest(http.MethodPost, "/api/namespaces", bytes.NewReader(raw)))
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", createRR.Code)
+	}
+
+	diffRR := httptest.NewRecorder()
+	srv.ServeHTTP(diffRR, httptest.NewRequest(http.MethodGet, "/api/cluster/diff?from="+token, nil))
+	if diffRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", diffRR.Code)
+	}
+	var diff struct {
+		Namespaces struct {
+			Added   []string `json:"added"`
+			Removed []string `json:"removed"`
+		} `json:"namespaces"`
+	}
+	if err := json.NewDecoder(diffRR.Body).Decode(&diff); err != nil {
+		t.Fatalf("decode diff: %v", err)
+	}
+	if len(diff.Namespaces.Added) != 1 || diff.Namespaces.Added[0] != "staging" || len(diff.Namespaces.Removed) != 0 {
+		t.Fatalf("unexpected namespace diff %+v", diff.Namespaces)
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/cluster/diff?from=not-a-token", nil))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for bad token, got %d", badRR.Code)
+	}
+}
+
+func TestHandleDeploymentCreate(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"name":"worker","namespace":"default","replicas":3,"containers":[{"name":"worker","image":"registry.local/worker:1.0.0"}]}`)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/deployments", bytes.NewReader(raw)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	var detail map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment: %v", err)
+	}
+	if detail["name"] != "worker" || detail["desiredReplicas"] != float64(3) {
+		t.Fatalf("unexpected deployment %v", detail)
+	}
+
+	dupRR := httptest.NewRecorder()
+	srv.ServeHTTP(dupRR, httptest.NewRequest(http.MethodPost, "/api/deployments", bytes.NewReader(raw)))
+	if dupRR.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for duplicate, got %d", dupRR.Code)
+	}
+
+	bad := []byte(`{"name":"huge","replicas":500,"containers":[{"name":"huge","image":"registry.local/huge:1.0.0"}]}`)
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodPost, "/api/deployments", bytes.NewReader(bad)))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for invalid replicas, got %d", badRR.Code)
+	}
+	var errResp map[string]any
+	if err := json.NewDecoder(badRR.Body).Decode(&errResp); err != nil || errResp["error"] == nil {
+		t.Fatalf("expected error envelope, got %v (%v)", errResp, err)
+	}
+}
+
+func TestVisibleNamespaces(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	}, WithVisibleNamespaces([]string{"prod"}))
+
+	for _, path := range []string{"/api/pods", "/api/logs/stream", "/api/services", "/api/events"} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", path, rr.Code)
+		}
+		var items []map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&items); err != nil {
+			t.Fatalf("%s: decode: %v", path, err)
+		}
+		if len(items) == 0 {
+			t.Fatalf("%s: expected prod resources", path)
+		}
+		for _, item := range items {
+			if item["namespace"] != "prod" {
+				t.Fatalf("%s: expected only prod resources, got %v", path, item)
+			}
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected hidden pod to return 404, got %d", rr.Code)
+	}
+
+	// A name shared only by hidden namespaces must not surface as ambiguous.
+	duplicate := pod.Detail{Summary: pod.Summary{Name: "frontend-7d8fdc9f7c-abc12", Namespace: "staging", Status: "Pending"}}
+	if err := srv.pods.Add(duplicate, fixedTime); err != nil {
+		t.Fatalf("add duplicate pod: %v", err)
+	}
+	ambiguousRR := httptest.NewRecorder()
+	srv.ServeHTTP(ambiguousRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12", nil))
+	if ambiguousRR.Code != http.StatusNotFound {
+		t.Fatalf("expected hidden duplicate to return 404, got %d", ambiguousRR.Code)
+	}
+
+	// With one visible copy the name resolves to it; hidden copies are ignored.
+	visible := pod.Detail{Summary: pod.Summary{Name: "frontend-7d8fdc9f7c-abc12", Namespace: "prod", Status: "Running"}}
+	if err := srv.pods.Add(visible, fixedTime); err != nil {
+		t.Fatalf("add visible pod: %v", err)
+	}
+	visibleRR := httptest.NewRecorder()
+	srv.ServeHTTP(visibleRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12", nil))
+	if visibleRR.Code != http.StatusOK {
+		t.Fatalf("expected the visible copy with status 200, got %d", visibleRR.Code)
+	}
+	var detail map[string]any
+	if err := json.NewDecoder(visibleRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode pod: %v", err)
+	}
+	if detail["namespace"] != "prod" {
+		t.Fatalf("expected the prod copy, got %v", detail["namespace"])
+	}
+}
+
+func TestHandleDeploymentRollback(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	scaleRR := httptest.NewRecorder()
+	srv.ServeHTTP(scaleRR, httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", bytes.NewReader([]byte(`{"replicas":6}`))))
+	if scaleRR.Code != http.StatusOK {
+		t.Fatalf("expected scale status 200, got %d", scaleRR.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/deployments/frontend/rollback", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var detail map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment: %v", err)
+	}
+	if detail["desiredReplicas"] != float64(4) || detail["revision"] != float64(9) {
+		t.Fatalf("unexpected rollback result %v", detail)
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodPost, "/api/deployments/frontend/rollback", bytes.NewReader([]byte(`{"toRevision":1}`))))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown revision, got %d", badRR.Code)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodPost, "/api/deployments/missing/rollback", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for missing deployment, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentCompare(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"name":"frontend","namespace":"prod","replicas":4,"containers":[{"name":"frontend","image":"registry.local/frontend:2.4.0"}]}`)
+	createRR := httptest.NewRecorder()
+	srv.ServeHTTP(createRR, httptest.NewRequest(http.MethodPost, "/api/deployments", bytes.NewReader(raw)))
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", createRR.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/compare?a=default/frontend&b=prod/frontend", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var cmp deploy.Comparison
+	if err := json.NewDecoder(rr.Body).Decode(&cmp); err != nil {
+		t.Fatalf("decode comparison: %v", err)
+	}
+	if cmp.Identical || len(cmp.Differences) != 1 || cmp.Differences[0].Field != "images" {
+		t.Fatalf("expected image difference only, got %+v", cmp)
+	}
+	images, _ := cmp.Differences[0].B.([]any)
+	if len(images) != 1 || images[0] != "registry.local/frontend:2.4.0" {
+		t.Fatalf("unexpected prod images %v", cmp.Differences[0].B)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/compare?a=default/frontend&b=staging/frontend", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentNamespacedLookup(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"name":"frontend","namespace":"prod","replicas":4,"containers":[{"name":"frontend","image":"registry.local/frontend:2.4.0"}]}`)
+	createRR := httptest.NewRecorder()
+	srv.ServeHTTP(createRR, httptest.NewRequest(http.MethodPost, "/api/deployments", bytes.NewReader(raw)))
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", createRR.Code)
+	}
+
+	for _, path := range []string{"/api/deployments/frontend", "/api/deployments/frontend/status"} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("%s: expected status 409, got %d", path, rr.Code)
+		}
+	}
+	scaleRR := httptest.NewRecorder()
+	srv.ServeHTTP(scaleRR, httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", bytes.NewReader([]byte(`{"replicas":6}`))))
+	if scaleRR.Code != http.StatusConflict {
+		t.Fatalf("expected scale status 409, got %d", scaleRR.Code)
+	}
+
+	for _, ns := range []string{"default", "prod"} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/"+ns+"/frontend", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d", ns, rr.Code)
+		}
+		var detail map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+			t.Fatalf("decode deployment detail: %v", err)
+		}
+		if detail["namespace"] != ns {
+			t.Fatalf("expected namespace %s, got %v", ns, detail["namespace"])
+		}
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/staging/frontend", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentRestart(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/deployments/frontend/restart", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	getRR := httptest.NewRecorder()
+	srv.ServeHTTP(getRR, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend", nil))
+	var detail map[string]any
+	if err := json.NewDecoder(getRR.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment: %v", err)
+	}
+	if detail["status"] != "Updating" || detail["updatedReplicas"] != float64(0) || detail["revision"] != float64(8) {
+		t.Fatalf("expected restart to be observable, got %v", detail)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodPost, "/api/deployments/missing/restart", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleNodeConditions(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/nodes/conditions", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var matrix node.ConditionMatrix
+	if err := json.NewDecoder(rr.Body).Decode(&matrix); err != nil {
+		t.Fatalf("decode matrix: %v", err)
+	}
+	if matrix.Nodes["node-3"]["NetworkUnavailable"] != "True" || matrix.Nodes["node-1"]["NetworkUnavailable"] != "Unknown" {
+		t.Fatalf("unexpected condition matrix %+v", matrix.Nodes)
+	}
+}
+
+func TestHandleDeploymentPauseAndResume(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	send := func(method, path string, body []byte) map[string]any {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(method, path, bytes.NewReader(body)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s %s: expected status 200, got %d", method, path, rr.Code)
+		}
+		var detail map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+			t.Fatalf("decode deployment: %v", err)
+		}
+		return detail
+	}
+
+	if detail := send(http.MethodPut, "/api/deployments/frontend/pause", nil); detail["paused"] != true || detail["status"] != "Paused" {
+		t.Fatalf("expected paused deployment, got %v", detail)
+	}
+	detail := send(http.MethodPut, "/api/deployments/frontend/scale", []byte(`{"replicas":2}`))
+	if detail["desiredReplicas"] != float64(2) || detail["readyReplicas"] != float64(4) || detail["status"] != "Paused" {
+		t.Fatalf("expected frozen progress while paused, got %v", detail)
+	}
+	detail = send(http.MethodPut, "/api/deployments/frontend/resume", nil)
+	if detail["paused"] != false || detail["readyReplicas"] != float64(2) || detail["status"] != "Healthy" {
+		t.Fatalf("expected resumed deployment, got %v", detail)
+	}
+}
+
+func TestHandlePodEvents(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods/jobs-runner-bb7d67f4f6-123zt/events?type=Warning&limit=10", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var events []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&events); err != nil {
+		t.Fatalf("decode events: %v", err)
+	}
+	if len(events) != 1 || events[0]["reason"] != "FailedScheduling" {
+		t.Fatalf("unexpected events %v", events)
+	}
+
+	emptyRR := httptest.NewRecorder()
+	srv.ServeHTTP(emptyRR, httptest.NewRequest(http.MethodGet, "/api/pods/frontend-7d8fdc9f7c-abc12/events?type=Warning", nil))
+	if emptyRR.Code != http.StatusOK || strings.TrimSpace(emptyRR.Body.String()) != "[]" {
+		t.Fatalf("expected empty array, got %d %q", emptyRR.Code, emptyRR.Body.String())
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/pods/missing/events", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentSetImage(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"container":"api","image":"registry.local/backend:1.13.0"}`)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/deployments/backend/image", bytes.NewReader(raw)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var detail deploy.Detail
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode deployment: %v", err)
+	}
+	if len(detail.Images) != 1 || detail.Images[0] != "registry.local/backend:1.13.0" {
+		t.Fatalf("unexpected images %v", detail.Images)
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodPut, "/api/deployments/backend/image", bytes.NewReader([]byte(`{"container":"sidecar","image":"busybox"}`))))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown container, got %d", badRR.Code)
+	}
+}
+
+func TestHandleClusterHealthWeights(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	clock := func() time.Time { return fixedTime }
+
+	score := func(srv *Server) cluster.HealthScore {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/cluster/health", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		var out cluster.HealthScore
+		if err := json.NewDecoder(rr.Body).Decode(&out); err != nil {
+			t.Fatalf("decode health: %v", err)
+		}
+		return out
+	}
+
+	// One NotReady node, one Pending pod and two unhealthy deployments.
+	if got := score(NewWithClock(clock)).Score; got != 63 {
+		t.Fatalf("expected default score 63, got %d", got)
+	}
+	custom := cluster.HealthConfig{NotReadyNode: 40}
+	if got := score(NewWithClock(clock, WithHealthWeights(custom))).Score; got != 60 {
+		t.Fatalf("expected custom score 60, got %d", got)
+	}
+}
+
+func TestHealthOptionsPanicOnInvalidValues(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	clock := func() time.Time { return fixedTime }
+
+	mustPanic := func(name string, opt Option) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s: expected panic", name)
+			}
+		}()
+		NewWithClock(clock, opt)
+	}
+
+	mustPanic("negative weight", WithHealthWeights(cluster.HealthConfig{NotReadyNode: -5}))
+	mustPanic("threshold below 0", WithDegradedThreshold(-1))
+	mustPanic("threshold above 100", WithDegradedThreshold(101))
+}
+
+func TestHandleDeploymentRevisions(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"container":"api","image":"registry.local/backend:1.13.0"}`)
+	imageRR := httptest.NewRecorder()
+	srv.ServeHTTP(imageRR, httptest.NewRequest(http.MethodPut, "/api/deployments/backend/image", bytes.NewReader(raw)))
+	if imageRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", imageRR.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/backend/revisions", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var revisions []deploy.Revision
+	if err := json.NewDecoder(rr.Body).Decode(&revisions); err != nil {
+		t.Fatalf("decode revisions: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 21 || revisions[1].Images[0] != "registry.local/backend:1.13.0" {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/missing/revisions", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandlePodLabelsBySelector(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"labels":{"canary":"true"}}`)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/pods/labels?labelSelector=app=frontend", bytes.NewReader(raw)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["updated"] != float64(2) {
+		t.Fatalf("expected 2 pods updated, got %v", resp)
+	}
+
+	for _, name := range []string{"frontend-7d8fdc9f7c-abc12", "frontend-7d8fdc9f7c-def34"} {
+		getRR := httptest.NewRecorder()
+		srv.ServeHTTP(getRR, httptest.NewRequest(http.MethodGet, "/api/pods/"+name, nil))
+		var detail pod.Detail
+		if err := json.NewDecoder(getRR.Body).Decode(&detail); err != nil {
+			t.Fatalf("decode pod: %v", err)
+		}
+		if detail.Labels["canary"] != "true" {
+			t.Fatalf("expected %s to be labeled, got %v", name, detail.Labels)
+		}
+	}
+
+	labelled := 0
+	for _, entry := range srv.audit.List() {
+		if entry.Action != "label" {
+			continue
+		}
+		if entry.Namespace != "default" || !strings.HasPrefix(entry.Name, "frontend-") {
+			t.Fatalf("expected one audit entry per pod, got %+v", entry)
+		}
+		labelled++
+	}
+	if labelled != 2 {
+		t.Fatalf("expected 2 label audit entries, got %d", labelled)
+	}
+
+	reservedRR := httptest.NewRecorder()
+	srv.ServeHTTP(reservedRR, httptest.NewRequest(http.MethodPut, "/api/pods/labels?labelSelector=app=frontend", bytes.NewReader([]byte(`{"labels":{"kubernetes.io/os":"linux"}}`))))
+	if reservedRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for reserved key, got %d", reservedRR.Code)
+	}
+}
+
+func TestHandleServiceCreateAndDelete(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	raw := []byte(`{"name":"api","namespace":"default","type":"ClusterIP","ports":[{"name":"http","port":80,"targetPort":8080}]}`)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/services", bytes.NewReader(raw)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+
+	dupRR := httptest.NewRecorder()
+	srv.ServeHTTP(dupRR, httptest.NewRequest(http.MethodPost, "/api/services", bytes.NewReader(raw)))
+	if dupRR.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for duplicate, got %d", dupRR.Code)
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodPost, "/api/services", bytes.NewReader([]byte(`{"name":"bad","ports":[{"port":0}]}`))))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for bad port, got %d", badRR.Code)
+	}
+
+	delRR := httptest.NewRecorder()
+	srv.ServeHTTP(delRR, httptest.NewRequest(http.MethodDelete, "/api/services/api", nil))
+	if delRR.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", delRR.Code)
+	}
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodDelete, "/api/services/api", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestJSONAccessLog(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	}, WithJSONLogger(&buf))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/nodes/missing", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+	if rr.Header().Get("X-Request-ID") != "req-123" {
+		t.Fatalf("expected request ID to be echoed, got %q", rr.Header().Get("X-Request-ID"))
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one log line, got %q", buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("parse log line: %v", err)
+	}
+	if entry["status"] != float64(http.StatusNotFound) || entry["method"] != "GET" || entry["path"] != "/api/nodes/missing" || entry["requestID"] != "req-123" {
+		t.Fatalf("unexpected log entry %v", entry)
+	}
+	if entry["bytes"] != float64(rr.Body.Len()) {
+		t.Fatalf("expected bytes %d, got %v", rr.Body.Len(), entry["bytes"])
+	}
+}
+
+func TestHandleAlerts(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/alerts", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var alerts []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&alerts); err != nil {
+		t.Fatalf("decode alerts: %v", err)
+	}
+
+	firing := make(map[string]map[string]any)
+	for _, a := range alerts {
+		firing[a["rule"].(string)+" "+a["object"].(string)] = a
+	}
+	if a := firing["NodeNotReady node/node-3"]; a == nil || a["severity"] != "critical" {
+		t.Fatalf("expected NotReady alert for node-3, got %v", alerts)
+	}
+	if a := firing["DeploymentDown deployment/batch-jobs"]; a == nil || a["description"] == "" {
+		t.Fatalf("expected Down alert for batch-jobs, got %v", alerts)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected exactly two firing alerts, got %v", alerts)
+	}
+}
+
+func TestHandleAlertAck(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)}
+	srv := NewWithClock(clock.Now)
+
+	downAlert := func() map[string]any {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/alerts", nil))
+		var alerts []map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&alerts); err != nil {
+			t.Fatalf("decode alerts: %v", err)
+		}
+		for _, a := range alerts {
+			if a["rule"] == "DeploymentDown" {
+				return a
+			}
+		}
+		return nil
+	}
+	scale := func(replicas int) {
+		t.Helper()
+		clock.Advance(time.Minute)
+		raw, _ := json.Marshal(map[string]int{"replicas": replicas})
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/deployments/batch-jobs/scale", bytes.NewReader(raw)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("scale: expected status 200, got %d", rr.Code)
+		}
+	}
+
+	firing := downAlert()
+	if firing == nil || firing["acknowledged"] != false {
+		t.Fatalf("expected unacknowledged Down alert, got %v", firing)
+	}
+	id := firing["id"].(string)
+
+	ackRR := httptest.NewRecorder()
+	srv.ServeHTTP(ackRR, httptest.NewRequest(http.MethodPost, "/api/alerts/"+id+"/ack", bytes.NewReader([]byte(`{"by":"oncall"}`))))
+	if ackRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", ackRR.Code)
+	}
+	if acked := downAlert(); acked["acknowledged"] != true || acked["ackedBy"] != "oncall" {
+		t.Fatalf("expected acknowledged alert, got %v", acked)
+	}
+
+	scale(0)
+	if cleared := downAlert(); cleared != nil {
+		t.Fatalf("expected alert to clear, got %v", cleared)
+	}
+	scale(2)
+	if refired := downAlert(); refired == nil || refired["acknowledged"] != false {
+		t.Fatalf("expected re-fired alert to be unacknowledged, got %v", refired)
+	}
+
+	// Clearing and re-firing between polls must not carry the ack over.
+	ackRR = httptest.NewRecorder()
+	srv.ServeHTTP(ackRR, httptest.NewRequest(http.MethodPost, "/api/alerts/"+id+"/ack", nil))
+	if ackRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", ackRR.Code)
+	}
+	scale(0)
+	scale(2)
+	if refired := downAlert(); refired == nil || refired["acknowledged"] != false {
+		t.Fatalf("expected alert re-fired between polls to be unacknowledged, got %v", refired)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodPost, "/api/alerts/unknown/ack", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleMetricsFormats(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected Prometheus text, got %q", ct)
+	}
+	if body := rr.Body.String(); strings.Contains(body, "# EOF") || !strings.Contains(body, "# TYPE kubedash_pod_restarts_total counter") {
+		t.Fatalf("unexpected Prometheus body:\n%s", body)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	omRR := httptest.NewRecorder()
+	srv.ServeHTTP(omRR, req)
+	if ct := omRR.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Fatalf("expected OpenMetrics content type, got %q", ct)
+	}
+	body := omRR.Body.String()
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Fatalf("expected trailing # EOF line, got:\n%s", body)
+	}
+	for _, want := range []string{
+		"# TYPE kubedash_pod_restarts counter",
+		"# UNIT kubedash_pod_age_seconds seconds",
+		`kubedash_alerts_firing{severity="critical"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in OpenMetrics body:\n%s", want, body)
+		}
+	}
+}
+
+func TestSlowThresholdLogsStoreCalls(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	}, WithSlowThreshold(0))
+
+	var buf bytes.Buffer
+	srv.slowLog = log.New(&buf, "", 0)
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(buf.String(), "slow store call: deployments.List") {
+		t.Fatalf("expected deployments.List in slow log, got %q", buf.String())
+	}
+
+	quiet := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+	if quiet.slowLog != nil {
+		t.Fatalf("expected slow log to be disabled by default")
+	}
+}
+
+func TestHandleLogExport(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/export?level=ERROR", nil)
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("expected CSV content type, got %q", ct)
+	}
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if lines[0] != "timestamp,namespace,pod,level,message" || len(lines) < 2 {
+		t.Fatalf("unexpected CSV export:\n%s", rr.Body.String())
+	}
+	for _, line := range lines[1:] {
+		if !strings.Contains(line, ",ERROR,") {
+			t.Fatalf("expected only ERROR rows, got %q", line)
+		}
+	}
+
+	textRR := httptest.NewRecorder()
+	srv.ServeHTTP(textRR, httptest.NewRequest(http.MethodGet, "/api/logs/export?format=text&level=ERROR", nil))
+	if ct := textRR.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") || !strings.Contains(textRR.Body.String(), "[ERROR]") {
+		t.Fatalf("unexpected text export %q: %s", ct, textRR.Body.String())
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/logs/export?format=xml", nil))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", badRR.Code)
+	}
+}
+
+func TestHandlePodsExpandOwner(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/pods?expand=owner&limit=100", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var pods []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&pods); err != nil {
+		t.Fatalf("decode pods: %v", err)
+	}
+
+	frontends := 0
+	for _, p := range pods {
+		owner, hasKey := p["owner"]
+		if !hasKey {
+			t.Fatalf("expected owner key on every pod, got %v", p)
+		}
+		if !strings.HasPrefix(p["name"].(string), "frontend-") {
+			continue
+		}
+		frontends++
+		summary, ok := owner.(map[string]any)
+		if !ok || summary["name"] != "frontend" {
+			t.Fatalf("expected inline frontend deployment, got %v", owner)
+		}
+		if summary["desiredReplicas"] != float64(4) || summary["readyReplicas"] == nil {
+			t.Fatalf("expected replica counts on owner, got %v", summary)
+		}
+	}
+	if frontends == 0 {
+		t.Fatalf("expected frontend pods in the seed")
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/pods?expand=node", nil))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", badRR.Code)
+	}
+}
+
+func TestHandleLogStreamCursor(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/logs/stream?cursor=&limit=2", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var page map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	if entries := page["entries"].([]any); len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	next, _ := page["nextCursor"].(string)
+	if next == "" {
+		t.Fatalf("expected a next cursor")
+	}
+
+	nextRR := httptest.NewRecorder()
+	srv.ServeHTTP(nextRR, httptest.NewRequest(http.MethodGet, "/api/logs/stream?limit=2&cursor="+next, nil))
+	if nextRR.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", nextRR.Code)
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/logs/stream?cursor=garbage", nil))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", badRR.Code)
+	}
+}
+
+func TestHandleServiceEndpointsGroupedByReadiness(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/services/edge-gateway/endpoints?groupBy=ready", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var groups map[string][]map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&groups); err != nil {
+		t.Fatalf("decode endpoint groups: %v", err)
+	}
+	pending := false
+	for _, ep := range groups["notReady"] {
+		if ep["pod"] == "edge-gateway-7d8fdc9f7c-9012b" {
+			pending = true
+		}
+	}
+	if !pending {
+		t.Fatalf("expected pending pod endpoint in notReady, got %v", groups)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/services/ghost/endpoints?groupBy=ready", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleLogAppend(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/logs", strings.NewReader(body)))
+		return rr
+	}
+
+	rr := post(`{"namespace":"prod","pod":"api-0","level":"warn","message":"disk 91% full","timestamp":"2024-07-12T23:30:00+08:00"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var stored map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&stored); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	if stored["level"] != "WARN" || stored["timestamp"] != "2024-07-12T15:30:00Z" {
+		t.Fatalf("expected normalized entry, got %v", stored)
+	}
+
+	found := false
+	for _, entry := range srv.logs.ListLogs(fixedTime, logs.LogFilter{Pod: "api-0"}) {
+		if entry.Message == "disk 91% full" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected appended line in the log store")
+	}
+
+	for _, body := range []string{
+		`{"namespace":"prod","pod":"api-0","level":"FATAL","message":"x"}`,
+		`{"namespace":"prod","level":"INFO","message":"x"}`,
+		`{"namespace":"prod","pod":"api-0","level":"INFO","timestamp":"yesterday"}`,
+		`not json`,
+	} {
+		if bad := post(body); bad.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for %s, got %d", body, bad.Code)
+		}
+	}
+}
+
+func TestHandleDeploymentRecommendation(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	if _, _, err := srv.deployments.SetAutoscaler("backend", deploy.AutoscalerSpec{Min: 1, Max: 20, TargetCPU: 60}, fixedTime); err != nil {
+		t.Fatalf("set autoscaler: %v", err)
+	}
+	if err := srv.deployments.SetCPULoad("backend", 90); err != nil {
+		t.Fatalf("set cpu load: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/backend/recommendation", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var rec map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&rec); err != nil {
+		t.Fatalf("decode recommendation: %v", err)
+	}
+	if rec["recommendedReplicas"].(float64) <= rec["currentReplicas"].(float64) {
+		t.Fatalf("expected recommendation above current replicas, got %v", rec)
+	}
+	if rationale, _ := rec["rationale"].(string); !strings.Contains(rationale, "scale up") {
+		t.Fatalf("expected scale-up rationale, got %q", rationale)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/ghost/recommendation", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentEvents(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend/events", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var events []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&events); err != nil {
+		t.Fatalf("decode events: %v", err)
+	}
+	found := false
+	for _, ev := range events {
+		if ev["reason"] == "ScalingReplicaSet" && ev["kind"] == "Deployment" && ev["name"] == "frontend" {
+			found = true
+		}
+		if ev["namespace"] != "default" {
+			t.Fatalf("unexpected event outside default: %v", ev)
+		}
+	}
+	if !found {
+		t.Fatalf("expected ScalingReplicaSet event, got %v", events)
+	}
+
+	srv.logs.AppendEvent(logs.Event{
+		Timestamp: fixedTime.Format(time.RFC3339),
+		Namespace: "default",
+		Kind:      "Pod",
+		Name:      "frontend-7d8fdc9f7c-abc12",
+		Type:      "Warning",
+		Reason:    "BackOff",
+		Message:   "Back-off restarting failed container",
+	})
+	podRR := httptest.NewRecorder()
+	srv.ServeHTTP(podRR, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend/events", nil))
+	var withPod []map[string]any
+	if err := json.NewDecoder(podRR.Body).Decode(&withPod); err != nil {
+		t.Fatalf("decode events: %v", err)
+	}
+	if len(withPod) != len(events)+1 || withPod[0]["reason"] != "BackOff" {
+		t.Fatalf("expected the owned pod event first, got %v", withPod)
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/deployments/ghost/events", nil))
+	if missingRR.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleDeploymentLogs(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend/logs?limit=50", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var entries []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode logs: %v", err)
+	}
+	sources := make(map[any]bool)
+	for i, entry := range entries {
+		sources[entry["pod"]] = true
+		if i > 0 && entries[i-1]["timestamp"].(string) < entry["timestamp"].(string) {
+			t.Fatalf("expected newest first, got %v", entries)
+		}
+	}
+	if len(sources) != 2 || !sources["frontend-7d8fdc9f7c-abc12"] || !sources["frontend-7d8fdc9f7c-def34"] {
+		t.Fatalf("expected lines from both frontend replicas, got %v", sources)
+	}
+
+	limitedRR := httptest.NewRecorder()
+	srv.ServeHTTP(limitedRR, httptest.NewRequest(http.MethodGet, "/api/deployments/frontend/logs?limit=1", nil))
+	var limited []map[string]any
+	if err := json.NewDecoder(limitedRR.Body).Decode(&limited); err != nil {
+		t.Fatalf("decode logs: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(limited))
+	}
+
+	for path, want := range map[string]int{
+		"/api/deployments/ghost/logs":            http.StatusNotFound,
+		"/api/deployments/frontend/logs?limit=x": http.StatusBadRequest,
+	} {
+		errRR := httptest.NewRecorder()
+		srv.ServeHTTP(errRR, httptest.NewRequest(http.MethodGet, path, nil))
+		if errRR.Code != want {
+			t.Fatalf("expected status %d for %s, got %d", want, path, errRR.Code)
+		}
+	}
+}
+
+func TestHandleClusterImportDelete(t *testing.T) {
+	const kubeconfigYAML = `apiVersion: v1
+clusters:
+- name: staging
+  cluster:
+    server: %s
+contexts:
+- name: staging-admin
+  context:
+    cluster: staging
+    user: admin
+current-context: staging-admin
+`
+
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	importKubeconfig(t, srv, "old.yaml", fmt.Sprintf(kubeconfigYAML, "https://old.example.test"))
+	importKubeconfig(t, srv, "new.yaml", fmt.Sprintf(kubeconfigYAML, "https://new.example.test"))
+
+	del := func() int {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/cluster/imports/staging-admin", nil))
+		return rr.Code
+	}
+
+	// Both imports are named staging-admin; the newest goes first.
+	if code := del(); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	remaining, ok := srv.kubeconfigs.Get("staging-admin")
+	if !ok || remaining.Clusters[0].Server != "https://old.example.test" {
+		t.Fatalf("expected the older import to remain, got %+v", remaining)
+	}
+	if code := del(); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if code := del(); code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", code)
+	}
+}
+
+func TestHandlePrefixSearch(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/search/prefix?p=fron", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var result map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("decode search: %v", err)
+	}
+
+	names := func(kind string) []string {
+		var out []string
+		for _, item := range result[kind].([]any) {
+			out = append(out, item.(string))
+		}
+		return out
+	}
+	contains := func(items []string, want string) bool {
+		for _, item := range items {
+			if item == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !contains(names("pods"), "frontend-7d8fdc9f7c-abc12") {
+		t.Fatalf("expected frontend pod, got %v", names("pods"))
+	}
+	if !contains(names("services"), "frontend") || !contains(names("deployments"), "frontend") {
+		t.Fatalf("expected frontend service and deployment, got %v / %v", names("services"), names("deployments"))
+	}
+	for _, kind := range []string{"pods", "services", "deployments", "nodes", "namespaces"} {
+		items := names(kind)
+		if len(items) > prefixSearchLimit {
+			t.Fatalf("expected at most %d %s, got %d", prefixSearchLimit, kind, len(items))
+		}
+		for _, item := range items {
+			if !strings.HasPrefix(item, "fron") {
+				t.Fatalf("unexpected %s match %q", kind, item)
+			}
+		}
+	}
+
+	missingRR := httptest.NewRecorder()
+	srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/search/prefix", nil))
+	if missingRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", missingRR.Code)
+	}
+}
+
+func TestHandleClusterImportDetail(t *testing.T) {
+	const kubeconfigYAML = `apiVersion: v1
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.test
+`
+
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	// Without any context the import is named after the file.
+	if rr := importKubeconfig(t, srv, "staging.yaml", kubeconfigYAML); rr.Code != http.StatusCreated {
+		t.Fatalf("import: expected 201, got %d", rr.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/cluster/imports/staging", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var summary map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&summary); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+	if summary["name"] != "staging" || len(summary["clusters"].([]any)) != 1 {
+		t.Fatalf("unexpected summary %v", summary)
+	}
+
+	for _, name := range []string{"Staging", "stag", "ghost"} {
+		missingRR := httptest.NewRecorder()
+		srv.ServeHTTP(missingRR, httptest.NewRequest(http.MethodGet, "/api/cluster/imports/"+name, nil))
+		if missingRR.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 for %q, got %d", name, missingRR.Code)
+		}
+	}
+}
+
+func TestHandleResourceAudit(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time {
+		return fixedTime
+	})
+
+	scaleRR := httptest.NewRecorder()
+	srv.ServeHTTP(scaleRR, httptest.NewRequest(http.MethodPut, "/api/deployments/frontend/scale", strings.NewReader(`{"replicas":6}`)))
+	if scaleRR.Code != http.StatusOK {
+		t.Fatalf("scale: expected status 200, got %d", scaleRR.Code)
+	}
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/api/deployments/backend/scale", strings.NewReader(`{"replicas":2}`)))
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/audit/resource?kind=deployment&name=frontend", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var entries []map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode audit: %v", err)
+	}
+	if len(entries) != 1 || entries[0]["action"] != "scale" || entries[0]["name"] != "frontend" {
+		t.Fatalf("expected a single frontend scale entry, got %v", entries)
+	}
+	before := entries[0]["before"].(map[string]any)
+	after := entries[0]["after"].(map[string]any)
+	if before["replicas"] != float64(4) || after["replicas"] != float64(6) {
+		t.Fatalf("expected replicas 4 -> 6, got %v -> %v", before, after)
+	}
+
+	// The other deployment mutations record their changed fields too.
+	changes := []struct {
+		method, path, body, action, field string
+		before, after                     any
+	}{
+		{http.MethodPut, "/api/deployments/frontend/pause", "", "pause", "paused", false, true},
+		{http.MethodPut, "/api/deployments/frontend/image", `{"container":"frontend","image":"registry.local/frontend:3.0.0"}`, "set-image", "image", nil, "registry.local/frontend:3.0.0"},
+	}
+	for _, tc := range changes {
+		changeRR := httptest.NewRecorder()
+		srv.ServeHTTP(changeRR, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		if changeRR.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tc.action, changeRR.Code)
+		}
+		latest := srv.audit.ListResource("deployment", "", "frontend", 1)
+		if len(latest) != 1 || latest[0].Action != tc.action {
+			t.Fatalf("%s: expected a new audit entry, got %+v", tc.action, latest)
+		}
+		got := latest[0]
+		if (tc.before != nil && got.Before[tc.field] != tc.before) || got.Before[tc.field] == got.After[tc.field] || got.After[tc.field] != tc.after {
+			t.Fatalf("%s: unexpected %s change %v -> %v", tc.action, tc.field, got.Before, got.After)
+		}
+	}
+
+	badRR := httptest.NewRecorder()
+	srv.ServeHTTP(badRR, httptest.NewRequest(http.MethodGet, "/api/audit/resource?kind=deployment", nil))
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", badRR.Code)
+	}
+}
+
+func TestHandleClusterImportActivate(t *testing.T) {
+	const kubeconfigYAML = `apiVersion: v1
+clusters:
+- name: %[1]s
+  cluster:
+    server: https://%[1]s.example.test
+contexts:
+- name: %[1]s-admin
+  context:
+    cluster: %[1]s
+    user: admin
+current-context: %[1]s-admin
+`
+
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	srv := NewWithClock(func() time.Time { return fixedTime })
+
+	importKubeconfig(t, srv, "prod.yaml", fmt.Sprintf(kubeconfigYAML, "prod"))
+	importKubeconfig(t, srv, "staging.yaml", fmt.Sprintf(kubeconfigYAML, "staging"))
+
+	activate := func(name string) int {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/cluster/imports/"+name+"/activate", nil))
+		return rr.Code
+	}
+	if code := activate("prod-admin"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := activate("staging-admin"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	listRR := httptest.NewRecorder()
+	srv.ServeHTTP(listRR, httptest.NewRequest(http.MethodGet, "/api/cluster/imports", nil))
+	var imports []map[string]any
+	if err := json.NewDecoder(listRR.Body).Decode(&imports); err != nil {
+		t.Fatalf("decode imports: %v", err)
+	}
+	for _, item := range imports {
+		if want := item["name"] == "staging-admin"; item["active"] != want {
+			t.Fatalf("unexpected active flag on %v", item)
+		}
+	}
+
+	if code := activate("ghost"); code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", code)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	fixedTime := time.Date(2024, 7, 12, 15, 30, 0, 0, time.UTC)
+	send := func(srv *Server, method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/namespaces", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+		}
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		return rr
+	}
+
+	defaults := NewWithClock(func() time.Time { return fixedTime })
+	if rr := send(defaults, http.MethodGet, "http://localhost:5173", false); rr.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected no CORS headers by default, got %v", rr.Header())
+	}
+
+	srv := NewWithClock(func() time.Time { return fixedTime }, WithCORSOrigins("http://localhost:5173/"))
+
+	preflight := send(srv, http.MethodOptions, "http://localhost:5173", true)
+	if preflight.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204 for preflight, got %d", preflight.Code)
+	}
+	if got := preflight.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:5173" {
+		t.Fatalf("unexpected allowed origin %q", got)
+	}
+	if !strings.Contains(preflight.Header().Get("Access-Control-Allow-Methods"), "POST") ||
+		!strings.Contains(preflight.Header().Get("Access-Control-Allow-Headers"), "Content-Type") {
+		t.Fatalf("unexpected preflight headers %v", preflight.Header())
+	}
+
+	actual := send(srv, http.MethodGet, "http://localhost:5173", false)
+	if actual.Code != http.StatusOK || actual.Header().Get("Access-Control-Allow-Origin") != "http://localhost:5173" {
+		t.Fatalf("expected allowed GET with CORS header, got %d %v", actual.Code, actual.Header())
+	}
+	if !strings.Contains(actual.Header().Get("Access-Control-Expose-Headers"), "X-Total-Count") {
+		t.Fatalf("expected exposed headers, got %v", actual.Header())
+	}
+
+	denied := send(srv, http.MethodOptions, "https://evil.example", true)
+	if denied.Header().Get("Access-Control-Allow-Origin") != "" || denied.Code == http.StatusNoContent {
+		t.Fatalf("expected preflight from unlisted origin to be refused, got %d %v", denied.Code, denied.Header())
+	}
+
+	wildcard := NewWithClock(func() time.Time { return fixedTime }, WithCORSOrigins("*"))
+	if rr := send(wildcard, http.MethodGet, "https://other.example", false); rr.Header().Get("Access-Control-Allow-Origin") != "https://other.example" {
+		t.Fatalf("expected wildcard policy to echo the origin, got %v", rr.Header())
 	}
 }
@@ -1,35 +1,124 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"k8s_dashboard/internal/service"
 )
 
 func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		s.idempotent(w, r, s.handleServiceCreate)
+		return
+	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	payload := s.services.List(s.now())
+	query := r.URL.Query()
+	var filter service.PortFilter
+	// Checked in order so the reported parameter is stable.
+	params := []struct {
+		name string
+		dst  *int
+	}{{"port", &filter.Port}, {"targetPort", &filter.TargetPort}}
+	for _, param := range params {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		port, err := strconv.Atoi(raw)
+		if err != nil || port < 1 || port > 65535 {
+			writeJSON(w, errorResponse{Error: param.name + " 参数无效"}, http.StatusBadRequest)
+			return
+		}
+		*param.dst = port
+	}
+
+	payload := visibleOnly(s, timeStore(s, "services.List", func() []service.Summary { return s.services.ListFiltered(s.now(), filter) }), serviceNamespace)
 	writeJSON(w, payload, http.StatusOK)
 }
 
-func (s *Server) handleServiceByName(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+func (s *Server) handleServiceCreate(w http.ResponseWriter, r *http.Request) {
+	var spec service.CreateSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := s.services.Create(spec, s.now())
+	if err != nil {
+		switch err {
+		case service.ErrInvalidType:
+			writeJSON(w, errorResponse{Error: "Service 类型无效，可选 ClusterIP、NodePort、LoadBalancer"}, http.StatusBadRequest)
+		case service.ErrInvalidPort:
+			writeJSON(w, errorResponse{Error: "端口无效，取值范围 1-65535"}, http.StatusBadRequest)
+		case service.ErrInvalidSpec:
+			writeJSON(w, errorResponse{Error: "Service 名称不能为空"}, http.StatusBadRequest)
+		case service.ErrExists:
+			writeJSON(w, errorResponse{Error: "Service 已存在"}, http.StatusConflict)
+		default:
+			http.Error(w, "failed to create service", http.StatusInternalServerError)
+		}
 		return
 	}
 
+	s.recordAudit("create", "service", detail.Namespace, detail.Name)
+	writeJSON(w, detail, http.StatusCreated)
+}
+
+func (s *Server) handleServiceByName(w http.ResponseWriter, r *http.Request) {
 	name := strings.TrimPrefix(r.URL.Path, "/api/services/")
+	if svc, sub, ok := strings.Cut(name, "/"); ok {
+		if svc == "" || sub != "endpoints" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleServiceEndpoints(w, r, svc)
+		return
+	}
 	if name == "" {
 		http.NotFound(w, r)
 		return
 	}
 
+	switch r.Method {
+	case http.MethodGet:
+		s.handleServiceDetail(w, r, name)
+	case http.MethodDelete:
+		s.handleServiceDelete(w, name)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleServiceDelete(w http.ResponseWriter, name string) {
 	detail, err := s.services.Get(name, s.now())
+	if err != nil || !s.namespaceVisible(detail.Namespace) || !s.services.Delete(name) {
+		writeJSON(w, errorResponse{Error: "Service 不存在"}, http.StatusNotFound)
+		return
+	}
+
+	s.recordAudit("delete", "service", detail.Namespace, detail.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleServiceDetail(w http.ResponseWriter, r *http.Request, name string) {
+	loc, err := displayZone(r)
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "时区无效"}, http.StatusBadRequest)
+		return
+	}
+
+	detail, err := timeStoreErr(s, "services.Get", func() (service.Detail, error) { return s.services.Get(name, s.now()) })
+	if err == nil && !s.namespaceVisible(detail.Namespace) {
+		err = service.ErrNotFound
+	}
 	if err != nil {
 		if err == service.ErrNotFound {
 			writeJSON(w, errorResponse{Error: "Service 不存在"}, http.StatusNotFound)
@@ -39,5 +128,46 @@ func (s *Server) handleServiceByName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if loc != nil {
+		detail.CreatedAtLocal = localizeTime(detail.CreatedAt, loc)
+	}
 	writeJSON(w, detail, http.StatusOK)
 }
+
+func serviceNamespace(svc service.Summary) string { return svc.Namespace }
+
+func (s *Server) handleServiceEndpoints(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupBy := r.URL.Query().Get("groupBy")
+	if groupBy != "" && groupBy != "ready" {
+		writeJSON(w, errorResponse{Error: "groupBy 参数无效，仅支持 ready"}, http.StatusBadRequest)
+		return
+	}
+
+	detail, err := s.services.Get(name, s.now())
+	if err != nil || !s.namespaceVisible(detail.Namespace) {
+		writeJSON(w, errorResponse{Error: "Service 不存在"}, http.StatusNotFound)
+		return
+	}
+
+	var payload any
+	if groupBy == "ready" {
+		payload, err = s.services.EndpointsByReadiness(name)
+	} else {
+		payload, err = s.services.Endpoints(name)
+	}
+	if err != nil {
+		if err == service.ErrNotFound {
+			writeJSON(w, errorResponse{Error: "Service 不存在"}, http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to load service endpoints", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, payload, http.StatusOK)
+}
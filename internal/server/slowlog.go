@@ -0,0 +1,47 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+// WithSlowThreshold logs every store call made by a handler that takes at
+// least d. A zero threshold logs all calls; negative values leave the slow
+// log disabled, which is the default.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(s *Server) {
+		if d >= 0 {
+			s.slowThreshold = d
+			s.slowLog = log.Default()
+		}
+	}
+}
+
+// timeStore runs a store call and reports it to the slow log when it
+// reaches the configured threshold.
+func timeStore[T any](s *Server, op string, call func() T) T {
+	if s.slowLog == nil {
+		return call()
+	}
+	start := time.Now()
+	out := call()
+	s.logSlow(op, time.Since(start))
+	return out
+}
+
+// timeStoreErr is timeStore for store calls that also return an error.
+func timeStoreErr[T any](s *Server, op string, call func() (T, error)) (T, error) {
+	if s.slowLog == nil {
+		return call()
+	}
+	start := time.Now()
+	out, err := call()
+	s.logSlow(op, time.Since(start))
+	return out, err
+}
+
+func (s *Server) logSlow(op string, elapsed time.Duration) {
+	if elapsed >= s.slowThreshold {
+		s.slowLog.Printf("slow store call: %s took %s (threshold %s)", op, elapsed, s.slowThreshold)
+	}
+}
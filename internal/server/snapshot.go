@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// clusterSnapshot is the compact state captured by /api/cluster/snapshot.
+// Pods and services are keyed namespace/name.
+type clusterSnapshot struct {
+	TakenAt     time.Time         `json:"t"`
+	Namespaces  []string          `json:"ns"`
+	Deployments map[string]int    `json:"dp"`
+	Pods        []string          `json:"po"`
+	Services    []string          `json:"svc"`
+	Nodes       map[string]string `json:"no"`
+}
+
+type snapshotResponse struct {
+	Token   string `json:"token"`
+	TakenAt string `json:"takenAt"`
+}
+
+type replicaChange struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+type statusChange struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type setChange struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+type clusterDiff struct {
+	Since       string    `json:"since"`
+	Namespaces  setChange `json:"namespaces"`
+	Deployments struct {
+		setChange
+		Scaled []replicaChange `json:"scaled"`
+	} `json:"deployments"`
+	Pods     setChange      `json:"pods"`
+	Services setChange      `json:"services"`
+	Nodes    []statusChange `json:"nodes"`
+}
+
+func (s *Server) handleClusterSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snap := s.takeSnapshot()
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		http.Error(w, "failed to encode snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, snapshotResponse{
+		Token:   base64.RawURLEncoding.EncodeToString(raw),
+		TakenAt: snap.TakenAt.Format(time.RFC3339),
+	}, http.StatusOK)
+}
+
+func (s *Server) handleClusterDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.URL.Query().Get("from")
+	if token == "" {
+		writeJSON(w, errorResponse{Error: "缺少 from 参数"}, http.StatusBadRequest)
+		return
+	}
+	var from clusterSnapshot
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(raw, &from)
+	}
+	if err != nil {
+		writeJSON(w, errorResponse{Error: "快照无效"}, http.StatusBadRequest)
+		return
+	}
+
+	to := s.takeSnapshot()
+	var out clusterDiff
+	out.Since = from.TakenAt.Format(time.RFC3339)
+	out.Namespaces = diffSets(from.Namespaces, to.Namespaces)
+	out.Pods = diffSets(from.Pods, to.Pods)
+	out.Services = diffSets(from.Services, to.Services)
+	out.Deployments.setChange = diffSets(sortedKeys(from.Deployments), sortedKeys(to.Deployments))
+	out.Deployments.Scaled = []replicaChange{}
+	for _, name := range sortedKeys(to.Deployments) {
+		before, ok := from.Deployments[name]
+		if ok && before != to.Deployments[name] {
+			out.Deployments.Scaled = append(out.Deployments.Scaled, replicaChange{Name: name, From: before, To: to.Deployments[name]})
+		}
+	}
+	out.Nodes = []statusChange{}
+	for _, name := range sortedKeys(to.Nodes) {
+		before, ok := from.Nodes[name]
+		if ok && before != to.Nodes[name] {
+			out.Nodes = append(out.Nodes, statusChange{Name: name, From: before, To: to.Nodes[name]})
+		}
+	}
+
+	writeJSON(w, out, http.StatusOK)
+}
+
+func (s *Server) takeSnapshot() clusterSnapshot {
+	now := s.now()
+	snap := clusterSnapshot{
+		TakenAt:     now.UTC(),
+		Namespaces:  []string{},
+		Deployments: make(map[string]int),
+		Pods:        []string{},
+		Services:    []string{},
+		Nodes:       make(map[string]string),
+	}
+	for _, ns := range s.namespaces.List(now) {
+		snap.Namespaces = append(snap.Namespaces, ns.Name)
+	}
+	for _, d := range s.deployments.List(now) {
+		snap.Deployments[d.Namespace+"/"+d.Name] = d.DesiredReplicas
+	}
+	for _, p := range visibleOnly(s, s.pods.List(now), podNamespace) {
+		snap.Pods = append(snap.Pods, p.Namespace+"/"+p.Name)
+	}
+	for _, svc := range visibleOnly(s, s.services.List(now), serviceNamespace) {
+		snap.Services = append(snap.Services, svc.Namespace+"/"+svc.Name)
+	}
+	for _, n := range s.nodes.List(now) {
+		snap.Nodes[n.Name] = n.Status
+	}
+	return snap
+}
+
+func diffSets(before, after []string) setChange {
+	out := setChange{Added: []string{}, Removed: []string{}}
+	seen := make(map[string]bool, len(before))
+	for _, item := range before {
+		seen[item] = true
+	}
+	for _, item := range after {
+		if !seen[item] {
+			out.Added = append(out.Added, item)
+		}
+		delete(seen, item)
+	}
+	for _, item := range before {
+		if seen[item] {
+			out.Removed = append(out.Removed, item)
+		}
+	}
+	sort.Strings(out.Added)
+	sort.Strings(out.Removed)
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
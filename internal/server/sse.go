@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSSEKeepalive = 15 * time.Second
+	defaultSSEPoll      = time.Second
+)
+
+// WithSSEKeepalive sets the interval between heartbeat comments on event
+// streams. Zero disables heartbeats.
+func WithSSEKeepalive(d time.Duration) Option {
+	return func(s *Server) {
+		s.sseKeepalive = d
+	}
+}
+
+type sseStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// openSSE prepares the response for server-sent events.
+func openSSE(w http.ResponseWriter) (*sseStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &sseStream{w: w, flusher: flusher}, true
+}
+
+func (st *sseStream) send(event, data string) {
+	if event != "" {
+		fmt.Fprintf(st.w, "event: %s\n", event)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(st.w, "data: %s\n", line)
+	}
+	fmt.Fprint(st.w, "\n")
+	st.flusher.Flush()
+}
+
+func (st *sseStream) comment(text string) {
+	fmt.Fprintf(st.w, ": %s\n\n", text)
+	st.flusher.Flush()
+}
+
+// runSSE polls the injected clock until ctx is done, emitting heartbeat
+// comments every keepalive interval and calling tick on each poll. Returning
+// false from tick ends the stream.
+func (s *Server) runSSE(ctx context.Context, st *sseStream, tick func(now time.Time) bool) {
+	ticker := time.NewTicker(s.ssePoll)
+	defer ticker.Stop()
+
+	lastBeat := s.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := s.now()
+			if s.sseKeepalive > 0 && now.Sub(lastBeat) >= s.sseKeepalive {
+				st.comment("keepalive")
+				lastBeat = now
+			}
+			if tick != nil && !tick(now) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// displayZone parses the optional tz query parameter used to render
+// human-facing timestamps. A nil location means no conversion.
+func displayZone(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// localizeTime renders an RFC3339 timestamp in loc. Values that do not parse
+// are returned as-is.
+func localizeTime(ts string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
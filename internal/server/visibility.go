@@ -0,0 +1,70 @@
+package server
+
+import (
+	"k8s_dashboard/internal/pod"
+	"k8s_dashboard/internal/scope"
+)
+
+// namespaceVisible reports whether resources in ns may be returned under the
+// configured WithVisibleNamespaces allow-list.
+func (s *Server) namespaceVisible(ns string) bool {
+	return scope.Allows(s.visibleNamespaces, ns)
+}
+
+// resolvePod picks the namespace a pod request addresses. An explicit
+// namespace outside the allow-list is reported as ErrNotFound. A bare name is
+// resolved among the visible namespaces only, so copies in hidden namespaces
+// neither answer the request nor make it ambiguous; the empty namespace it
+// returns without an allow-list leaves ambiguity to the store.
+func (s *Server) resolvePod(namespace, name string) (string, error) {
+	if namespace != "" {
+		if !s.namespaceVisible(namespace) {
+			return "", pod.ErrNotFound
+		}
+		return namespace, nil
+	}
+	if len(s.visibleNamespaces) == 0 {
+		return "", nil
+	}
+	return s.pods.Resolve(name, s.visibleNamespaces)
+}
+
+// visiblePod fetches the pod a bare name resolves to under resolvePod.
+func (s *Server) visiblePod(name string) (pod.Detail, error) {
+	namespace, err := s.resolvePod("", name)
+	if err != nil {
+		return pod.Detail{}, err
+	}
+	if namespace != "" {
+		return s.pods.GetNamespaced(namespace, name, s.now())
+	}
+	return s.pods.Get(name, s.now())
+}
+
+// visibleOnly drops items whose namespace is outside the allow-list.
+func visibleOnly[T any](s *Server, items []T, namespaceOf func(T) string) []T {
+	if len(s.visibleNamespaces) == 0 {
+		return items
+	}
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if s.namespaceVisible(namespaceOf(item)) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// visibleGroups applies visibleOnly to every group, dropping emptied groups.
+func visibleGroups[T any](s *Server, groups map[string][]T, namespaceOf func(T) string) map[string][]T {
+	if len(s.visibleNamespaces) == 0 {
+		return groups
+	}
+	out := make(map[string][]T, len(groups))
+	for key, items := range groups {
+		if visible := visibleOnly(s, items, namespaceOf); len(visible) > 0 {
+			out[key] = visible
+		}
+	}
+	return out
+}
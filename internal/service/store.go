@@ -3,15 +3,32 @@ package service
 import (
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"k8s_dashboard/internal/age"
+	"k8s_dashboard/internal/names"
+	"k8s_dashboard/internal/scope"
 )
 
 // ErrNotFound indicates the service does not exist in the mock store.
 var ErrNotFound = errors.New("service not found")
 
+// ErrExists indicates a service with the same name already exists.
+var ErrExists = errors.New("service already exists")
+
+// ErrInvalidType indicates an unsupported service type.
+var ErrInvalidType = errors.New("invalid service type")
+
+// ErrInvalidPort indicates a port outside 1-65535 or a spec without ports.
+var ErrInvalidPort = errors.New("invalid service port")
+
+// ErrInvalidSpec indicates a create request is missing required fields.
+var ErrInvalidSpec = errors.New("invalid service spec")
+
 // Port represents a single service port mapping.
 type Port struct {
 	Name       string `json:"name"`
@@ -21,6 +38,21 @@ type Port struct {
 	NodePort   *int   `json:"nodePort,omitempty"`
 }
 
+// ServiceEndpoint is a backend address and whether it passes readiness. Pod
+// names the related pod backing the address, when known.
+type ServiceEndpoint struct {
+	Address string `json:"address"`
+	Ready   bool   `json:"ready"`
+	Pod     string `json:"pod,omitempty"`
+}
+
+// EndpointGroups splits a service's endpoints into those receiving traffic
+// and those that are not.
+type EndpointGroups struct {
+	Ready    []ServiceEndpoint `json:"ready"`
+	NotReady []ServiceEndpoint `json:"notReady"`
+}
+
 // RelatedPod gives a lightweight view of pods selected by the service.
 type RelatedPod struct {
 	Name      string `json:"name"`
@@ -39,23 +71,44 @@ type Summary struct {
 	Ports       []Port   `json:"ports"`
 	Status      string   `json:"status"`
 	Age         string   `json:"age"`
+	AgeSeconds  int64    `json:"ageSeconds"`
 }
 
 // Detail extends Summary with selector metadata.
 type Detail struct {
 	Summary
 	Selector    map[string]string `json:"selector"`
-	Endpoints   []string          `json:"endpoints"`
+	Endpoints   []ServiceEndpoint `json:"endpoints"`
 	RelatedPods []RelatedPod      `json:"relatedPods"`
 	CreatedAt   string            `json:"createdAt"`
 	Description string            `json:"description"`
+
+	// CreatedAtLocal is CreatedAt rendered in a caller-requested timezone.
+	CreatedAtLocal string `json:"createdAtLocal,omitempty"`
+}
+
+// CreateSpec describes a service to add at runtime. A zero TargetPort
+// defaults to Port.
+type CreateSpec struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      string            `json:"type"`
+	Ports     []Port            `json:"ports"`
+	Selector  map[string]string `json:"selector"`
+}
+
+// PortFilter narrows the service list to services exposing a port. Zero
+// fields match every service; both must match when set.
+type PortFilter struct {
+	Port       int
+	TargetPort int
 }
 
 type record struct {
 	Summary
 	CreatedAt   time.Time
 	Selector    map[string]string
-	Endpoints   []string
+	Endpoints   []ServiceEndpoint
 	RelatedPods []RelatedPod
 	Description string
 }
@@ -82,7 +135,7 @@ func (s *Store) List(now time.Time) []Summary {
 
 	summaries := make([]Summary, 0, len(s.items))
 	for _, rec := range s.items {
-		summaries = append(summaries, decorateSummary(rec.Summary, rec.CreatedAt, now))
+		summaries = append(summaries, decorateSummary(rec, now))
 	}
 
 	sort.Slice(summaries, func(i, j int) bool {
@@ -95,35 +148,214 @@ func (s *Store) List(now time.Time) []Summary {
 	return summaries
 }
 
+// ListFiltered returns sorted service summaries matching the port filter.
+func (s *Store) ListFiltered(now time.Time, filter PortFilter) []Summary {
+	all := s.List(now)
+	out := make([]Summary, 0, len(all))
+	for _, item := range all {
+		if filter.matches(item.Ports) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (f PortFilter) matches(ports []Port) bool {
+	if f.Port == 0 && f.TargetPort == 0 {
+		return true
+	}
+	for _, p := range ports {
+		if (f.Port == 0 || p.Port == f.Port) && (f.TargetPort == 0 || p.TargetPort == f.TargetPort) {
+			return true
+		}
+	}
+	return false
+}
+
 // Get returns a service detail by name.
 func (s *Store) Get(name string, now time.Time) (Detail, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	if rec, ok := s.items[name]; ok {
-		summary := decorateSummary(rec.Summary, rec.CreatedAt, now)
-		detail := Detail{
-			Summary:     summary,
-			Selector:    copyMap(rec.Selector),
-			Endpoints:   append([]string{}, rec.Endpoints...),
-			RelatedPods: append([]RelatedPod{}, rec.RelatedPods...),
-			CreatedAt:   rec.CreatedAt.Format(time.RFC3339),
-			Description: rec.Description,
-		}
-		return detail, nil
+		return toDetail(rec, now), nil
 	}
 
 	return Detail{}, ErrNotFound
 }
 
-func decorateSummary(sum Summary, createdAt, now time.Time) Summary {
-	out := sum
-	out.Age = formatAge(now.Sub(createdAt))
-	out.ExternalIPs = append([]string{}, sum.ExternalIPs...)
-	out.Ports = copyPorts(sum.Ports)
+func toDetail(rec record, now time.Time) Detail {
+	return Detail{
+		Summary:     decorateSummary(rec, now),
+		Selector:    copyMap(rec.Selector),
+		Endpoints:   append([]ServiceEndpoint{}, rec.Endpoints...),
+		RelatedPods: append([]RelatedPod{}, rec.RelatedPods...),
+		CreatedAt:   rec.CreatedAt.Format(time.RFC3339),
+		Description: rec.Description,
+	}
+}
+
+// Create adds a service from spec with a ClusterIP derived from its
+// namespace and name. Namespace defaults to "default" and type to ClusterIP.
+func (s *Store) Create(spec CreateSpec, now time.Time) (Detail, error) {
+	if spec.Namespace == "" {
+		spec.Namespace = "default"
+	}
+	if spec.Type == "" {
+		spec.Type = "ClusterIP"
+	}
+	if strings.TrimSpace(spec.Name) == "" {
+		return Detail{}, ErrInvalidSpec
+	}
+	switch spec.Type {
+	case "ClusterIP", "NodePort", "LoadBalancer":
+	default:
+		return Detail{}, ErrInvalidType
+	}
+	if len(spec.Ports) == 0 {
+		return Detail{}, ErrInvalidPort
+	}
+	ports := copyPorts(spec.Ports)
+	for i := range ports {
+		if ports[i].TargetPort == 0 {
+			ports[i].TargetPort = ports[i].Port
+		}
+		if ports[i].Protocol == "" {
+			ports[i].Protocol = "TCP"
+		}
+		if !validPort(ports[i].Port) || !validPort(ports[i].TargetPort) ||
+			(ports[i].NodePort != nil && !validPort(*ports[i].NodePort)) {
+			return Detail{}, ErrInvalidPort
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.items[spec.Name]; exists {
+		return Detail{}, ErrExists
+	}
+
+	rec := record{
+		Summary: Summary{
+			Name:        spec.Name,
+			Namespace:   spec.Namespace,
+			Type:        spec.Type,
+			ClusterIP:   s.allocateClusterIP(spec.Namespace, spec.Name),
+			ExternalIPs: []string{},
+			Ports:       ports,
+		},
+		CreatedAt:   now,
+		Selector:    copyMap(spec.Selector),
+		Endpoints:   []ServiceEndpoint{},
+		RelatedPods: []RelatedPod{},
+	}
+	s.items[rec.Name] = rec
+	return toDetail(rec, now), nil
+}
+
+// Delete removes the named service, reporting whether it existed.
+func (s *Store) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[name]; !ok {
+		return false
+	}
+	delete(s.items, name)
+	return true
+}
+
+// allocateClusterIP hashes namespace/name into 10.96.0.0/16, probing forward
+// past addresses already in use. Callers must hold the lock.
+func (s *Store) allocateClusterIP(namespace, name string) string {
+	used := make(map[string]bool, len(s.items))
+	for _, rec := range s.items {
+		used[rec.ClusterIP] = true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace + "/" + name))
+	// Skip .0 and .255 host octets so every address looks assignable.
+	const hosts = 256 * 254
+	n := int(h.Sum32() % hosts)
+	for {
+		ip := fmt.Sprintf("10.96.%d.%d", n/254, n%254+1)
+		if !used[ip] {
+			return ip
+		}
+		n = (n + 1) % hosts
+	}
+}
+
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+func decorateSummary(rec record, now time.Time) Summary {
+	out := rec.Summary
+	out.Age = age.Format(now.Sub(rec.CreatedAt))
+	out.AgeSeconds = age.Seconds(now.Sub(rec.CreatedAt))
+	out.ExternalIPs = append([]string{}, rec.ExternalIPs...)
+	out.Ports = copyPorts(rec.Ports)
+	out.Status = endpointStatus(rec.Endpoints)
 	return out
 }
 
+// endpointStatus is Active when at least one endpoint is ready, otherwise
+// Degraded.
+func endpointStatus(endpoints []ServiceEndpoint) string {
+	for _, ep := range endpoints {
+		if ep.Ready {
+			return "Active"
+		}
+	}
+	return "Degraded"
+}
+
+// Endpoints returns the endpoints of the named service.
+func (s *Store) Endpoints(name string) ([]ServiceEndpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.items[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]ServiceEndpoint{}, rec.Endpoints...), nil
+}
+
+// EndpointsByReadiness groups the named service's endpoints. An endpoint
+// backed by a related pod counts as ready only while it passes readiness and
+// the pod is Running.
+func (s *Store) EndpointsByReadiness(name string) (EndpointGroups, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.items[name]
+	if !ok {
+		return EndpointGroups{}, ErrNotFound
+	}
+
+	podStatus := make(map[string]string, len(rec.RelatedPods))
+	for _, p := range rec.RelatedPods {
+		podStatus[p.Name] = p.Status
+	}
+
+	out := EndpointGroups{Ready: []ServiceEndpoint{}, NotReady: []ServiceEndpoint{}}
+	for _, ep := range rec.Endpoints {
+		ready := ep.Ready
+		if status, ok := podStatus[ep.Pod]; ok && status != "Running" {
+			ready = false
+		}
+		if ready {
+			out.Ready = append(out.Ready, ep)
+		} else {
+			out.NotReady = append(out.NotReady, ep)
+		}
+	}
+	return out, nil
+}
+
 func copyPorts(ports []Port) []Port {
 	out := make([]Port, len(ports))
 	for i, p := range ports {
@@ -148,28 +380,6 @@ func copyMap(in map[string]string) map[string]string {
 	return out
 }
 
-func formatAge(d time.Duration) string {
-	if d < 0 {
-		d = 0
-	}
-
-	days := d / (24 * time.Hour)
-	d -= days * 24 * time.Hour
-	hours := d / time.Hour
-	d -= hours * time.Hour
-	minutes := d / time.Minute
-
-	switch {
-	case days > 0:
-		return fmt.Sprintf("%dd%dh", days, hours)
-	case hours > 0:
-		return fmt.Sprintf("%dh%dm", hours, minutes)
-	default:
-		secs := int(d/time.Second) % 60
-		return fmt.Sprintf("%dm%ds", minutes, secs)
-	}
-}
-
 func intPtr(v int) *int {
 	return &v
 }
@@ -188,14 +398,16 @@ func defaultSeed(now time.Time) []record {
 					{Name: "http", Protocol: "TCP", Port: 80, TargetPort: 8080},
 					{Name: "metrics", Protocol: "TCP", Port: 9000, TargetPort: 9000},
 				},
-				Status: "Active",
 			},
 			CreatedAt: base,
 			Selector: map[string]string{
 				"app":  "frontend",
 				"tier": "web",
 			},
-			Endpoints: []string{"10.0.0.11:8080", "10.0.0.12:8080"},
+			Endpoints: []ServiceEndpoint{
+				{Address: "10.0.0.11:8080", Ready: true, Pod: "frontend-7d8fdc9f7c-abc12"},
+				{Address: "10.0.0.12:8080", Ready: true, Pod: "frontend-7d8fdc9f7c-def34"},
+			},
 			RelatedPods: []RelatedPod{
 				{Name: "frontend-7d8fdc9f7c-abc12", Namespace: "default", Status: "Running", Node: "node-2"},
 				{Name: "frontend-7d8fdc9f7c-def34", Namespace: "default", Status: "Running", Node: "node-3"},
@@ -213,14 +425,17 @@ func defaultSeed(now time.Time) []record {
 					{Name: "http", Protocol: "TCP", Port: 80, TargetPort: 8080, NodePort: intPtr(30080)},
 					{Name: "https", Protocol: "TCP", Port: 443, TargetPort: 8443, NodePort: intPtr(30443)},
 				},
-				Status: "Pending",
 			},
 			CreatedAt: base.Add(12 * time.Hour),
 			Selector: map[string]string{
 				"app":       "edge-gateway",
 				"component": "ingress",
 			},
-			Endpoints: []string{"34.87.18.4:80", "34.87.18.4:443"},
+			// Neither gateway backend passes readiness yet.
+			Endpoints: []ServiceEndpoint{
+				{Address: "10.0.1.21:8080", Ready: false, Pod: "edge-gateway-7d8fdc9f7c-9012a"},
+				{Address: "10.0.1.22:8080", Ready: false, Pod: "edge-gateway-7d8fdc9f7c-9012b"},
+			},
 			RelatedPods: []RelatedPod{
 				{Name: "edge-gateway-7d8fdc9f7c-9012a", Namespace: "prod", Status: "Running", Node: "node-1"},
 				{Name: "edge-gateway-7d8fdc9f7c-9012b", Namespace: "prod", Status: "Pending", Node: ""},
@@ -236,13 +451,14 @@ func defaultSeed(now time.Time) []record {
 				Ports: []Port{
 					{Name: "http", Protocol: "TCP", Port: 8080, TargetPort: 8080, NodePort: intPtr(32045)},
 				},
-				Status: "Active",
 			},
 			CreatedAt: base.Add(30 * time.Hour),
 			Selector: map[string]string{
 				"job": "metrics",
 			},
-			Endpoints: []string{"10.0.12.5:8080"},
+			Endpoints: []ServiceEndpoint{
+				{Address: "10.0.12.5:8080", Ready: true, Pod: "batch-metrics-7c5d6f6b4d-xk9p2"},
+			},
 			RelatedPods: []RelatedPod{
 				{Name: "batch-metrics-7c5d6f6b4d-xk9p2", Namespace: "batch", Status: "Running", Node: "node-2"},
 			},
@@ -250,3 +466,19 @@ func defaultSeed(now time.Time) []record {
 		},
 	}
 }
+
+// NamesWithPrefix returns up to limit sorted, distinct service names starting
+// with prefix, ignoring case. Namespaces, when non-empty, restricts the
+// search to those namespaces.
+func (s *Store) NamesWithPrefix(prefix string, namespaces []string, limit int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates := make([]string, 0, len(s.items))
+	for _, rec := range s.items {
+		if scope.Allows(namespaces, rec.Namespace) {
+			candidates = append(candidates, rec.Name)
+		}
+	}
+	return names.WithPrefix(candidates, prefix, limit)
+}
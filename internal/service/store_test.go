@@ -47,7 +47,7 @@ func TestStoreGetDetail(t *testing.T) {
 		t.Fatalf("expected edge-gateway, got %s", detail.Name)
 	}
 
-	if detail.Status != "Pending" {
+	if detail.Status != "Degraded" {
 		t.Fatalf("unexpected status %s", detail.Status)
 	}
 
@@ -71,3 +71,128 @@ func TestStoreGetDetail(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestStoreListFilteredByPort(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	https := store.ListFiltered(now, PortFilter{Port: 443})
+	if len(https) != 1 || https[0].Name != "edge-gateway" {
+		t.Fatalf("expected only edge-gateway on 443, got %+v", https)
+	}
+
+	target := store.ListFiltered(now, PortFilter{TargetPort: 8080})
+	if len(target) != 3 {
+		t.Fatalf("expected 3 services targeting 8080, got %d", len(target))
+	}
+
+	if none := store.ListFiltered(now, PortFilter{Port: 443, TargetPort: 8080}); len(none) != 0 {
+		t.Fatalf("expected no services, got %+v", none)
+	}
+}
+
+func TestCreateAndDelete(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	spec := CreateSpec{
+		Name:     "api",
+		Type:     "NodePort",
+		Ports:    []Port{{Name: "http", Port: 80, TargetPort: 8080}},
+		Selector: map[string]string{"app": "api"},
+	}
+	detail, err := store.Create(spec, now)
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+	if detail.Namespace != "default" || detail.Status != "Degraded" || detail.Ports[0].Protocol != "TCP" {
+		t.Fatalf("unexpected created service %+v", detail)
+	}
+	again := NewStore(now)
+	if other, _ := again.Create(spec, now); other.ClusterIP != detail.ClusterIP {
+		t.Fatalf("expected deterministic ClusterIP, got %s and %s", detail.ClusterIP, other.ClusterIP)
+	}
+
+	if _, err := store.Create(spec, now); err != ErrExists {
+		t.Fatalf("expected ErrExists, got %v", err)
+	}
+	if _, err := store.Create(CreateSpec{Name: "x", Type: "ExternalName", Ports: spec.Ports}, now); err != ErrInvalidType {
+		t.Fatalf("expected ErrInvalidType, got %v", err)
+	}
+	if _, err := store.Create(CreateSpec{Name: "x", Ports: []Port{{Port: 70000}}}, now); err != ErrInvalidPort {
+		t.Fatalf("expected ErrInvalidPort, got %v", err)
+	}
+
+	if !store.Delete("api") {
+		t.Fatalf("expected delete to succeed")
+	}
+	if store.Delete("api") {
+		t.Fatalf("expected second delete to report missing")
+	}
+}
+
+func TestEndpointStatus(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	want := map[string]string{
+		"frontend":      "Active",
+		"edge-gateway":  "Degraded",
+		"batch-metrics": "Active",
+	}
+	for _, svc := range store.List(now) {
+		if svc.Status != want[svc.Name] {
+			t.Fatalf("expected %s to be %s, got %s", svc.Name, want[svc.Name], svc.Status)
+		}
+	}
+
+	cases := []struct {
+		endpoints []ServiceEndpoint
+		want      string
+	}{
+		{nil, "Degraded"},
+		{[]ServiceEndpoint{{Address: "10.0.0.1:80"}}, "Degraded"},
+		{[]ServiceEndpoint{{Address: "10.0.0.1:80"}, {Address: "10.0.0.2:80", Ready: true}}, "Active"},
+	}
+	for _, tc := range cases {
+		if got := endpointStatus(tc.endpoints); got != tc.want {
+			t.Fatalf("endpointStatus(%v) = %s, want %s", tc.endpoints, got, tc.want)
+		}
+	}
+}
+
+func TestEndpointsByReadiness(t *testing.T) {
+	now := time.Date(2024, 7, 12, 12, 0, 0, 0, time.UTC)
+	store := NewStore(now)
+
+	groups, err := store.EndpointsByReadiness("edge-gateway")
+	if err != nil {
+		t.Fatalf("group endpoints: %v", err)
+	}
+	found := false
+	for _, ep := range groups.NotReady {
+		if ep.Pod == "edge-gateway-7d8fdc9f7c-9012b" {
+			found = true
+		}
+	}
+	if !found || len(groups.Ready) != 0 {
+		t.Fatalf("expected pending pod endpoint in notReady, got %+v", groups)
+	}
+
+	// A ready endpoint is still withheld while its pod is not Running.
+	rec := store.items["frontend"]
+	rec.RelatedPods = append([]RelatedPod{}, rec.RelatedPods...)
+	rec.RelatedPods[0].Status = "Pending"
+	store.items["frontend"] = rec
+	groups, err = store.EndpointsByReadiness("frontend")
+	if err != nil {
+		t.Fatalf("group endpoints: %v", err)
+	}
+	if len(groups.Ready) != 1 || len(groups.NotReady) != 1 || groups.NotReady[0].Pod != rec.RelatedPods[0].Name {
+		t.Fatalf("expected pending frontend pod endpoint in notReady, got %+v", groups)
+	}
+
+	if _, err := store.EndpointsByReadiness("missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}